@@ -0,0 +1,209 @@
+// Command content imports posts from other blogs and exports posts as
+// Markdown, using the database settings of the server.
+//
+//	content import -as admin [-format markdown|wxr] [-dry-run] FILE
+//	content export [-user USERNAME] [-o posts.zip]
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/demo/demo-gin/internal/config"
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/i18n"
+	"github.com/demo/demo-gin/internal/transfer"
+	"github.com/demo/demo-gin/pkg/database"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = runImport(ctx, os.Args[2:])
+	case "export":
+		err = runExport(ctx, os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage:")
+	fmt.Fprintln(os.Stderr, "  content import -as USERNAME [-format markdown|wxr] [-dry-run] FILE")
+	fmt.Fprintln(os.Stderr, "  content export [-user USERNAME] [-o FILE]")
+	os.Exit(2)
+}
+
+// runImport imports a Markdown zip or WXR file and prints one line per
+// entry. It fails when any entry could not be imported.
+func runImport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	as := flags.String("as", "", "username that owns posts by unknown authors")
+	format := flags.String("format", "", "markdown or wxr; guessed from the file extension when empty")
+	dryRun := flags.Bool("dry-run", false, "report without importing")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 || *as == "" {
+		usage()
+	}
+	path := flags.Arg(0)
+
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".zip":
+			*format = transfer.FormatMarkdown
+		case ".xml", ".wxr":
+			*format = transfer.FormatWXR
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var documents []transfer.Document
+	switch *format {
+	case transfer.FormatMarkdown:
+		documents, err = transfer.ParseMarkdownArchive(data)
+	case transfer.FormatWXR:
+		documents, err = transfer.ParseWXR(bytes.NewReader(data))
+	default:
+		return errors.New("unknown format, set -format to markdown or wxr")
+	}
+	if err != nil {
+		return err
+	}
+
+	cfg, conn, err := connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	owner, err := db.New(conn).GetUserByUsername(ctx, *as)
+	if err != nil {
+		return fmt.Errorf("find user %q: %w", *as, err)
+	}
+
+	importer := transfer.NewImporter(conn, i18n.New(cfg.I18n.DefaultLocale, cfg.I18n.Locales))
+	report, err := importer.Import(ctx, documents, transfer.Options{
+		Format:     *format,
+		DryRun:     *dryRun,
+		ImportedBy: owner.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, result := range report.Results {
+		line := fmt.Sprintf("%-8s %s", result.Action, result.SourceID)
+		if result.PostID != 0 {
+			line += fmt.Sprintf(" -> post %d", result.PostID)
+		}
+		if result.Error != "" {
+			line += ": " + result.Error
+		}
+		fmt.Println(line)
+		for _, warning := range result.Warnings {
+			fmt.Println("         warning: " + warning)
+		}
+	}
+
+	verb := "created"
+	if report.DryRun {
+		verb = "to create"
+	}
+	fmt.Printf("%d %s, %d already imported, %d failed\n", report.Created, verb, report.Exists, report.Failed)
+
+	if report.Failed > 0 {
+		return fmt.Errorf("%d entries could not be imported", report.Failed)
+	}
+	return nil
+}
+
+// runExport writes the posts of one user, or of everyone, as a zip of
+// Markdown files.
+func runExport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	username := flags.String("user", "", "only export posts of this user")
+	output := flags.String("o", "posts.zip", "output file")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		usage()
+	}
+
+	_, conn, err := connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	queries := db.New(conn)
+
+	var userID sql.NullInt32
+	if *username != "" {
+		user, err := queries.GetUserByUsername(ctx, *username)
+		if err != nil {
+			return fmt.Errorf("find user %q: %w", *username, err)
+		}
+		userID = sql.NullInt32{Int32: user.ID, Valid: true}
+	}
+
+	documents, err := transfer.Export(ctx, queries, userID)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := transfer.WriteMarkdownArchive(file, documents); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("exported %d posts to %s\n", len(documents), *output)
+	return nil
+}
+
+// connect loads the server configuration and opens the database.
+func connect() (*config.Config, *sql.DB, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conn, err := database.Connect(cfg.Database)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, conn, nil
+}
\ No newline at end of file
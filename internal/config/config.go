@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -9,6 +11,16 @@ import (
 type Config struct {
 	Database DatabaseConfig
 	Server   ServerConfig
+	Storage  StorageConfig
+	Trash    TrashConfig
+	Privacy  PrivacyConfig
+	Views    ViewsConfig
+	I18n     I18nConfig
+	Preview  PreviewConfig
+	Mail     MailConfig
+	Digest   DigestConfig
+	Events   EventsConfig
+	Webhooks WebhooksConfig
 }
 
 type DatabaseConfig struct {
@@ -27,6 +39,106 @@ type ServerConfig struct {
 	APIPrefix  string
 }
 
+type StorageConfig struct {
+	Driver      string
+	LocalDir    string
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
+}
+
+// TrashConfig controls how long deleted posts and users can be restored
+// before the purge worker removes them for good.
+type TrashConfig struct {
+	RetentionDays int
+	PurgeInterval time.Duration
+}
+
+// Retention returns the retention period as a duration.
+func (c *TrashConfig) Retention() time.Duration {
+	return time.Duration(c.RetentionDays) * 24 * time.Hour
+}
+
+// PrivacyConfig controls personal data exports and account erasure.
+// ErasureMode is "anonymize", which keeps an erased user's posts under an
+// anonymous account, or "delete", which removes them with the account.
+type PrivacyConfig struct {
+	ExportTTL        time.Duration
+	ErasureGraceDays int
+	ErasureMode      string
+	WorkerInterval   time.Duration
+}
+
+// ErasureGrace returns the erasure grace period as a duration.
+func (c *PrivacyConfig) ErasureGrace() time.Duration {
+	return time.Duration(c.ErasureGraceDays) * 24 * time.Hour
+}
+
+// ViewsConfig controls view counting and the trending ranking. A visitor
+// is counted once per post per DedupWindow, and a view's weight in the
+// trending score halves every TrendingHalfLife.
+type ViewsConfig struct {
+	DedupWindow      time.Duration
+	FlushInterval    time.Duration
+	TrendingWindow   time.Duration
+	TrendingHalfLife time.Duration
+	TrendingInterval time.Duration
+}
+
+// I18nConfig lists the locales posts can be written and translated in.
+// Posts created without a locale are in DefaultLocale, which is also served
+// when none of the locales a client asks for is available.
+type I18nConfig struct {
+	DefaultLocale string
+	Locales       []string
+}
+
+// PreviewConfig configures signed draft preview links. Links are disabled
+// without a secret.
+type PreviewConfig struct {
+	Secret string
+	MaxTTL time.Duration
+}
+
+// MailConfig selects how emails are sent: "log", which only logs them, or
+// "smtp".
+type MailConfig struct {
+	Driver       string
+	From         string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+}
+
+// DigestConfig controls how often the digest worker looks for users whose
+// notification email digest is due.
+type DigestConfig struct {
+	Interval time.Duration
+}
+
+// EventsConfig controls the event stream. Heartbeat is how long a stream
+// may stay silent, and events can be replayed for Retention after they
+// were published.
+type EventsConfig struct {
+	Heartbeat     time.Duration
+	Retention     time.Duration
+	PruneInterval time.Duration
+}
+
+// WebhooksConfig controls webhook deliveries. A delivery is attempted up
+// to MaxAttempts times, and a webhook is disabled once DisableAfter
+// attempts in a row have failed.
+type WebhooksConfig struct {
+	Timeout      time.Duration
+	MaxAttempts  int
+	DisableAfter int
+	Interval     time.Duration
+}
+
 func Load() (*Config, error) {
 	viper.SetConfigFile(".env")
 	viper.AutomaticEnv()
@@ -43,6 +155,34 @@ func Load() (*Config, error) {
 	viper.SetDefault("API_PREFIX", "/api")
 	viper.SetDefault("DB_PORT", 5432)
 	viper.SetDefault("DB_SSLMODE", "disable")
+	viper.SetDefault("STORAGE_DRIVER", "local")
+	viper.SetDefault("STORAGE_LOCAL_DIR", "uploads")
+	viper.SetDefault("S3_REGION", "us-east-1")
+	viper.SetDefault("S3_USE_SSL", true)
+	viper.SetDefault("TRASH_RETENTION_DAYS", 30)
+	viper.SetDefault("TRASH_PURGE_INTERVAL", time.Hour)
+	viper.SetDefault("PRIVACY_EXPORT_TTL", 7*24*time.Hour)
+	viper.SetDefault("PRIVACY_ERASURE_GRACE_DAYS", 30)
+	viper.SetDefault("PRIVACY_ERASURE_MODE", "anonymize")
+	viper.SetDefault("PRIVACY_WORKER_INTERVAL", time.Minute)
+	viper.SetDefault("VIEWS_DEDUP_WINDOW", 30*time.Minute)
+	viper.SetDefault("VIEWS_FLUSH_INTERVAL", 10*time.Second)
+	viper.SetDefault("VIEWS_TRENDING_WINDOW", 7*24*time.Hour)
+	viper.SetDefault("VIEWS_TRENDING_HALF_LIFE", 24*time.Hour)
+	viper.SetDefault("VIEWS_TRENDING_INTERVAL", 10*time.Minute)
+	viper.SetDefault("I18N_DEFAULT_LOCALE", "zh")
+	viper.SetDefault("I18N_LOCALES", "zh,en")
+	viper.SetDefault("PREVIEW_MAX_TTL", 7*24*time.Hour)
+	viper.SetDefault("MAIL_DRIVER", "log")
+	viper.SetDefault("SMTP_PORT", 587)
+	viper.SetDefault("DIGEST_INTERVAL", 15*time.Minute)
+	viper.SetDefault("EVENTS_HEARTBEAT", 25*time.Second)
+	viper.SetDefault("EVENTS_RETENTION", 24*time.Hour)
+	viper.SetDefault("EVENTS_PRUNE_INTERVAL", 10*time.Minute)
+	viper.SetDefault("WEBHOOKS_TIMEOUT", 10*time.Second)
+	viper.SetDefault("WEBHOOKS_MAX_ATTEMPTS", 8)
+	viper.SetDefault("WEBHOOKS_DISABLE_AFTER", 20)
+	viper.SetDefault("WEBHOOKS_INTERVAL", 10*time.Second)
 
 	config := &Config{
 		Database: DatabaseConfig{
@@ -59,6 +199,63 @@ func Load() (*Config, error) {
 			APIVersion: viper.GetString("API_VERSION"),
 			APIPrefix:  viper.GetString("API_PREFIX"),
 		},
+		Storage: StorageConfig{
+			Driver:      viper.GetString("STORAGE_DRIVER"),
+			LocalDir:    viper.GetString("STORAGE_LOCAL_DIR"),
+			S3Endpoint:  viper.GetString("S3_ENDPOINT"),
+			S3Region:    viper.GetString("S3_REGION"),
+			S3Bucket:    viper.GetString("S3_BUCKET"),
+			S3AccessKey: viper.GetString("S3_ACCESS_KEY"),
+			S3SecretKey: viper.GetString("S3_SECRET_KEY"),
+			S3UseSSL:    viper.GetBool("S3_USE_SSL"),
+		},
+		Trash: TrashConfig{
+			RetentionDays: viper.GetInt("TRASH_RETENTION_DAYS"),
+			PurgeInterval: viper.GetDuration("TRASH_PURGE_INTERVAL"),
+		},
+		Privacy: PrivacyConfig{
+			ExportTTL:        viper.GetDuration("PRIVACY_EXPORT_TTL"),
+			ErasureGraceDays: viper.GetInt("PRIVACY_ERASURE_GRACE_DAYS"),
+			ErasureMode:      viper.GetString("PRIVACY_ERASURE_MODE"),
+			WorkerInterval:   viper.GetDuration("PRIVACY_WORKER_INTERVAL"),
+		},
+		Views: ViewsConfig{
+			DedupWindow:      viper.GetDuration("VIEWS_DEDUP_WINDOW"),
+			FlushInterval:    viper.GetDuration("VIEWS_FLUSH_INTERVAL"),
+			TrendingWindow:   viper.GetDuration("VIEWS_TRENDING_WINDOW"),
+			TrendingHalfLife: viper.GetDuration("VIEWS_TRENDING_HALF_LIFE"),
+			TrendingInterval: viper.GetDuration("VIEWS_TRENDING_INTERVAL"),
+		},
+		I18n: I18nConfig{
+			DefaultLocale: viper.GetString("I18N_DEFAULT_LOCALE"),
+			Locales:       strings.Split(viper.GetString("I18N_LOCALES"), ","),
+		},
+		Preview: PreviewConfig{
+			Secret: viper.GetString("PREVIEW_SECRET"),
+			MaxTTL: viper.GetDuration("PREVIEW_MAX_TTL"),
+		},
+		Mail: MailConfig{
+			Driver:       viper.GetString("MAIL_DRIVER"),
+			From:         viper.GetString("MAIL_FROM"),
+			SMTPHost:     viper.GetString("SMTP_HOST"),
+			SMTPPort:     viper.GetInt("SMTP_PORT"),
+			SMTPUsername: viper.GetString("SMTP_USERNAME"),
+			SMTPPassword: viper.GetString("SMTP_PASSWORD"),
+		},
+		Digest: DigestConfig{
+			Interval: viper.GetDuration("DIGEST_INTERVAL"),
+		},
+		Events: EventsConfig{
+			Heartbeat:     viper.GetDuration("EVENTS_HEARTBEAT"),
+			Retention:     viper.GetDuration("EVENTS_RETENTION"),
+			PruneInterval: viper.GetDuration("EVENTS_PRUNE_INTERVAL"),
+		},
+		Webhooks: WebhooksConfig{
+			Timeout:      viper.GetDuration("WEBHOOKS_TIMEOUT"),
+			MaxAttempts:  viper.GetInt("WEBHOOKS_MAX_ATTEMPTS"),
+			DisableAfter: viper.GetInt("WEBHOOKS_DISABLE_AFTER"),
+			Interval:     viper.GetDuration("WEBHOOKS_INTERVAL"),
+		},
 	}
 
 	return config, nil
@@ -67,4 +264,4 @@ func Load() (*Config, error) {
 func (c *DatabaseConfig) DSN() string {
 	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		c.Host, c.Port, c.User, c.Password, c.Name, c.SSLMode)
-}
+}
\ No newline at end of file
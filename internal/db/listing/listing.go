@@ -0,0 +1,187 @@
+// Package listing runs the list queries with client-defined filters and sort
+// orders. sqlc cannot generate them because their WHERE and ORDER BY clauses
+// are compiled at run time by package listquery, which only emits columns of
+// an allow-list and passes every value as an argument. Rows are scanned into
+// the types of the generated package.
+package listing
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/listquery"
+)
+
+// Queries runs list queries on a connection or transaction.
+type Queries struct {
+	db db.DBTX
+}
+
+func New(conn db.DBTX) *Queries {
+	return &Queries{db: conn}
+}
+
+// filteredPostsFrom selects the posts the viewer ($1) may list: published
+// posts of active authors, and the posts they are an author of in any
+// status. Posts by authors who blocked the viewer and authors the viewer
+// muted are left out.
+const filteredPostsFrom = `
+FROM posts p
+JOIN users u ON p.user_id = u.id
+WHERE p.deleted_at IS NULL AND u.deleted_at IS NULL
+    AND ((p.status = 'published' AND u.is_active = true) OR EXISTS (
+        SELECT 1 FROM post_authors pa
+        WHERE pa.post_id = p.id AND pa.user_id = $1
+    ))
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks b
+        WHERE b.blocker_id = p.user_id AND b.blocked_id = $1
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM user_mutes m
+        WHERE m.muter_id = $1 AND m.muted_id = p.user_id
+    )`
+
+const listPosts = `SELECT p.id, p.user_id, p.title, p.content, p.status, p.published_at, p.created_at, p.updated_at, p.comments_closed, p.like_count, p.bookmark_count, p.version, p.content_format, p.content_html, p.slug, p.deleted_at, p.locale, u.username, u.email,
+    (SELECT COUNT(*) FROM comments c
+        JOIN users cu ON c.user_id = cu.id
+        WHERE c.post_id = p.id AND c.deleted_at IS NULL AND cu.deleted_at IS NULL) AS comment_count` + filteredPostsFrom
+
+type ListPostsParams struct {
+	ViewerID sql.NullInt32   `json:"viewer_id"`
+	Filter   listquery.Query `json:"-"`
+	Limit    int32           `json:"limit"`
+	Offset   int32           `json:"offset"`
+}
+
+func (q *Queries) ListPosts(ctx context.Context, arg ListPostsParams) ([]db.ListPostsRow, error) {
+	where, args := arg.Filter.Where([]interface{}{arg.ViewerID})
+	query := listPosts + where + "\nORDER BY " + arg.Filter.OrderBy() + limitOffset(len(args))
+	rows, err := q.db.QueryContext(ctx, query, append(args, arg.Limit, arg.Offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []db.ListPostsRow{}
+	for rows.Next() {
+		var i db.ListPostsRow
+		if err := rows.Scan(
+			&i.Post.ID,
+			&i.Post.UserID,
+			&i.Post.Title,
+			&i.Post.Content,
+			&i.Post.Status,
+			&i.Post.PublishedAt,
+			&i.Post.CreatedAt,
+			&i.Post.UpdatedAt,
+			&i.Post.CommentsClosed,
+			&i.Post.LikeCount,
+			&i.Post.BookmarkCount,
+			&i.Post.Version,
+			&i.Post.ContentFormat,
+			&i.Post.ContentHtml,
+			&i.Post.Slug,
+			&i.Post.DeletedAt,
+			&i.Post.Locale,
+			&i.Username,
+			&i.Email,
+			&i.CommentCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (q *Queries) CountPosts(ctx context.Context, viewerID sql.NullInt32, filter listquery.Query) (int64, error) {
+	where, args := filter.Where([]interface{}{viewerID})
+	row := q.db.QueryRowContext(ctx, "SELECT COUNT(*)"+filteredPostsFrom+where, args...)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+// filteredUsersFrom selects the active users the viewer ($1) may list,
+// leaving out users who blocked the viewer.
+const filteredUsersFrom = `
+FROM users
+WHERE is_active = true AND deleted_at IS NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks b
+        WHERE b.blocker_id = users.id AND b.blocked_id = $1
+    )`
+
+const listUsers = `SELECT id, email, username, password_hash, full_name, is_active, created_at, updated_at, version, bio, website, location, avatar_key, role, deleted_at, erasure_scheduled_at, erased_at` + filteredUsersFrom
+
+type ListUsersParams struct {
+	ViewerID sql.NullInt32   `json:"viewer_id"`
+	Filter   listquery.Query `json:"-"`
+	Limit    int32           `json:"limit"`
+	Offset   int32           `json:"offset"`
+}
+
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]db.User, error) {
+	where, args := arg.Filter.Where([]interface{}{arg.ViewerID})
+	query := listUsers + where + "\nORDER BY " + arg.Filter.OrderBy() + limitOffset(len(args))
+	rows, err := q.db.QueryContext(ctx, query, append(args, arg.Limit, arg.Offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []db.User{}
+	for rows.Next() {
+		var i db.User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.PasswordHash,
+			&i.FullName,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.Bio,
+			&i.Website,
+			&i.Location,
+			&i.AvatarKey,
+			&i.Role,
+			&i.DeletedAt,
+			&i.ErasureScheduledAt,
+			&i.ErasedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (q *Queries) CountUsers(ctx context.Context, viewerID sql.NullInt32, filter listquery.Query) (int64, error) {
+	where, args := filter.Where([]interface{}{viewerID})
+	row := q.db.QueryRowContext(ctx, "SELECT COUNT(*)"+filteredUsersFrom+where, args...)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+// limitOffset returns the LIMIT and OFFSET clause of a statement with n
+// arguments before them.
+func limitOffset(n int) string {
+	return "\nLIMIT $" + strconv.Itoa(n+1) + " OFFSET $" + strconv.Itoa(n+2)
+}
\ No newline at end of file
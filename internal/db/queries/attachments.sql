@@ -0,0 +1,37 @@
+-- name: GetAttachment :one
+-- Attachments of trashed posts are not served.
+SELECT * FROM attachments a
+WHERE a.id = $1
+    AND NOT EXISTS (
+        SELECT 1 FROM posts p
+        WHERE p.id = a.post_id AND p.deleted_at IS NOT NULL
+    )
+LIMIT 1;
+
+-- name: ListPostAttachments :many
+SELECT * FROM attachments
+WHERE post_id = $1
+ORDER BY created_at, id;
+
+-- name: ListUserAttachments :many
+SELECT * FROM attachments
+WHERE user_id = $1 AND post_id IS NOT NULL
+ORDER BY id;
+
+-- name: ListOrphanedAttachments :many
+SELECT * FROM attachments
+WHERE post_id IS NULL
+ORDER BY id
+LIMIT $1;
+
+-- name: CreateAttachment :one
+INSERT INTO attachments (
+    post_id, user_id, storage_key, thumbnail_key, filename, content_type, size_bytes, width, height
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: DeleteAttachment :exec
+DELETE FROM attachments
+WHERE id = $1;
\ No newline at end of file
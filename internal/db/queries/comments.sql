@@ -0,0 +1,100 @@
+-- name: GetComment :one
+SELECT * FROM comments
+WHERE id = $1 LIMIT 1;
+
+-- name: ListUserComments :many
+SELECT * FROM comments
+WHERE user_id = $1
+ORDER BY id;
+
+-- name: ListComments :many
+-- Comments by users who blocked the viewer are left out.
+SELECT sqlc.embed(c), u.username, u.deleted_at AS author_deleted_at
+FROM comments c
+JOIN users u ON c.user_id = u.id
+WHERE c.post_id = sqlc.arg('post_id')
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks b
+        WHERE b.blocker_id = c.user_id AND b.blocked_id = sqlc.narg('viewer_id')
+    )
+ORDER BY c.created_at, c.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountComments :one
+SELECT COUNT(*) FROM comments c
+WHERE c.post_id = sqlc.arg('post_id')
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks b
+        WHERE b.blocker_id = c.user_id AND b.blocked_id = sqlc.narg('viewer_id')
+    );
+
+-- name: ListRootComments :many
+SELECT sqlc.embed(c), u.username, u.deleted_at AS author_deleted_at
+FROM comments c
+JOIN users u ON c.user_id = u.id
+WHERE c.post_id = sqlc.arg('post_id') AND c.parent_id IS NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks b
+        WHERE b.blocker_id = c.user_id AND b.blocked_id = sqlc.narg('viewer_id')
+    )
+ORDER BY c.created_at, c.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountRootComments :one
+SELECT COUNT(*) FROM comments c
+WHERE c.post_id = sqlc.arg('post_id') AND c.parent_id IS NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks b
+        WHERE b.blocker_id = c.user_id AND b.blocked_id = sqlc.narg('viewer_id')
+    );
+
+-- name: ListCommentReplies :many
+WITH RECURSIVE thread AS (
+    SELECT id FROM comments
+    WHERE parent_id = ANY(@root_ids::int[])
+    UNION ALL
+    SELECT r.id FROM comments r
+    JOIN thread t ON r.parent_id = t.id
+)
+SELECT sqlc.embed(c), u.username, u.deleted_at AS author_deleted_at
+FROM comments c
+JOIN thread ON thread.id = c.id
+JOIN users u ON c.user_id = u.id
+WHERE NOT EXISTS (
+    SELECT 1 FROM user_blocks b
+    WHERE b.blocker_id = c.user_id AND b.blocked_id = sqlc.narg('viewer_id')
+)
+ORDER BY c.created_at, c.id;
+
+-- name: CountCommentReplies :one
+SELECT COUNT(*) FROM comments
+WHERE parent_id = $1;
+
+-- name: CreateComment :one
+INSERT INTO comments (
+    post_id, user_id, parent_id, content
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: UpdateComment :one
+UPDATE comments
+SET content = $2
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SoftDeleteComment :exec
+UPDATE comments
+SET content = '', deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: EraseUserComments :exec
+-- Comments stay as tombstones so reply threads keep their shape.
+UPDATE comments
+SET content = '', deleted_at = COALESCE(deleted_at, CURRENT_TIMESTAMP)
+WHERE user_id = $1;
+
+-- name: DeleteComment :exec
+DELETE FROM comments
+WHERE id = $1;
\ No newline at end of file
@@ -0,0 +1,67 @@
+-- name: CreateDataExport :one
+INSERT INTO data_exports (
+    user_id
+) VALUES (
+    $1
+)
+RETURNING *;
+
+-- name: GetDataExport :one
+SELECT * FROM data_exports
+WHERE id = $1 AND user_id = $2
+LIMIT 1;
+
+-- name: GetDataExportByToken :one
+SELECT * FROM data_exports
+WHERE token = $1
+LIMIT 1;
+
+-- name: ClaimPendingDataExport :one
+-- Marks the oldest pending export as processing. Exports stuck in
+-- processing since stale_before, because their worker stopped, are taken
+-- again. SKIP LOCKED lets several workers take different exports.
+UPDATE data_exports
+SET status = 'processing', started_at = CURRENT_TIMESTAMP
+WHERE id = (
+    SELECT id FROM data_exports
+    WHERE status = 'pending'
+        OR (status = 'processing' AND started_at < sqlc.arg('stale_before'))
+    ORDER BY id
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteDataExport :one
+UPDATE data_exports
+SET
+    status = 'ready',
+    storage_key = sqlc.arg('storage_key'),
+    size_bytes = sqlc.arg('size_bytes'),
+    token = sqlc.arg('token'),
+    expires_at = sqlc.arg('expires_at'),
+    completed_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: FailDataExport :exec
+UPDATE data_exports
+SET status = 'failed', error = $2, completed_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ListExpiredDataExports :many
+-- Stored archives whose link has expired or whose user has been erased.
+SELECT * FROM data_exports
+WHERE storage_key IS NOT NULL
+    AND (expires_at < CURRENT_TIMESTAMP OR user_id IS NULL)
+ORDER BY id
+LIMIT $1;
+
+-- name: ExpireDataExport :exec
+UPDATE data_exports
+SET status = 'expired', storage_key = NULL
+WHERE id = $1;
+
+-- name: DeleteOrphanedDataExports :exec
+DELETE FROM data_exports
+WHERE user_id IS NULL AND storage_key IS NULL;
\ No newline at end of file
@@ -0,0 +1,127 @@
+-- name: FollowUser :execrows
+INSERT INTO follows (
+    follower_id, followee_id
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (follower_id, followee_id) DO NOTHING;
+
+-- name: UnfollowUser :exec
+DELETE FROM follows
+WHERE follower_id = $1 AND followee_id = $2;
+
+-- name: DeleteFollowsBetween :exec
+DELETE FROM follows
+WHERE (follower_id = sqlc.arg('user_id') AND followee_id = sqlc.arg('other_id'))
+    OR (follower_id = sqlc.arg('other_id') AND followee_id = sqlc.arg('user_id'));
+
+-- name: GetFollowCounts :one
+-- Counts apply the same filters as ListFollowers and ListFollowing so that
+-- totals match the lists they describe.
+SELECT
+    (SELECT COUNT(*) FROM follows f
+        JOIN users u ON f.follower_id = u.id
+        WHERE f.followee_id = sqlc.arg('user_id')
+            AND u.is_active = true AND u.deleted_at IS NULL
+            AND NOT EXISTS (
+                SELECT 1 FROM user_blocks b
+                WHERE b.blocker_id = u.id AND b.blocked_id = sqlc.narg('viewer_id')
+            )) AS follower_count,
+    (SELECT COUNT(*) FROM follows f
+        JOIN users u ON f.followee_id = u.id
+        WHERE f.follower_id = sqlc.arg('user_id')
+            AND u.is_active = true AND u.deleted_at IS NULL
+            AND NOT EXISTS (
+                SELECT 1 FROM user_blocks b
+                WHERE b.blocker_id = u.id AND b.blocked_id = sqlc.narg('viewer_id')
+            )) AS following_count;
+
+-- name: ListUserCounts :many
+-- Follower, following and published post counts of several users. Follows
+-- are counted with the same filters as GetFollowCounts.
+SELECT u.id AS user_id,
+    (SELECT COUNT(*) FROM follows f
+        JOIN users fu ON f.follower_id = fu.id
+        WHERE f.followee_id = u.id
+            AND fu.is_active = true AND fu.deleted_at IS NULL
+            AND NOT EXISTS (
+                SELECT 1 FROM user_blocks b
+                WHERE b.blocker_id = fu.id AND b.blocked_id = sqlc.narg('viewer_id')
+            )) AS follower_count,
+    (SELECT COUNT(*) FROM follows f
+        JOIN users fu ON f.followee_id = fu.id
+        WHERE f.follower_id = u.id
+            AND fu.is_active = true AND fu.deleted_at IS NULL
+            AND NOT EXISTS (
+                SELECT 1 FROM user_blocks b
+                WHERE b.blocker_id = fu.id AND b.blocked_id = sqlc.narg('viewer_id')
+            )) AS following_count,
+    (SELECT COUNT(*) FROM posts p
+        WHERE p.user_id = u.id AND p.status = 'published' AND p.deleted_at IS NULL) AS post_count
+FROM users u
+WHERE u.id = ANY(sqlc.arg('user_ids')::int[]);
+
+-- name: ListFollowers :many
+SELECT sqlc.embed(u), f.created_at AS followed_at
+FROM follows f
+JOIN users u ON f.follower_id = u.id
+WHERE f.followee_id = sqlc.arg('followee_id')
+    AND u.is_active = true AND u.deleted_at IS NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks b
+        WHERE b.blocker_id = u.id AND b.blocked_id = sqlc.narg('viewer_id')
+    )
+ORDER BY f.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListFollowing :many
+SELECT sqlc.embed(u), f.created_at AS followed_at
+FROM follows f
+JOIN users u ON f.followee_id = u.id
+WHERE f.follower_id = sqlc.arg('follower_id')
+    AND u.is_active = true AND u.deleted_at IS NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks b
+        WHERE b.blocker_id = u.id AND b.blocked_id = sqlc.narg('viewer_id')
+    )
+ORDER BY f.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListHomeFeed :many
+-- Fan-out on read: the lateral subquery takes at most limit posts per
+-- followed author from idx_posts_user_published, so the cost grows with
+-- the number of authors followed rather than with their post history.
+SELECT sqlc.embed(p), u.username,
+    (SELECT COUNT(*) FROM comments c
+        JOIN users cu ON c.user_id = cu.id
+        WHERE c.post_id = p.id AND c.deleted_at IS NULL AND cu.deleted_at IS NULL) AS comment_count
+FROM posts p
+JOIN users u ON p.user_id = u.id
+WHERE p.id IN (
+    SELECT latest.id
+    FROM follows f
+    JOIN users fu ON f.followee_id = fu.id AND fu.is_active = true AND fu.deleted_at IS NULL
+    CROSS JOIN LATERAL (
+        SELECT fp.id, fp.published_at
+        FROM posts fp
+        WHERE fp.user_id = f.followee_id
+            AND fp.status = 'published'
+            AND fp.deleted_at IS NULL
+            AND (sqlc.narg('before_published_at')::timestamptz IS NULL
+                OR (fp.published_at, fp.id) < (sqlc.narg('before_published_at')::timestamptz, sqlc.narg('before_id')::int))
+        ORDER BY fp.published_at DESC, fp.id DESC
+        LIMIT sqlc.arg('limit')
+    ) latest
+    WHERE f.follower_id = sqlc.arg('follower_id')
+        AND NOT EXISTS (
+            SELECT 1 FROM user_mutes m
+            WHERE m.muter_id = f.follower_id AND m.muted_id = f.followee_id
+        )
+        AND NOT EXISTS (
+            SELECT 1 FROM user_blocks b
+            WHERE b.blocker_id = f.followee_id AND b.blocked_id = f.follower_id
+        )
+    ORDER BY latest.published_at DESC, latest.id DESC
+    LIMIT sqlc.arg('limit')
+)
+ORDER BY p.published_at DESC, p.id DESC;
\ No newline at end of file
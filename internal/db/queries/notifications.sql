@@ -0,0 +1,86 @@
+-- name: CreateNotification :one
+-- Notifies a user of something another user did, unless they muted them.
+-- Returns no rows in that case.
+INSERT INTO notifications (user_id, actor_id, type, post_id, comment_id)
+SELECT @user_id, @actor_id, @type, sqlc.narg(post_id), sqlc.narg(comment_id)
+WHERE NOT EXISTS (
+    SELECT 1 FROM user_mutes
+    WHERE muter_id = @user_id AND muted_id = @actor_id
+)
+RETURNING *;
+
+-- name: ListNotifications :many
+SELECT * FROM notifications
+WHERE user_id = @user_id AND (NOT @unread_only::boolean OR read_at IS NULL)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountNotifications :one
+SELECT COUNT(*) FROM notifications
+WHERE user_id = @user_id AND (NOT @unread_only::boolean OR read_at IS NULL);
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*) FROM notifications
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: MarkNotificationRead :one
+UPDATE notifications
+SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET read_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: GetNotificationPreferences :one
+SELECT * FROM notification_preferences
+WHERE user_id = $1;
+
+-- name: SaveNotificationPreferences :one
+INSERT INTO notification_preferences (user_id, comments, replies, likes, follows, email_digest)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (user_id) DO UPDATE
+SET comments = EXCLUDED.comments,
+    replies = EXCLUDED.replies,
+    likes = EXCLUDED.likes,
+    follows = EXCLUDED.follows,
+    email_digest = EXCLUDED.email_digest,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: ListDueDigests :many
+-- Users whose daily digest was last sent before daily_before or weekly
+-- digest before weekly_before.
+SELECT np.user_id, np.email_digest, u.email, u.username
+FROM notification_preferences np
+JOIN users u ON np.user_id = u.id
+WHERE u.deleted_at IS NULL AND u.is_active = true
+    AND ((np.email_digest = 'daily' AND (np.last_digest_at IS NULL OR np.last_digest_at <= @daily_before))
+        OR (np.email_digest = 'weekly' AND (np.last_digest_at IS NULL OR np.last_digest_at <= @weekly_before)))
+ORDER BY np.user_id
+LIMIT sqlc.arg('limit');
+
+-- name: ListUndigestedNotifications :many
+-- Unread notifications not yet part of a digest, with the actor's username
+-- and the post's title where there are any.
+SELECT n.id, n.type, n.created_at,
+    COALESCE(u.username, '') AS actor_username,
+    COALESCE(p.title, '') AS post_title
+FROM notifications n
+LEFT JOIN users u ON n.actor_id = u.id AND u.deleted_at IS NULL
+LEFT JOIN posts p ON n.post_id = p.id AND p.deleted_at IS NULL
+WHERE n.user_id = $1 AND n.read_at IS NULL AND n.emailed_at IS NULL
+ORDER BY n.created_at, n.id
+LIMIT $2;
+
+-- name: MarkNotificationsEmailed :exec
+UPDATE notifications
+SET emailed_at = CURRENT_TIMESTAMP
+WHERE id = ANY(@ids::int[]);
+
+-- name: RecordDigestSent :exec
+UPDATE notification_preferences
+SET last_digest_at = $2
+WHERE user_id = $1;
\ No newline at end of file
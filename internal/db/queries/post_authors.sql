@@ -0,0 +1,24 @@
+-- name: GetPostAuthorRole :one
+SELECT role FROM post_authors
+WHERE post_id = $1 AND user_id = $2;
+
+-- name: ListPostAuthors :many
+-- The owner comes first, then co-authors in the order they were added.
+SELECT pa.user_id, u.username, u.full_name, pa.role, pa.created_at
+FROM post_authors pa
+JOIN users u ON pa.user_id = u.id
+WHERE pa.post_id = $1 AND u.deleted_at IS NULL
+ORDER BY pa.role = 'owner' DESC, pa.created_at, pa.user_id;
+
+-- name: AddPostCoAuthor :execrows
+-- Adding an existing author, including the owner, changes nothing.
+INSERT INTO post_authors (
+    post_id, user_id, role
+) VALUES (
+    $1, $2, 'co-author'
+)
+ON CONFLICT (post_id, user_id) DO NOTHING;
+
+-- name: RemovePostCoAuthor :execrows
+DELETE FROM post_authors
+WHERE post_id = $1 AND user_id = $2 AND role = 'co-author';
\ No newline at end of file
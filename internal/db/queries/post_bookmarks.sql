@@ -0,0 +1,39 @@
+-- name: BookmarkPost :exec
+INSERT INTO post_bookmarks (
+    post_id, user_id
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (post_id, user_id) DO NOTHING;
+
+-- name: UnbookmarkPost :exec
+DELETE FROM post_bookmarks
+WHERE post_id = $1 AND user_id = $2;
+
+-- name: ListBookmarkedPosts :many
+SELECT sqlc.embed(p), u.username, b.created_at AS bookmarked_at,
+    (SELECT COUNT(*) FROM comments c
+        JOIN users cu ON c.user_id = cu.id
+        WHERE c.post_id = p.id AND c.deleted_at IS NULL AND cu.deleted_at IS NULL) AS comment_count
+FROM post_bookmarks b
+JOIN posts p ON b.post_id = p.id
+JOIN users u ON p.user_id = u.id
+WHERE b.user_id = $1 AND p.status = 'published' AND p.deleted_at IS NULL AND u.deleted_at IS NULL
+    AND u.is_active = true
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks ub
+        WHERE ub.blocker_id = p.user_id AND ub.blocked_id = b.user_id
+    )
+ORDER BY b.created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountBookmarkedPosts :one
+SELECT COUNT(*) FROM post_bookmarks b
+JOIN posts p ON b.post_id = p.id
+JOIN users u ON p.user_id = u.id
+WHERE b.user_id = $1 AND p.status = 'published' AND p.deleted_at IS NULL AND u.deleted_at IS NULL
+    AND u.is_active = true
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks ub
+        WHERE ub.blocker_id = p.user_id AND ub.blocked_id = b.user_id
+    );
\ No newline at end of file
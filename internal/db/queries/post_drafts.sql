@@ -0,0 +1,19 @@
+-- name: GetPostDraft :one
+SELECT * FROM post_drafts
+WHERE post_id = $1 AND user_id = $2;
+
+-- name: SavePostDraft :one
+-- Replaces the author's autosave of a post.
+INSERT INTO post_drafts (post_id, user_id, title, content, content_format, base_version)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (post_id, user_id) DO UPDATE
+SET title = EXCLUDED.title,
+    content = EXCLUDED.content,
+    content_format = EXCLUDED.content_format,
+    base_version = EXCLUDED.base_version,
+    saved_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeletePostDraft :execrows
+DELETE FROM post_drafts
+WHERE post_id = $1 AND user_id = $2;
\ No newline at end of file
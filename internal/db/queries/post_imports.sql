@@ -0,0 +1,20 @@
+-- name: GetPostImport :one
+SELECT * FROM post_imports
+WHERE source_key = $1;
+
+-- name: CreatePostImport :execrows
+INSERT INTO post_imports (source_key, post_id, format, imported_by)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (source_key) DO NOTHING;
+
+-- name: ImportPost :one
+-- Creates a post with the dates of the blog it was imported from.
+INSERT INTO posts (
+    user_id, title, slug, content, status, content_format, content_html, locale,
+    published_at, created_at
+) VALUES (
+    sqlc.arg('user_id'), sqlc.arg('title'), sqlc.arg('slug'), sqlc.arg('content'),
+    sqlc.arg('status'), sqlc.arg('content_format'), sqlc.arg('content_html'), sqlc.arg('locale'),
+    sqlc.narg('published_at')::timestamptz, sqlc.arg('created_at')::timestamptz
+)
+RETURNING *;
\ No newline at end of file
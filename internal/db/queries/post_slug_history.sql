@@ -0,0 +1,14 @@
+-- name: GetSlugRedirect :one
+SELECT p.slug AS current_slug FROM post_slug_history h
+JOIN posts p ON p.id = h.post_id
+WHERE h.slug = $1 AND p.deleted_at IS NULL
+LIMIT 1;
+
+-- name: AddSlugHistory :exec
+INSERT INTO post_slug_history (slug, post_id)
+VALUES ($1, $2)
+ON CONFLICT (slug) DO NOTHING;
+
+-- name: DeleteSlugHistory :exec
+DELETE FROM post_slug_history
+WHERE slug = $1 AND post_id = $2;
\ No newline at end of file
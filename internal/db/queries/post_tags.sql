@@ -0,0 +1,18 @@
+-- name: AddPostTag :exec
+INSERT INTO post_tags (post_id, tag)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: DeletePostTags :exec
+DELETE FROM post_tags
+WHERE post_id = $1;
+
+-- name: ListPostTags :many
+SELECT tag FROM post_tags
+WHERE post_id = $1
+ORDER BY tag;
+
+-- name: ListTagsForPosts :many
+SELECT * FROM post_tags
+WHERE post_id = ANY(@post_ids::int[])
+ORDER BY post_id, tag;
\ No newline at end of file
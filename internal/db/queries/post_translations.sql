@@ -0,0 +1,93 @@
+-- name: GetPostTranslation :one
+SELECT * FROM post_translations
+WHERE post_id = $1 AND locale = $2;
+
+-- name: GetPostTranslationForUpdate :one
+SELECT * FROM post_translations
+WHERE post_id = $1 AND locale = $2
+FOR UPDATE;
+
+-- name: ListPostTranslations :many
+SELECT * FROM post_translations
+WHERE post_id = $1
+ORDER BY locale;
+
+-- name: ListTranslationsForPosts :many
+-- Translations of a page of posts into any of the preferred locales.
+SELECT * FROM post_translations
+WHERE post_id = ANY(@post_ids::int[]) AND locale = ANY(@locales::text[]);
+
+-- name: CreatePostTranslation :one
+INSERT INTO post_translations (
+    post_id, locale, title, slug, content, content_format, content_html
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: UpdatePostTranslation :one
+UPDATE post_translations
+SET
+    title = sqlc.arg('title'),
+    slug = sqlc.arg('slug'),
+    content = sqlc.narg('content'),
+    content_format = sqlc.arg('content_format'),
+    content_html = sqlc.narg('content_html')
+WHERE post_id = sqlc.arg('post_id') AND locale = sqlc.arg('locale')
+RETURNING *;
+
+-- name: DeletePostTranslation :execrows
+DELETE FROM post_translations
+WHERE post_id = $1 AND locale = $2;
+
+-- name: ListTakenTranslationSlugs :many
+-- Slugs that collide with a base slug. A translation may take back old
+-- slugs of its own post but not the slugs of the post or its other
+-- translations.
+SELECT slug FROM posts
+WHERE slug = @slug::text OR slug LIKE @slug::text || '-%'
+UNION
+SELECT slug FROM post_slug_history
+WHERE (slug = @slug::text OR slug LIKE @slug::text || '-%') AND post_id <> @post_id::int
+UNION
+SELECT slug FROM post_translations
+WHERE (slug = @slug::text OR slug LIKE @slug::text || '-%')
+    AND NOT (post_id = @post_id::int AND locale = @locale::text);
+
+-- name: GetPostByTranslationSlug :one
+SELECT sqlc.embed(p), u.username, u.email, t.locale AS translation_locale,
+    (SELECT COUNT(*) FROM comments c
+        JOIN users cu ON c.user_id = cu.id
+        WHERE c.post_id = p.id AND c.deleted_at IS NULL AND cu.deleted_at IS NULL) AS comment_count
+FROM post_translations t
+JOIN posts p ON t.post_id = p.id
+JOIN users u ON p.user_id = u.id
+WHERE t.slug = $1 AND p.deleted_at IS NULL AND u.deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListPostsMissingTranslation :many
+-- Published posts not written in a locale and not yet translated into it,
+-- oldest first so translators can work through the backlog.
+SELECT sqlc.embed(p), u.username
+FROM posts p
+JOIN users u ON p.user_id = u.id
+WHERE p.status = 'published' AND p.deleted_at IS NULL AND u.deleted_at IS NULL
+    AND u.is_active = true
+    AND p.locale <> sqlc.arg('locale')::text
+    AND NOT EXISTS (
+        SELECT 1 FROM post_translations t
+        WHERE t.post_id = p.id AND t.locale = sqlc.arg('locale')::text
+    )
+ORDER BY p.published_at, p.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountPostsMissingTranslation :one
+SELECT COUNT(*) FROM posts p
+JOIN users u ON p.user_id = u.id
+WHERE p.status = 'published' AND p.deleted_at IS NULL AND u.deleted_at IS NULL
+    AND u.is_active = true
+    AND p.locale <> sqlc.arg('locale')::text
+    AND NOT EXISTS (
+        SELECT 1 FROM post_translations t
+        WHERE t.post_id = p.id AND t.locale = sqlc.arg('locale')::text
+    );
\ No newline at end of file
@@ -0,0 +1,93 @@
+-- name: AddPostViews :exec
+-- Adds buffered views to an hourly bucket and to the post's total. Views of
+-- a post that was purged since they were recorded are dropped.
+WITH bucket AS (
+    INSERT INTO post_views (post_id, hour, views)
+    SELECT @post_id::int, @hour::timestamptz, @views::int
+    WHERE EXISTS (SELECT 1 FROM posts WHERE id = @post_id)
+    ON CONFLICT (post_id, hour) DO UPDATE SET views = post_views.views + EXCLUDED.views
+)
+INSERT INTO post_stats (post_id, view_count)
+SELECT @post_id::int, @views::int
+WHERE EXISTS (SELECT 1 FROM posts WHERE id = @post_id)
+ON CONFLICT (post_id) DO UPDATE SET view_count = post_stats.view_count + EXCLUDED.view_count;
+
+-- name: RefreshTrendingScores :execrows
+-- Each view counts half as much for every half-life that has passed since
+-- its hour. Posts without views since the start of the window drop to zero.
+WITH scores AS (
+    SELECT post_id,
+        SUM(views * POWER(0.5, EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - hour) / sqlc.arg('half_life_seconds')::float8)) AS score
+    FROM post_views
+    WHERE hour >= sqlc.arg('since')::timestamptz
+    GROUP BY post_id
+)
+UPDATE post_stats ps
+SET trending_score = COALESCE(s.score, 0), scored_at = CURRENT_TIMESTAMP
+FROM post_stats cur
+LEFT JOIN scores s ON s.post_id = cur.post_id
+WHERE ps.post_id = cur.post_id AND (cur.trending_score > 0 OR s.score IS NOT NULL);
+
+-- name: DeleteOldPostViews :execrows
+DELETE FROM post_views
+WHERE hour < $1;
+
+-- name: GetPostStats :one
+SELECT
+    COALESCE((SELECT view_count FROM post_stats s WHERE s.post_id = sqlc.arg('post_id')), 0)::bigint AS view_count,
+    COALESCE((SELECT trending_score FROM post_stats s WHERE s.post_id = sqlc.arg('post_id')), 0)::float8 AS trending_score,
+    COALESCE((SELECT SUM(views) FROM post_views v
+        WHERE v.post_id = sqlc.arg('post_id') AND v.hour >= sqlc.arg('day_since')::timestamptz), 0)::bigint AS views_last_day,
+    COALESCE((SELECT SUM(views) FROM post_views v
+        WHERE v.post_id = sqlc.arg('post_id') AND v.hour >= sqlc.arg('week_since')::timestamptz), 0)::bigint AS views_last_week;
+
+-- name: ListPostDailyViews :many
+SELECT (hour AT TIME ZONE 'UTC')::date AS day, SUM(views)::bigint AS views
+FROM post_views
+WHERE post_id = sqlc.arg('post_id') AND hour >= sqlc.arg('since')::timestamptz
+GROUP BY day
+ORDER BY day;
+
+-- name: ListTrendingPosts :many
+SELECT sqlc.embed(p), u.username, ps.view_count, ps.trending_score,
+    (SELECT COUNT(*) FROM comments c
+        JOIN users cu ON c.user_id = cu.id
+        WHERE c.post_id = p.id AND c.deleted_at IS NULL AND cu.deleted_at IS NULL) AS comment_count
+FROM post_stats ps
+JOIN posts p ON ps.post_id = p.id
+JOIN users u ON p.user_id = u.id
+WHERE ps.trending_score > 0
+    AND p.status = 'published' AND p.deleted_at IS NULL AND u.deleted_at IS NULL
+    AND u.is_active = true
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks b
+        WHERE b.blocker_id = p.user_id AND b.blocked_id = sqlc.narg('viewer_id')
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM user_mutes m
+        WHERE m.muter_id = sqlc.narg('viewer_id') AND m.muted_id = p.user_id
+    )
+ORDER BY ps.trending_score DESC, p.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListMostReadPosts :many
+SELECT sqlc.embed(p), u.username, ps.view_count, ps.trending_score,
+    (SELECT COUNT(*) FROM comments c
+        JOIN users cu ON c.user_id = cu.id
+        WHERE c.post_id = p.id AND c.deleted_at IS NULL AND cu.deleted_at IS NULL) AS comment_count
+FROM post_stats ps
+JOIN posts p ON ps.post_id = p.id
+JOIN users u ON p.user_id = u.id
+WHERE ps.view_count > 0
+    AND p.status = 'published' AND p.deleted_at IS NULL AND u.deleted_at IS NULL
+    AND u.is_active = true
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks b
+        WHERE b.blocker_id = p.user_id AND b.blocked_id = sqlc.narg('viewer_id')
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM user_mutes m
+        WHERE m.muter_id = sqlc.narg('viewer_id') AND m.muted_id = p.user_id
+    )
+ORDER BY ps.view_count DESC, p.id DESC
+LIMIT sqlc.arg('limit');
\ No newline at end of file
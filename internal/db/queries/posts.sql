@@ -1,48 +1,199 @@
 -- name: GetPost :one
-SELECT p.*, u.username, u.email
+SELECT sqlc.embed(p), u.username, u.email,
+    (SELECT COUNT(*) FROM comments c
+        JOIN users cu ON c.user_id = cu.id
+        WHERE c.post_id = p.id AND c.deleted_at IS NULL AND cu.deleted_at IS NULL) AS comment_count
 FROM posts p
 JOIN users u ON p.user_id = u.id
-WHERE p.id = $1 LIMIT 1;
+WHERE p.id = $1 AND p.deleted_at IS NULL AND u.deleted_at IS NULL
+LIMIT 1;
+
+-- name: GetPostBySlug :one
+SELECT sqlc.embed(p), u.username, u.email,
+    (SELECT COUNT(*) FROM comments c
+        JOIN users cu ON c.user_id = cu.id
+        WHERE c.post_id = p.id AND c.deleted_at IS NULL AND cu.deleted_at IS NULL) AS comment_count
+FROM posts p
+JOIN users u ON p.user_id = u.id
+WHERE p.slug = $1 AND p.deleted_at IS NULL AND u.deleted_at IS NULL
+LIMIT 1;
 
 -- name: ListPosts :many
-SELECT p.*, u.username, u.email
+SELECT sqlc.embed(p), u.username, u.email,
+    (SELECT COUNT(*) FROM comments c
+        JOIN users cu ON c.user_id = cu.id
+        WHERE c.post_id = p.id AND c.deleted_at IS NULL AND cu.deleted_at IS NULL) AS comment_count
 FROM posts p
 JOIN users u ON p.user_id = u.id
-WHERE p.status = 'published'
+WHERE p.status = 'published' AND p.deleted_at IS NULL AND u.deleted_at IS NULL
+    AND u.is_active = true
+    AND (sqlc.narg('user_id')::int IS NULL OR p.user_id = sqlc.narg('user_id')::int)
+    -- Tags are compared case-insensitively, as they are deduplicated.
+    AND (sqlc.narg('tag')::text IS NULL OR EXISTS (
+        SELECT 1 FROM post_tags pt
+        WHERE pt.post_id = p.id AND lower(pt.tag) = lower(sqlc.narg('tag')::text)
+    ))
+    -- Hide authors who blocked the viewer and authors the viewer muted.
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks b
+        WHERE b.blocker_id = p.user_id AND b.blocked_id = sqlc.narg('viewer_id')
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM user_mutes m
+        WHERE m.muter_id = sqlc.narg('viewer_id') AND m.muted_id = p.user_id
+    )
 ORDER BY p.published_at DESC
-LIMIT $1 OFFSET $2;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: ListUserPosts :many
 SELECT * FROM posts
-WHERE user_id = $1
+WHERE user_id = sqlc.arg('user_id') AND deleted_at IS NULL
+    AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status')::text)
 ORDER BY created_at DESC
-LIMIT $2 OFFSET $3;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListAllUserPosts :many
+-- Every post of a user whatever its status, including the trash.
+SELECT * FROM posts
+WHERE user_id = $1
+ORDER BY id;
 
 -- name: CreatePost :one
 INSERT INTO posts (
-    user_id, title, content, status
+    user_id, title, slug, content, status, content_format, content_html, locale, published_at
 ) VALUES (
-    $1, $2, $3, $4
+    $1, $2, $3, $4, $5, $6, $7, $8,
+    CASE WHEN $5 = 'published' THEN CURRENT_TIMESTAMP END
 )
 RETURNING *;
 
+-- name: GetPostForUpdate :one
+SELECT * FROM posts
+WHERE id = $1 AND deleted_at IS NULL
+LIMIT 1
+FOR UPDATE;
+
 -- name: UpdatePost :one
 UPDATE posts
 SET
-    title = COALESCE($2, title),
-    content = COALESCE($3, content),
-    status = COALESCE($4, status),
+    title = sqlc.arg('title'),
+    slug = sqlc.arg('slug'),
+    content = sqlc.narg('content'),
+    content_format = sqlc.arg('content_format'),
+    content_html = sqlc.narg('content_html'),
+    status = sqlc.arg('status'),
     published_at = CASE
-        WHEN $4 = 'published' AND status != 'published' THEN CURRENT_TIMESTAMP
+        WHEN sqlc.arg('status') = 'published' AND status IS DISTINCT FROM 'published' THEN CURRENT_TIMESTAMP
         ELSE published_at
     END
-WHERE id = $1
+WHERE id = sqlc.arg('id')
 RETURNING *;
 
--- name: DeletePost :exec
+-- name: ListTakenSlugs :many
+-- Slugs that collide with a base slug. A post may take back its own old
+-- slugs but not the slugs of its translations.
+SELECT slug FROM posts
+WHERE (slug = @slug::text OR slug LIKE @slug::text || '-%') AND id <> @post_id::int
+UNION
+SELECT slug FROM post_slug_history
+WHERE (slug = @slug::text OR slug LIKE @slug::text || '-%') AND post_id <> @post_id::int
+UNION
+SELECT slug FROM post_translations
+WHERE slug = @slug::text OR slug LIKE @slug::text || '-%';
+
+-- name: TrashPost :execrows
+UPDATE posts
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg('id') AND deleted_at IS NULL
+    AND (sqlc.narg('version')::int IS NULL OR version = sqlc.narg('version')::int);
+
+-- name: GetTrashedPost :one
+SELECT * FROM posts
+WHERE id = $1 AND deleted_at IS NOT NULL
+LIMIT 1;
+
+-- name: ListTrashedPosts :many
+SELECT * FROM posts
+WHERE user_id = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountTrashedPosts :one
+SELECT COUNT(*) FROM posts
+WHERE user_id = $1 AND deleted_at IS NOT NULL;
+
+-- name: RestorePost :one
+UPDATE posts
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeTrashedPosts :execrows
+-- Removes at most limit posts trashed before the cutoff. Their attachments
+-- are left for the attachment cleanup worker.
 DELETE FROM posts
-WHERE id = $1;
+WHERE id IN (
+    SELECT id FROM posts
+    WHERE deleted_at < sqlc.arg('before')
+    ORDER BY id
+    LIMIT sqlc.arg('limit')
+);
 
 -- name: CountPosts :one
+SELECT COUNT(*) FROM posts p
+JOIN users u ON p.user_id = u.id
+WHERE p.status = sqlc.arg('status') AND p.deleted_at IS NULL AND u.deleted_at IS NULL
+    AND u.is_active = true
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks b
+        WHERE b.blocker_id = p.user_id AND b.blocked_id = sqlc.narg('viewer_id')
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM user_mutes m
+        WHERE m.muter_id = sqlc.narg('viewer_id') AND m.muted_id = p.user_id
+    );
+
+-- name: CountUserPosts :one
 SELECT COUNT(*) FROM posts
-WHERE status = $1;
\ No newline at end of file
+WHERE user_id = sqlc.arg('user_id') AND deleted_at IS NULL
+    AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status')::text);
+
+-- name: SetPostStatus :one
+UPDATE posts
+SET status = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: SetPostCommentsClosed :one
+UPDATE posts
+SET comments_closed = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: GetPostCounters :one
+SELECT like_count, bookmark_count FROM posts
+WHERE id = $1 LIMIT 1;
+
+-- name: ListPostReactions :many
+SELECT p.id,
+    EXISTS (SELECT 1 FROM post_likes l WHERE l.post_id = p.id AND l.user_id = @user_id) AS liked,
+    EXISTS (SELECT 1 FROM post_bookmarks b WHERE b.post_id = p.id AND b.user_id = @user_id) AS bookmarked
+FROM posts p
+WHERE p.id = ANY(@post_ids::int[]);
+
+-- name: ListPostsForExport :many
+-- Posts outside the trash, of one user or of everyone.
+SELECT sqlc.embed(p), u.username
+FROM posts p
+JOIN users u ON p.user_id = u.id
+WHERE p.deleted_at IS NULL
+    AND (sqlc.narg('user_id')::int IS NULL OR p.user_id = sqlc.narg('user_id')::int)
+ORDER BY p.id;
+
+-- name: TouchPost :one
+-- Marks a post as changed when only rows that belong to it, such as its
+-- tags, were written. The trigger bumps updated_at and the version.
+UPDATE posts
+SET updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
\ No newline at end of file
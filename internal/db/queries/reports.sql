@@ -0,0 +1,54 @@
+-- name: CreateReport :one
+INSERT INTO reports (
+    reporter_id, target_type, target_id, reason, details
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetReportForUpdate :one
+SELECT * FROM reports
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: ListReports :many
+SELECT * FROM reports
+WHERE status = $1
+ORDER BY created_at, id
+LIMIT $2 OFFSET $3;
+
+-- name: CountReports :one
+SELECT COUNT(*) FROM reports
+WHERE status = $1;
+
+-- name: ClaimReport :one
+UPDATE reports
+SET status = 'claimed', claimed_by = $2, claimed_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: CloseReport :one
+UPDATE reports
+SET
+    status = sqlc.arg('status'),
+    closed_by = sqlc.arg('closed_by'),
+    closed_at = CURRENT_TIMESTAMP,
+    resolution = sqlc.narg('resolution'),
+    note = sqlc.narg('note')
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: CreateModerationLogEntry :exec
+INSERT INTO moderation_log (
+    moderator_id, report_id, action, target_type, target_id, note
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: ListModerationLog :many
+SELECT * FROM moderation_log
+ORDER BY created_at DESC, id DESC
+LIMIT $1 OFFSET $2;
+
+-- name: CountModerationLog :one
+SELECT COUNT(*) FROM moderation_log;
\ No newline at end of file
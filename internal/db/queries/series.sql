@@ -0,0 +1,69 @@
+-- name: CreateSeries :one
+INSERT INTO series (
+    user_id, title, description
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetSeries :one
+SELECT s.* FROM series s
+JOIN users u ON s.user_id = u.id
+WHERE s.id = $1 AND u.deleted_at IS NULL;
+
+-- name: GetSeriesForUpdate :one
+SELECT * FROM series
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListSeries :many
+SELECT s.*, u.username FROM series s
+JOIN users u ON s.user_id = u.id
+WHERE u.deleted_at IS NULL
+    AND (sqlc.narg('user_id')::int IS NULL OR s.user_id = sqlc.narg('user_id')::int)
+ORDER BY s.created_at DESC, s.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountSeries :one
+SELECT COUNT(*) FROM series s
+JOIN users u ON s.user_id = u.id
+WHERE u.deleted_at IS NULL
+    AND (sqlc.narg('user_id')::int IS NULL OR s.user_id = sqlc.narg('user_id')::int);
+
+-- name: UpdateSeries :one
+UPDATE series
+SET title = $2, description = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteSeries :exec
+DELETE FROM series
+WHERE id = $1;
+
+-- name: ListSeriesPosts :many
+-- Posts of a series in reading order. Unless include_unpublished is set,
+-- only published posts by active authors are listed.
+SELECT sqlc.embed(p), u.username, sp.position
+FROM series_posts sp
+JOIN posts p ON sp.post_id = p.id
+JOIN users u ON p.user_id = u.id
+WHERE sp.series_id = sqlc.arg('series_id') AND p.deleted_at IS NULL AND u.deleted_at IS NULL
+    AND (sqlc.arg('include_unpublished')::boolean OR (p.status = 'published' AND u.is_active = true))
+ORDER BY sp.position;
+
+-- name: GetPostSeries :one
+SELECT s.id, s.title, sp.position
+FROM series_posts sp
+JOIN series s ON sp.series_id = s.id
+WHERE sp.post_id = $1;
+
+-- name: ClearSeriesPosts :exec
+DELETE FROM series_posts
+WHERE series_id = $1;
+
+-- name: AddSeriesPost :exec
+INSERT INTO series_posts (
+    series_id, post_id, position
+) VALUES (
+    $1, $2, $3
+);
\ No newline at end of file
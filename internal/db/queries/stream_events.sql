@@ -0,0 +1,25 @@
+-- name: CreateStreamEvent :one
+INSERT INTO stream_events (user_id, type, data)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetStreamEvent :one
+SELECT * FROM stream_events
+WHERE id = $1;
+
+-- name: ListStreamEventsAfter :many
+-- Events a user may see that were published after an event, oldest first.
+SELECT * FROM stream_events
+WHERE id > @after_id AND (user_id IS NULL OR user_id = @user_id::int)
+ORDER BY id
+LIMIT sqlc.arg('limit');
+
+-- name: ListAllStreamEventsAfter :many
+SELECT * FROM stream_events
+WHERE id > $1
+ORDER BY id
+LIMIT $2;
+
+-- name: PruneStreamEvents :execrows
+DELETE FROM stream_events
+WHERE created_at < $1;
\ No newline at end of file
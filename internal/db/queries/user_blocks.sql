@@ -0,0 +1,30 @@
+-- name: BlockUser :exec
+INSERT INTO user_blocks (
+    blocker_id, blocked_id
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (blocker_id, blocked_id) DO NOTHING;
+
+-- name: UnblockUser :exec
+DELETE FROM user_blocks
+WHERE blocker_id = $1 AND blocked_id = $2;
+
+-- name: IsBlocked :one
+SELECT EXISTS (
+    SELECT 1 FROM user_blocks
+    WHERE blocker_id = $1 AND blocked_id = $2
+);
+
+-- name: ListBlockedUsers :many
+SELECT sqlc.embed(u), b.created_at AS blocked_at
+FROM user_blocks b
+JOIN users u ON b.blocked_id = u.id
+WHERE b.blocker_id = $1 AND u.deleted_at IS NULL
+ORDER BY b.created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountBlockedUsers :one
+SELECT COUNT(*) FROM user_blocks b
+JOIN users u ON b.blocked_id = u.id
+WHERE b.blocker_id = $1 AND u.deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,24 @@
+-- name: MuteUser :exec
+INSERT INTO user_mutes (
+    muter_id, muted_id
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (muter_id, muted_id) DO NOTHING;
+
+-- name: UnmuteUser :exec
+DELETE FROM user_mutes
+WHERE muter_id = $1 AND muted_id = $2;
+
+-- name: ListMutedUsers :many
+SELECT sqlc.embed(u), m.created_at AS muted_at
+FROM user_mutes m
+JOIN users u ON m.muted_id = u.id
+WHERE m.muter_id = $1 AND u.deleted_at IS NULL
+ORDER BY m.created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountMutedUsers :one
+SELECT COUNT(*) FROM user_mutes m
+JOIN users u ON m.muted_id = u.id
+WHERE m.muter_id = $1 AND u.deleted_at IS NULL;
\ No newline at end of file
@@ -1,21 +1,28 @@
 -- name: GetUser :one
 SELECT * FROM users
-WHERE id = $1 LIMIT 1;
+WHERE id = $1 AND deleted_at IS NULL
+LIMIT 1;
 
 -- name: GetUserByEmail :one
 SELECT * FROM users
-WHERE email = $1 LIMIT 1;
+WHERE email = $1 AND deleted_at IS NULL
+LIMIT 1;
 
 -- name: GetUserByUsername :one
 SELECT * FROM users
-WHERE username = $1 LIMIT 1;
+WHERE username = $1 AND deleted_at IS NULL
+LIMIT 1;
 
 -- name: ListUsers :many
 SELECT * FROM users
-WHERE is_active = true
+WHERE is_active = true AND deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2;
 
+-- name: ListUsersByIDs :many
+SELECT * FROM users
+WHERE id = ANY(@ids::int[]) AND deleted_at IS NULL;
+
 -- name: CreateUser :one
 INSERT INTO users (
     email, username, password_hash, full_name
@@ -24,16 +31,137 @@ INSERT INTO users (
 )
 RETURNING *;
 
+-- name: GetUserForUpdate :one
+SELECT * FROM users
+WHERE id = $1 AND deleted_at IS NULL
+LIMIT 1
+FOR UPDATE;
+
 -- name: UpdateUser :one
 UPDATE users
 SET
-    email = COALESCE($2, email),
-    username = COALESCE($3, username),
-    full_name = COALESCE($4, full_name),
-    is_active = COALESCE($5, is_active)
+    email = sqlc.arg('email'),
+    username = sqlc.arg('username'),
+    full_name = sqlc.narg('full_name'),
+    bio = sqlc.narg('bio'),
+    website = sqlc.narg('website'),
+    location = sqlc.narg('location')
+WHERE id = sqlc.arg('id')
+    AND (sqlc.narg('version')::int IS NULL OR version = sqlc.narg('version')::int)
+RETURNING *;
+
+-- name: UpdateUserAvatar :one
+UPDATE users
+SET avatar_key = sqlc.narg('avatar_key')
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: SetUserActive :one
+UPDATE users
+SET is_active = $2
 WHERE id = $1
 RETURNING *;
 
+-- name: TrashUser :execrows
+UPDATE users
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg('id') AND deleted_at IS NULL
+    AND (sqlc.narg('version')::int IS NULL OR version = sqlc.narg('version')::int);
+
+-- name: ListTrashedUsers :many
+SELECT * FROM users
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id DESC
+LIMIT $1 OFFSET $2;
+
+-- name: CountTrashedUsers :one
+SELECT COUNT(*) FROM users
+WHERE deleted_at IS NOT NULL;
+
+-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: ListPurgeableUsers :many
+SELECT * FROM users
+WHERE deleted_at < sqlc.arg('before')
+ORDER BY id
+LIMIT sqlc.arg('limit');
+
+-- name: PurgeUser :execrows
+-- Deleting a user cascades to their posts, comments and relationships.
+DELETE FROM users
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: ScheduleUserErasure :one
+-- Keeps an existing schedule so repeating the request does not postpone it.
+UPDATE users
+SET erasure_scheduled_at = COALESCE(erasure_scheduled_at, sqlc.arg('erasure_scheduled_at')::timestamptz)
+WHERE id = sqlc.arg('id') AND deleted_at IS NULL
+RETURNING *;
+
+-- name: CancelUserErasure :one
+UPDATE users
+SET erasure_scheduled_at = NULL
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: ListDueErasures :many
+SELECT * FROM users
+WHERE erasure_scheduled_at <= CURRENT_TIMESTAMP
+ORDER BY erasure_scheduled_at
+LIMIT $1;
+
+-- name: AnonymizeUser :exec
+-- Replaces every personal field. The account can no longer sign in, and
+-- its username and email are freed.
+UPDATE users
+SET
+    email = 'erased-' || id || '@erased.invalid',
+    username = 'erased-' || id,
+    password_hash = '',
+    full_name = NULL,
+    bio = NULL,
+    website = NULL,
+    location = NULL,
+    avatar_key = NULL,
+    role = 'user',
+    is_active = false,
+    erasure_scheduled_at = NULL,
+    erased_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: DeleteUserRelationships :exec
+-- Removes everything that ties an erased user to other users and posts,
+-- except the posts themselves, along with their autosaves, notification
+-- settings and webhooks. Their data exports are left for the export
+-- worker to remove.
+WITH deleted_follows AS (
+    DELETE FROM follows WHERE follower_id = @user_id OR followee_id = @user_id
+), deleted_blocks AS (
+    DELETE FROM user_blocks WHERE blocker_id = @user_id OR blocked_id = @user_id
+), deleted_mutes AS (
+    DELETE FROM user_mutes WHERE muter_id = @user_id OR muted_id = @user_id
+), deleted_likes AS (
+    DELETE FROM post_likes WHERE user_id = @user_id
+), deleted_bookmarks AS (
+    DELETE FROM post_bookmarks WHERE user_id = @user_id
+), deleted_coauthorships AS (
+    DELETE FROM post_authors WHERE user_id = @user_id AND role = 'co-author'
+), deleted_drafts AS (
+    DELETE FROM post_drafts WHERE user_id = @user_id
+), deleted_notification_preferences AS (
+    DELETE FROM notification_preferences WHERE user_id = @user_id
+), deleted_webhooks AS (
+    DELETE FROM webhooks WHERE user_id = @user_id
+), detached_reports AS (
+    UPDATE reports SET reporter_id = NULL WHERE reporter_id = @user_id
+)
+UPDATE data_exports SET user_id = NULL WHERE user_id = @user_id;
+
 -- name: DeleteUser :exec
+-- Deleting a user cascades to their posts, comments and relationships.
 DELETE FROM users
 WHERE id = $1;
\ No newline at end of file
@@ -0,0 +1,110 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (user_id, url, event_types, secret)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetWebhook :one
+SELECT * FROM webhooks
+WHERE id = $1;
+
+-- name: ListWebhooks :many
+SELECT * FROM webhooks
+ORDER BY id
+LIMIT $1 OFFSET $2;
+
+-- name: CountWebhooks :one
+SELECT COUNT(*) FROM webhooks;
+
+-- name: UpdateWebhook :one
+-- Enabling a webhook clears its failures.
+UPDATE webhooks
+SET url = @url,
+    event_types = @event_types,
+    active = @active::boolean,
+    failure_count = CASE WHEN @active::boolean AND NOT active THEN 0 ELSE failure_count END,
+    disabled_at = CASE WHEN @active::boolean THEN NULL ELSE disabled_at END
+WHERE id = @id
+RETURNING *;
+
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks
+WHERE id = $1;
+
+-- name: RecordWebhookSuccess :exec
+UPDATE webhooks
+SET failure_count = 0
+WHERE id = $1 AND failure_count > 0;
+
+-- name: RecordWebhookFailure :one
+-- Counts a failed delivery attempt and disables the webhook once
+-- failure_count reaches disable_after.
+UPDATE webhooks
+SET failure_count = failure_count + 1,
+    active = active AND failure_count + 1 < @disable_after::int,
+    disabled_at = CASE
+        WHEN active AND failure_count + 1 >= @disable_after::int THEN CURRENT_TIMESTAMP
+        ELSE disabled_at
+    END
+WHERE id = @id
+RETURNING *;
+
+-- name: EnqueueWebhookDeliveries :execrows
+-- Queues an event for every active webhook subscribed to its type.
+INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+SELECT id, @event_id, @event_type, @payload
+FROM webhooks
+WHERE active AND @event_type::text = ANY(event_types);
+
+-- name: ClaimWebhookDelivery :one
+-- Marks the next due delivery of an active webhook as being delivered and
+-- counts the attempt. Deliveries stuck in delivering since stale_before,
+-- because their worker stopped, are taken again. SKIP LOCKED lets several
+-- workers take different deliveries.
+UPDATE webhook_deliveries
+SET status = 'delivering',
+    attempts = attempts + 1,
+    last_attempt_at = CURRENT_TIMESTAMP
+WHERE id = (
+    SELECT d.id FROM webhook_deliveries d
+    JOIN webhooks w ON d.webhook_id = w.id
+    WHERE w.active
+        AND ((d.status = 'pending' AND d.next_attempt_at <= CURRENT_TIMESTAMP)
+            OR (d.status = 'delivering' AND d.last_attempt_at < @stale_before))
+    ORDER BY d.next_attempt_at, d.id
+    LIMIT 1
+    FOR UPDATE OF d SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteWebhookDelivery :exec
+-- Records the outcome of an attempt. A delivery to retry is set back to
+-- pending with the time of its next attempt.
+UPDATE webhook_deliveries
+SET status = $2,
+    next_attempt_at = $3,
+    response_status = $4,
+    response_body = $5,
+    error = $6
+WHERE id = $1;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1 AND webhook_id = $2;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE webhook_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountWebhookDeliveries :one
+SELECT COUNT(*) FROM webhook_deliveries
+WHERE webhook_id = $1;
+
+-- name: RedeliverWebhookDelivery :one
+-- Queues a new delivery of the same event.
+INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+SELECT webhook_id, event_id, event_type, payload
+FROM webhook_deliveries
+WHERE id = $1
+RETURNING *;
\ No newline at end of file
@@ -0,0 +1,86 @@
+// Package events tells the rest of the application what handlers did,
+// such as notifying users, without the handlers knowing who listens.
+package events
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Event types.
+const (
+	CommentCreated  = "comment.created"
+	PostLiked       = "post.liked"
+	UserFollowed    = "user.followed"
+	PostPublished   = "post.published"
+	PostUpdated     = "post.updated"
+	PostUnpublished = "post.unpublished"
+	PostDeleted     = "post.deleted"
+	UserUpdated     = "user.updated"
+	UserDeleted     = "user.deleted"
+	UserRestored    = "user.restored"
+)
+
+// Event is something a user did. IDs that do not apply to the type are
+// zero.
+type Event struct {
+	Type string
+	// ActorID is the user who did it.
+	ActorID int32
+	// UserID is the user it was done to, such as the followed user.
+	UserID    int32
+	PostID    int32
+	CommentID int32
+	// Public is set when anyone may see the event, such as changes to
+	// published posts.
+	Public bool
+	// Data is the resource the event is about as the API returns it, when
+	// hooks forward it.
+	Data interface{}
+	At   time.Time
+}
+
+// Hook reacts to an event.
+type Hook func(ctx context.Context, event Event) error
+
+// Bus passes emitted events to every subscribed hook. A nil *Bus drops
+// events, so handlers work without one.
+type Bus struct {
+	mu    sync.RWMutex
+	hooks []Hook
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe adds a hook called for every event emitted afterwards.
+func (b *Bus) Subscribe(hook Hook) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.hooks = append(b.hooks, hook)
+}
+
+// Emit calls every hook in the order they subscribed. The action the event
+// describes has already happened, so hook errors are logged rather than
+// returned and do not stop later hooks.
+func (b *Bus) Emit(ctx context.Context, event Event) {
+	if b == nil {
+		return
+	}
+	if event.At.IsZero() {
+		event.At = time.Now()
+	}
+
+	b.mu.RLock()
+	hooks := b.hooks
+	b.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if err := hook(ctx, event); err != nil {
+			log.Printf("events: %s: %v", event.Type, err)
+		}
+	}
+}
\ No newline at end of file
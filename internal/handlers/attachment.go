@@ -0,0 +1,465 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/media"
+	"github.com/demo/demo-gin/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxAttachmentSize is the largest file accepted for upload.
+	maxAttachmentSize = 10 << 20
+
+	// thumbnailSize is the longest side of generated thumbnails in pixels.
+	thumbnailSize = 320
+)
+
+type AttachmentHandler struct {
+	db      *sql.DB
+	queries *db.Queries
+	storage storage.Storage
+}
+
+func NewAttachmentHandler(conn *sql.DB, store storage.Storage) *AttachmentHandler {
+	return &AttachmentHandler{db: conn, queries: db.New(conn), storage: store}
+}
+
+// Upload godoc
+// @Summary Upload attachment
+// @Description Upload a file to a post as multipart form field "file". The type is detected from the file contents; JPEG, PNG, GIF, WebP and PDF up to 10 MiB are accepted, and images get a thumbnail. Only the owner and co-authors can upload to a post.
+// @Tags attachments
+// @Security Bearer
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param file formData file true "File to upload"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 413 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Router /posts/{id}/attachments [post]
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// Check the post before reading a potentially large body.
+	post, err := h.queries.GetPost(ctx, int32(postID))
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+
+	author, err := isPostAuthor(ctx, h.queries, post.Post.ID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+	if !author {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the authors can add attachments"})
+		return
+	}
+
+	// Leave room for the multipart framing around the file itself.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxAttachmentSize+1<<20)
+	fileHeader, err := c.FormFile("file")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File exceeds the 10 MiB limit"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+	if fileHeader.Size > maxAttachmentSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File exceeds the 10 MiB limit"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+	defer file.Close()
+
+	attachment, status, err := h.store(c, file, fileHeader, post.Post.ID, userID)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Attachment uploaded successfully",
+		"data":    attachmentResponse(baseURL(c, "/posts/"), attachment),
+	})
+}
+
+// store validates an uploaded file, writes it and its thumbnail to storage
+// and records the attachment. On failure it returns the HTTP status and a
+// client-facing error.
+func (h *AttachmentHandler) store(c *gin.Context, file multipart.File, fileHeader *multipart.FileHeader, postID, userID int32) (db.Attachment, int, error) {
+	ctx := c.Request.Context()
+
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(file, sniff)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return db.Attachment{}, http.StatusBadRequest, errors.New("File is empty")
+	}
+
+	contentType, ext, ok := media.Detect(sniff[:n])
+	if !ok {
+		return db.Attachment{}, http.StatusUnsupportedMediaType, errors.New("Unsupported file type")
+	}
+
+	key := "attachments/" + randomKey() + ext
+	params := db.CreateAttachmentParams{
+		PostID:      sql.NullInt32{Int32: postID, Valid: true},
+		UserID:      sql.NullInt32{Int32: userID, Valid: true},
+		StorageKey:  key,
+		Filename:    filepath.Base(fileHeader.Filename),
+		ContentType: contentType,
+		SizeBytes:   fileHeader.Size,
+	}
+
+	if media.IsImage(contentType) {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return db.Attachment{}, http.StatusInternalServerError, errors.New("Failed to read file")
+		}
+
+		thumb, width, height, err := media.Thumbnail(file, thumbnailSize)
+		if errors.Is(err, media.ErrImageTooLarge) {
+			return db.Attachment{}, http.StatusRequestEntityTooLarge, errors.New("Image dimensions too large")
+		}
+		if err != nil {
+			return db.Attachment{}, http.StatusBadRequest, errors.New("Invalid image")
+		}
+
+		thumbKey := "attachments/" + randomKey() + "_thumb.jpg"
+		if err := h.storage.Put(ctx, thumbKey, bytes.NewReader(thumb), int64(len(thumb)), "image/jpeg"); err != nil {
+			return db.Attachment{}, http.StatusInternalServerError, errors.New("Failed to store file")
+		}
+
+		params.ThumbnailKey = sql.NullString{String: thumbKey, Valid: true}
+		params.Width = sql.NullInt32{Int32: int32(width), Valid: true}
+		params.Height = sql.NullInt32{Int32: int32(height), Valid: true}
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		h.removeFiles(c, params.StorageKey, params.ThumbnailKey)
+		return db.Attachment{}, http.StatusInternalServerError, errors.New("Failed to read file")
+	}
+	if err := h.storage.Put(ctx, key, file, fileHeader.Size, contentType); err != nil {
+		h.removeFiles(c, params.StorageKey, params.ThumbnailKey)
+		return db.Attachment{}, http.StatusInternalServerError, errors.New("Failed to store file")
+	}
+
+	attachment, err := h.queries.CreateAttachment(ctx, params)
+	if err != nil {
+		h.removeFiles(c, params.StorageKey, params.ThumbnailKey)
+		return db.Attachment{}, http.StatusInternalServerError, errors.New("Failed to save attachment")
+	}
+	return attachment, http.StatusCreated, nil
+}
+
+// List godoc
+// @Summary List attachments
+// @Description Get the attachments of a post, oldest first
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/attachments [get]
+func (h *AttachmentHandler) List(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	post, err := h.queries.GetPost(ctx, int32(postID))
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+
+	// Attachments are as visible as their post.
+	visible, err := canReadPost(c, h.queries, post.Post)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+	if !visible {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	rows, err := h.queries.ListPostAttachments(ctx, sql.NullInt32{Int32: int32(postID), Valid: true})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attachments"})
+		return
+	}
+
+	base := baseURL(c, "/posts/")
+	attachments := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		attachments = append(attachments, attachmentResponse(base, row))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attachments": attachments})
+}
+
+// File godoc
+// @Summary Download attachment
+// @Description Stream the stored file of an attachment
+// @Tags attachments
+// @Produce octet-stream
+// @Param id path int true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]interface{}
+// @Router /attachments/{id}/file [get]
+func (h *AttachmentHandler) File(c *gin.Context) {
+	h.serve(c, false)
+}
+
+// Thumbnail godoc
+// @Summary Download thumbnail
+// @Description Stream the JPEG thumbnail of an image attachment
+// @Tags attachments
+// @Produce jpeg
+// @Param id path int true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]interface{}
+// @Router /attachments/{id}/thumbnail [get]
+func (h *AttachmentHandler) Thumbnail(c *gin.Context) {
+	h.serve(c, true)
+}
+
+// serve streams an attachment or its thumbnail from storage to callers who
+// can see its post. Stored objects never change, so they may be cached
+// indefinitely.
+func (h *AttachmentHandler) serve(c *gin.Context, thumbnail bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	attachment, err := h.queries.GetAttachment(ctx, int32(id))
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !attachment.PostID.Valid) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attachment"})
+		return
+	}
+
+	post, err := h.queries.GetPost(ctx, attachment.PostID.Int32)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attachment"})
+		return
+	}
+
+	visible, err := canReadPost(c, h.queries, post.Post)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attachment"})
+		return
+	}
+	if !visible {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+
+	key, contentType, size := attachment.StorageKey, attachment.ContentType, attachment.SizeBytes
+	if thumbnail {
+		if !attachment.ThumbnailKey.Valid {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Attachment has no thumbnail"})
+			return
+		}
+		key, contentType, size = attachment.ThumbnailKey.String, "image/jpeg", -1
+	}
+
+	reader, err := h.storage.Open(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read attachment"})
+		return
+	}
+	defer reader.Close()
+
+	headers := map[string]string{
+		"Cache-Control":          "public, max-age=31536000, immutable",
+		"X-Content-Type-Options": "nosniff",
+	}
+	// Files of posts only their authors can see must not end up in shared
+	// caches.
+	if post.Post.Status.String == "draft" || post.Post.Status.String == "hidden" {
+		headers["Cache-Control"] = "private, max-age=31536000, immutable"
+	}
+	if !media.IsImage(contentType) {
+		headers["Content-Disposition"] = "attachment; filename=" + strconv.Quote(attachment.Filename)
+	}
+
+	c.DataFromReader(http.StatusOK, size, contentType, reader, headers)
+}
+
+// Delete godoc
+// @Summary Delete attachment
+// @Description Delete an attachment and its stored files. Allowed for the uploader and the post's authors.
+// @Tags attachments
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Attachment ID"
+// @Success 204
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /attachments/{id} [delete]
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	attachment, err := h.queries.GetAttachment(ctx, int32(id))
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !attachment.PostID.Valid) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attachment"})
+		return
+	}
+
+	if !attachment.UserID.Valid || attachment.UserID.Int32 != userID {
+		author, err := isPostAuthor(ctx, h.queries, attachment.PostID.Int32, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+			return
+		}
+		if !author {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can only delete your own attachments"})
+			return
+		}
+	}
+
+	// Remove the files first so a storage failure leaves the row in place
+	// and the delete can be retried.
+	if err := deleteAttachmentFiles(c, h.storage, attachment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete attachment"})
+		return
+	}
+
+	if err := h.queries.DeleteAttachment(ctx, attachment.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete attachment"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// removeFiles deletes files stored for an upload that could not be
+// completed. Errors are ignored; the upload already failed.
+func (h *AttachmentHandler) removeFiles(c *gin.Context, key string, thumbKey sql.NullString) {
+	_ = deleteAttachmentFiles(c, h.storage, db.Attachment{StorageKey: key, ThumbnailKey: thumbKey})
+}
+
+// deleteAttachmentFiles removes the stored file and thumbnail of an
+// attachment.
+func deleteAttachmentFiles(c *gin.Context, store storage.Storage, attachment db.Attachment) error {
+	ctx := c.Request.Context()
+	if attachment.ThumbnailKey.Valid {
+		if err := store.Delete(ctx, attachment.ThumbnailKey.String); err != nil {
+			return err
+		}
+	}
+	return store.Delete(ctx, attachment.StorageKey)
+}
+
+// randomKey returns a random hex string used to name stored files, so keys
+// are neither guessable nor derived from client input.
+func randomKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// attachmentResponse converts an attachment row into its JSON
+// representation with download URLs relative to the API base.
+func attachmentResponse(base string, a db.Attachment) gin.H {
+	fileURL := base + "/attachments/" + strconv.Itoa(int(a.ID)) + "/file"
+
+	response := gin.H{
+		"id":            a.ID,
+		"post_id":       nullInt32(a.PostID),
+		"user_id":       nullInt32(a.UserID),
+		"filename":      a.Filename,
+		"content_type":  a.ContentType,
+		"size_bytes":    a.SizeBytes,
+		"width":         nullInt32(a.Width),
+		"height":        nullInt32(a.Height),
+		"url":           fileURL,
+		"thumbnail_url": nil,
+		"created_at":    nullTime(a.CreatedAt),
+	}
+	if a.ThumbnailKey.Valid {
+		response["thumbnail_url"] = base + "/attachments/" + strconv.Itoa(int(a.ID)) + "/thumbnail"
+	}
+	return response
+}
\ No newline at end of file
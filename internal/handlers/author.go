@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// AuthorHandler manages the authors of a post. The owner is the user who
+// created the post; co-authors can edit it but not delete it or manage its
+// authors.
+type AuthorHandler struct {
+	db      *sql.DB
+	queries *db.Queries
+}
+
+func NewAuthorHandler(conn *sql.DB) *AuthorHandler {
+	return &AuthorHandler{db: conn, queries: db.New(conn)}
+}
+
+// List godoc
+// @Summary List post authors
+// @Description Get the authors of a post, owner first, then co-authors in the order they were added
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/authors [get]
+func (h *AuthorHandler) List(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if _, err := h.queries.GetPost(ctx, int32(id)); errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+
+	authors, err := h.queries.ListPostAuthors(ctx, int32(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch authors"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": authorsResponse(authors)})
+}
+
+// Add godoc
+// @Summary Add co-author
+// @Description Add a user as co-author of your post. Co-authors can edit the post but not delete it or manage its authors. Adding an existing author has no further effect.
+// @Tags posts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param user_id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/authors/{user_id} [put]
+func (h *AuthorHandler) Add(c *gin.Context) {
+	postID, authorID, userID, ok := h.authorParams(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if !h.ownedPost(c, postID, userID) {
+		return
+	}
+
+	if _, err := h.queries.GetUser(ctx, authorID); errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return
+	}
+
+	if _, err := h.queries.AddPostCoAuthor(ctx, db.AddPostCoAuthorParams{PostID: postID, UserID: authorID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add author"})
+		return
+	}
+
+	authors, err := h.queries.ListPostAuthors(ctx, postID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch authors"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": authorsResponse(authors)})
+}
+
+// Remove godoc
+// @Summary Remove co-author
+// @Description Remove a co-author from your post, or step down as co-author of someone else's post. The owner cannot be removed.
+// @Tags posts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param user_id path int true "User ID"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/authors/{user_id} [delete]
+func (h *AuthorHandler) Remove(c *gin.Context) {
+	postID, authorID, userID, ok := h.authorParams(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	row, err := h.queries.GetPost(ctx, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+
+	if row.Post.UserID != userID && authorID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the owner can manage authors"})
+		return
+	}
+
+	if authorID == row.Post.UserID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The owner cannot be removed"})
+		return
+	}
+
+	removed, err := h.queries.RemovePostCoAuthor(ctx, db.RemovePostCoAuthorParams{PostID: postID, UserID: authorID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove author"})
+		return
+	}
+	if removed == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Author not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// authorParams parses the post and user IDs of an author route and the
+// caller's ID. It writes the error response and returns false when one is
+// missing or invalid.
+func (h *AuthorHandler) authorParams(c *gin.Context) (postID, authorID, userID int32, ok bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return 0, 0, 0, false
+	}
+
+	author, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return 0, 0, 0, false
+	}
+
+	userID, ok = activeUserID(c, h.queries)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	return int32(id), int32(author), userID, true
+}
+
+// ownedPost checks that a post exists and the caller owns it. It writes the
+// error response and returns false otherwise.
+func (h *AuthorHandler) ownedPost(c *gin.Context, postID, userID int32) bool {
+	row, err := h.queries.GetPost(c.Request.Context(), postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return false
+	}
+
+	if row.Post.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the owner can manage authors"})
+		return false
+	}
+	return true
+}
+
+// isPostAuthor reports whether a user is the owner or a co-author of a
+// post.
+func isPostAuthor(ctx context.Context, queries *db.Queries, postID, userID int32) (bool, error) {
+	_, err := queries.GetPostAuthorRole(ctx, db.GetPostAuthorRoleParams{PostID: postID, UserID: userID})
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// canReadPost applies the visibility rule of single posts to the caller:
+// drafts and posts hidden by a moderator are visible to their authors only,
+// and posts by authors who blocked the caller look like they do not exist.
+func canReadPost(c *gin.Context, queries *db.Queries, p db.Post) (bool, error) {
+	ctx := c.Request.Context()
+	userID, authenticated := currentUserID(c)
+
+	if p.Status.String == "draft" || p.Status.String == "hidden" {
+		if !authenticated {
+			return false, nil
+		}
+		return isPostAuthor(ctx, queries, p.ID, userID)
+	}
+
+	if authenticated {
+		blocked, err := blockedByAny(ctx, queries, userID, p.UserID)
+		return !blocked, err
+	}
+	return true, nil
+}
+
+// hasAuthor reports whether a user is among a post's authors.
+func hasAuthor(authors []db.ListPostAuthorsRow, userID int32) bool {
+	for _, a := range authors {
+		if a.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// authorsResponse converts a post's authors into their JSON representation.
+func authorsResponse(authors []db.ListPostAuthorsRow) []gin.H {
+	response := make([]gin.H, 0, len(authors))
+	for _, a := range authors {
+		response = append(response, gin.H{
+			"user_id":   a.UserID,
+			"username":  a.Username,
+			"full_name": nullString(a.FullName),
+			"role":      a.Role,
+			"added_at":  nullTime(a.CreatedAt),
+		})
+	}
+	return response
+}
\ No newline at end of file
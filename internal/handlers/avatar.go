@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/media"
+	"github.com/demo/demo-gin/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxAvatarSize is the largest image accepted as an avatar.
+	maxAvatarSize = 5 << 20
+
+	// defaultAvatarSize is served when no size is requested.
+	defaultAvatarSize = 128
+)
+
+type AvatarHandler struct {
+	db      *sql.DB
+	queries *db.Queries
+	storage storage.Storage
+}
+
+func NewAvatarHandler(conn *sql.DB, store storage.Storage) *AvatarHandler {
+	return &AvatarHandler{db: conn, queries: db.New(conn), storage: store}
+}
+
+// Upload godoc
+// @Summary Upload avatar
+// @Description Replace your own avatar with an image sent as multipart form field "file". JPEG, PNG, GIF and WebP images up to 5 MiB are accepted; the image is cropped to a centred square and resized to 64, 128 and 256 pixels.
+// @Tags users
+// @Security Bearer
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "User ID"
+// @Param file formData file true "Avatar image"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 413 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Router /users/{id}/avatar [put]
+func (h *AvatarHandler) Upload(c *gin.Context) {
+	id, ok := h.ownAccount(c)
+	if !ok {
+		return
+	}
+
+	// Leave room for the multipart framing around the file itself.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxAvatarSize+1<<20)
+	fileHeader, err := c.FormFile("file")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) || (err == nil && fileHeader.Size > maxAvatarSize) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Avatar exceeds the 5 MiB limit"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+	defer file.Close()
+
+	sniff := make([]byte, 512)
+	n, _ := io.ReadFull(file, sniff)
+	contentType, _, ok := media.Detect(sniff[:n])
+	if !ok || !media.IsImage(contentType) {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Avatar must be a JPEG, PNG, GIF or WebP image"})
+		return
+	}
+
+	if _, ok := activeUser(c, h.queries); !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// Resize everything before storing anything so an invalid image leaves
+	// no files behind.
+	images := make(map[int][]byte, len(media.AvatarSizes))
+	for _, size := range media.AvatarSizes {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file"})
+			return
+		}
+		data, err := media.Avatar(file, size)
+		if errors.Is(err, media.ErrImageTooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Image dimensions too large"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid image"})
+			return
+		}
+		images[size] = data
+	}
+
+	key := "avatars/" + randomKey()
+	for _, size := range media.AvatarSizes {
+		data := images[size]
+		if err := h.storage.Put(ctx, media.AvatarKey(key, size), bytes.NewReader(data), int64(len(data)), "image/jpeg"); err != nil {
+			h.removeFiles(c, key)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store avatar"})
+			return
+		}
+	}
+
+	user, previous, err := h.replace(c, id, sql.NullString{String: key, Valid: true})
+	if err != nil {
+		h.removeFiles(c, key)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update avatar"})
+		return
+	}
+
+	if previous.Valid {
+		h.removeFiles(c, previous.String)
+	}
+
+	c.Header("ETag", etag(user.Version))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Avatar updated successfully",
+		"data":    userResponse(baseURL(c, "/users/"), user),
+	})
+}
+
+// Get godoc
+// @Summary Get avatar
+// @Description Get a user's avatar as a square JPEG
+// @Tags users
+// @Produce jpeg
+// @Param id path int true "User ID"
+// @Param size query int false "Size in pixels: 64, 128 or 256" default(128)
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {file} file
+// @Success 304
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /users/{id}/avatar [get]
+func (h *AvatarHandler) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	size, err := strconv.Atoi(c.DefaultQuery("size", strconv.Itoa(defaultAvatarSize)))
+	if err != nil || !slices.Contains(media.AvatarSizes, size) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid size, must be one of 64, 128, 256"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	user, err := h.queries.GetUser(ctx, int32(id))
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !user.AvatarKey.Valid) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Avatar not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return
+	}
+
+	// Every upload gets a new key, so the key identifies the image.
+	sum := sha256.Sum256([]byte(media.AvatarKey(user.AvatarKey.String, size)))
+	tag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	c.Header("ETag", tag)
+	c.Header("Cache-Control", "public, max-age=3600")
+	if matchETag(c.GetHeader("If-None-Match"), tag, true) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	reader, err := h.storage.Open(ctx, media.AvatarKey(user.AvatarKey.String, size))
+	if errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Avatar not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read avatar"})
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(http.StatusOK, -1, "image/jpeg", reader, map[string]string{
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// Delete godoc
+// @Summary Delete avatar
+// @Description Remove your own avatar
+// @Tags users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /users/{id}/avatar [delete]
+func (h *AvatarHandler) Delete(c *gin.Context) {
+	id, ok := h.ownAccount(c)
+	if !ok {
+		return
+	}
+
+	if _, ok := activeUser(c, h.queries); !ok {
+		return
+	}
+
+	_, previous, err := h.replace(c, id, sql.NullString{})
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete avatar"})
+		return
+	}
+
+	if previous.Valid {
+		h.removeFiles(c, previous.String)
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ownAccount parses the user ID and checks that it is the caller's own
+// account. It writes the error response and returns false otherwise.
+func (h *AvatarHandler) ownAccount(c *gin.Context) (int32, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return 0, false
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return 0, false
+	}
+
+	if int32(id) != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only update your own account"})
+		return 0, false
+	}
+	return userID, true
+}
+
+// replace sets the avatar key of a user and returns the updated user with
+// the key it replaced. The row is locked so concurrent uploads cannot lose
+// track of a previous avatar's files.
+func (h *AvatarHandler) replace(c *gin.Context, id int32, key sql.NullString) (db.User, sql.NullString, error) {
+	ctx := c.Request.Context()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return db.User{}, sql.NullString{}, err
+	}
+	defer tx.Rollback()
+
+	qtx := h.queries.WithTx(tx)
+
+	current, err := qtx.GetUserForUpdate(ctx, id)
+	if err != nil {
+		return db.User{}, sql.NullString{}, err
+	}
+
+	user, err := qtx.UpdateUserAvatar(ctx, db.UpdateUserAvatarParams{AvatarKey: key, ID: id})
+	if err != nil {
+		return db.User{}, sql.NullString{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return db.User{}, sql.NullString{}, err
+	}
+	return user, current.AvatarKey, nil
+}
+
+// removeFiles deletes all sizes stored under an avatar key. Errors are
+// ignored; leftover files are unreachable once the key is replaced.
+func (h *AvatarHandler) removeFiles(c *gin.Context, key string) {
+	for _, size := range media.AvatarSizes {
+		_ = h.storage.Delete(c.Request.Context(), media.AvatarKey(key, size))
+	}
+}
+
+// avatarURLs returns the URL of every avatar size keyed by size, or nil when
+// the user has no avatar.
+func avatarURLs(base string, u db.User) interface{} {
+	if !u.AvatarKey.Valid {
+		return nil
+	}
+
+	urls := make(gin.H, len(media.AvatarSizes))
+	for _, size := range media.AvatarSizes {
+		urls[strconv.Itoa(size)] = base + "/users/" + strconv.Itoa(int(u.ID)) + "/avatar?size=" + strconv.Itoa(size)
+	}
+	return urls
+}
\ No newline at end of file
@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+type BlockHandler struct {
+	db      *sql.DB
+	queries *db.Queries
+}
+
+func NewBlockHandler(conn *sql.DB) *BlockHandler {
+	return &BlockHandler{db: conn, queries: db.New(conn)}
+}
+
+// Block godoc
+// @Summary Block user
+// @Description Block a user. They can no longer follow you or comment on, like or bookmark your posts, your posts are hidden from them, and any follows between you are removed. Blocking twice has no further effect.
+// @Tags users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /users/{id}/block [put]
+func (h *BlockHandler) Block(c *gin.Context) {
+	h.toggle(c, "blocked", true, func(ctx context.Context, userID, targetID int32) error {
+		tx, err := h.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		qtx := h.queries.WithTx(tx)
+		if err := qtx.BlockUser(ctx, db.BlockUserParams{BlockerID: userID, BlockedID: targetID}); err != nil {
+			return err
+		}
+		if err := qtx.DeleteFollowsBetween(ctx, db.DeleteFollowsBetweenParams{UserID: userID, OtherID: targetID}); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// Unblock godoc
+// @Summary Unblock user
+// @Description Remove a block. Follows removed by the block are not restored. Unblocking twice has no further effect.
+// @Tags users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /users/{id}/block [delete]
+func (h *BlockHandler) Unblock(c *gin.Context) {
+	h.toggle(c, "blocked", false, func(ctx context.Context, userID, targetID int32) error {
+		return h.queries.UnblockUser(ctx, db.UnblockUserParams{BlockerID: userID, BlockedID: targetID})
+	})
+}
+
+// Mute godoc
+// @Summary Mute user
+// @Description Mute a user so their posts no longer appear in your post list and home feed. They are not notified and can still interact with you. Muting twice has no further effect.
+// @Tags users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /users/{id}/mute [put]
+func (h *BlockHandler) Mute(c *gin.Context) {
+	h.toggle(c, "muted", true, func(ctx context.Context, userID, targetID int32) error {
+		return h.queries.MuteUser(ctx, db.MuteUserParams{MuterID: userID, MutedID: targetID})
+	})
+}
+
+// Unmute godoc
+// @Summary Unmute user
+// @Description Remove a mute. Unmuting twice has no further effect.
+// @Tags users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /users/{id}/mute [delete]
+func (h *BlockHandler) Unmute(c *gin.Context) {
+	h.toggle(c, "muted", false, func(ctx context.Context, userID, targetID int32) error {
+		return h.queries.UnmuteUser(ctx, db.UnmuteUserParams{MuterID: userID, MutedID: targetID})
+	})
+}
+
+// toggle applies an idempotent block or mute change and responds with the
+// caller's resulting state.
+func (h *BlockHandler) toggle(c *gin.Context, field string, on bool, apply func(ctx context.Context, userID, targetID int32) error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	if int32(id) == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot block or mute yourself"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	user, err := h.queries.GetUser(ctx, int32(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return
+	}
+
+	if err := apply(ctx, userID, user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"user_id": user.ID,
+			field:     on,
+		},
+	})
+}
+
+// ListBlocks godoc
+// @Summary List blocked users
+// @Description Get the users you have blocked, most recent first
+// @Tags users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /users/me/blocks [get]
+func (h *BlockHandler) ListBlocks(c *gin.Context) {
+	h.list(c, "blocked_at", func(ctx context.Context, userID, limit, offset int32) ([]db.User, []sql.NullTime, int64, error) {
+		rows, err := h.queries.ListBlockedUsers(ctx, db.ListBlockedUsersParams{BlockerID: userID, Limit: limit, Offset: offset})
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		total, err := h.queries.CountBlockedUsers(ctx, userID)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+
+		users := make([]db.User, 0, len(rows))
+		since := make([]sql.NullTime, 0, len(rows))
+		for _, row := range rows {
+			users = append(users, row.User)
+			since = append(since, row.BlockedAt)
+		}
+		return users, since, total, nil
+	})
+}
+
+// ListMutes godoc
+// @Summary List muted users
+// @Description Get the users you have muted, most recent first
+// @Tags users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /users/me/mutes [get]
+func (h *BlockHandler) ListMutes(c *gin.Context) {
+	h.list(c, "muted_at", func(ctx context.Context, userID, limit, offset int32) ([]db.User, []sql.NullTime, int64, error) {
+		rows, err := h.queries.ListMutedUsers(ctx, db.ListMutedUsersParams{MuterID: userID, Limit: limit, Offset: offset})
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		total, err := h.queries.CountMutedUsers(ctx, userID)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+
+		users := make([]db.User, 0, len(rows))
+		since := make([]sql.NullTime, 0, len(rows))
+		for _, row := range rows {
+			users = append(users, row.User)
+			since = append(since, row.MutedAt)
+		}
+		return users, since, total, nil
+	})
+}
+
+// list responds with one page of the caller's blocked or muted users as
+// public profiles, each with the time it was added under field.
+func (h *BlockHandler) list(c *gin.Context, field string, fetch func(ctx context.Context, userID, limit, offset int32) ([]db.User, []sql.NullTime, int64, error)) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+
+	users, since, total, err := fetch(c.Request.Context(), userID, int32(limit), int32(offset))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+		return
+	}
+
+	base := baseURL(c, "/users/")
+	profiles := make([]gin.H, 0, len(users))
+	for i, user := range users {
+		profile := profileResponse(base, user)
+		profile[field] = nullTime(since[i])
+		profiles = append(profiles, profile)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users": profiles,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (int(total) + limit - 1) / limit,
+			"offset":      offset,
+		},
+	})
+}
+
+// blockedByAny reports whether any of the given users has blocked userID.
+func blockedByAny(ctx context.Context, queries *db.Queries, userID int32, blockerIDs ...int32) (bool, error) {
+	for _, blockerID := range blockerIDs {
+		if blockerID == userID {
+			continue
+		}
+		blocked, err := queries.IsBlocked(ctx, db.IsBlockedParams{BlockerID: blockerID, BlockedID: userID})
+		if err != nil || blocked {
+			return blocked, err
+		}
+	}
+	return false, nil
+}
\ No newline at end of file
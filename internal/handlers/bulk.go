@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/events"
+	"github.com/demo/demo-gin/internal/transfer"
+	"github.com/gin-gonic/gin"
+)
+
+// Bulk modes: atomic applies all operations in one transaction and rolls
+// them all back when one fails, per_item commits each operation on its own.
+const (
+	bulkModeAtomic  = "atomic"
+	bulkModePerItem = "per_item"
+)
+
+// bulkStatuses maps the status-changing bulk operations to the status they
+// set.
+var bulkStatuses = map[string]string{
+	"publish":   "published",
+	"unpublish": "draft",
+	"archive":   "archived",
+}
+
+// BulkPostsRequest lists operations on posts, applied in order.
+type BulkPostsRequest struct {
+	Mode       string              `json:"mode" binding:"omitempty,oneof=atomic per_item"`
+	Operations []BulkPostOperation `json:"operations" binding:"required,min=1,max=100,dive"`
+}
+
+// BulkPostOperation is one operation of a bulk request. IfMatch works like
+// the If-Match header of the single-post endpoints. Tags replaces the tags
+// of the post in retag operations, and is not allowed in others.
+type BulkPostOperation struct {
+	Op      string   `json:"op" binding:"required,oneof=publish unpublish archive delete retag"`
+	ID      int32    `json:"id" binding:"required,min=1"`
+	IfMatch string   `json:"if_match"`
+	Tags    []string `json:"tags" binding:"required_if=Op retag,excluded_unless=Op retag,max=20,dive,max=50"`
+}
+
+// bulkResult is the outcome of one bulk operation, with the status code the
+// single-post endpoint would have responded with.
+type bulkResult struct {
+	status int
+	post   *db.Post
+	// tags are the tags of the post after a retag.
+	tags []string
+	err  string
+	// event is emitted once the operation is committed.
+	event *events.Event
+}
+
+// Bulk godoc
+// @Summary Apply operations to many posts
+// @Description Publish, unpublish, archive, retag or delete up to 100 posts at once, with the same authorization and checks as the single-post endpoints: status changes and retagging are open to the owner and co-authors, deletes to the owner only. Retagging replaces the tags of a post with the given list; an empty list removes them all. In atomic mode (the default) the operations run in one transaction and stop at the first failure; the other operations are then not applied and are reported with 424. In per_item mode each operation is committed on its own. Each result carries the status code of its operation.
+// @Tags posts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body BulkPostsRequest true "Operations"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /posts/bulk [post]
+func (h *PostHandler) Bulk(c *gin.Context) {
+	var req BulkPostsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = bulkModeAtomic
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	var results []bulkResult
+	if req.Mode == bulkModePerItem {
+		results = h.bulkPerItem(ctx, userID, req.Operations)
+	} else {
+		results = h.bulkAtomic(ctx, userID, req.Operations)
+	}
+
+	succeeded := 0
+	data := make([]gin.H, 0, len(results))
+	for i, result := range results {
+		op := req.Operations[i]
+		item := gin.H{"index": i, "op": op.Op, "id": op.ID, "status": result.status}
+		if result.status < http.StatusBadRequest {
+			succeeded++
+		}
+		if result.post != nil {
+			post := postResponse(*result.post)
+			post["etag"] = etag(result.post.Version)
+			if result.tags != nil {
+				post["tags"] = result.tags
+			}
+			item["data"] = post
+		}
+		if result.err != "" {
+			item["error"] = result.err
+		}
+		data = append(data, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":      req.Mode,
+		"succeeded": succeeded,
+		"failed":    len(results) - succeeded,
+		"results":   data,
+	})
+}
+
+// bulkAtomic applies operations in one transaction, stopping at the first
+// failure.
+func (h *PostHandler) bulkAtomic(ctx context.Context, userID int32, ops []BulkPostOperation) []bulkResult {
+	results := make([]bulkResult, len(ops))
+	fail := func(index int, result bulkResult) []bulkResult {
+		for i := range results {
+			if i != index {
+				results[i] = bulkResult{
+					status: http.StatusFailedDependency,
+					err:    "Not applied because operation " + strconv.Itoa(index) + " failed",
+				}
+			}
+		}
+		results[index] = result
+		return results
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fail(0, bulkResult{status: http.StatusInternalServerError, err: "Failed to update posts"})
+	}
+	defer tx.Rollback()
+
+	qtx := h.queries.WithTx(tx)
+
+	for i, op := range ops {
+		result := applyBulkOperation(ctx, qtx, userID, op)
+		if result.status >= http.StatusBadRequest {
+			return fail(i, result)
+		}
+		results[i] = result
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fail(len(ops)-1, bulkResult{status: http.StatusInternalServerError, err: "Failed to update posts"})
+	}
+	for _, result := range results {
+		if result.event != nil {
+			h.bus.Emit(ctx, *result.event)
+		}
+	}
+	return results
+}
+
+// bulkPerItem applies each operation in its own transaction.
+func (h *PostHandler) bulkPerItem(ctx context.Context, userID int32, ops []BulkPostOperation) []bulkResult {
+	results := make([]bulkResult, 0, len(ops))
+	for _, op := range ops {
+		results = append(results, h.bulkItem(ctx, userID, op))
+	}
+	return results
+}
+
+// bulkItem applies and commits a single operation.
+func (h *PostHandler) bulkItem(ctx context.Context, userID int32, op BulkPostOperation) bulkResult {
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return bulkResult{status: http.StatusInternalServerError, err: "Failed to update post"}
+	}
+	defer tx.Rollback()
+
+	result := applyBulkOperation(ctx, h.queries.WithTx(tx), userID, op)
+	if result.status >= http.StatusBadRequest {
+		return result
+	}
+
+	if err := tx.Commit(); err != nil {
+		return bulkResult{status: http.StatusInternalServerError, err: "Failed to update post"}
+	}
+	if result.event != nil {
+		h.bus.Emit(ctx, *result.event)
+	}
+	return result
+}
+
+// applyBulkOperation runs one operation with the checks of the matching
+// single-post endpoint: Update for status changes and retagging, and Delete
+// for deletes.
+func applyBulkOperation(ctx context.Context, qtx *db.Queries, userID int32, op BulkPostOperation) bulkResult {
+	current, err := qtx.GetPostForUpdate(ctx, op.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return bulkResult{status: http.StatusNotFound, err: "Post not found"}
+	}
+	if err != nil {
+		return bulkResult{status: http.StatusInternalServerError, err: "Failed to fetch post"}
+	}
+
+	if op.Op == "delete" {
+		if current.UserID != userID {
+			return bulkResult{status: http.StatusForbidden, err: "Only the owner can delete this post"}
+		}
+		if op.IfMatch != "" && !matchVersion(op.IfMatch, current.Version) {
+			return bulkResult{status: http.StatusPreconditionFailed, err: "Post has been modified"}
+		}
+		if _, err := qtx.TrashPost(ctx, db.TrashPostParams{ID: current.ID}); err != nil {
+			return bulkResult{status: http.StatusInternalServerError, err: "Failed to delete post"}
+		}
+		return bulkResult{status: http.StatusNoContent, event: postEvent(userID, &current, nil)}
+	}
+
+	author, err := isPostAuthor(ctx, qtx, current.ID, userID)
+	if err != nil {
+		return bulkResult{status: http.StatusInternalServerError, err: "Failed to fetch post"}
+	}
+	if !author {
+		return bulkResult{status: http.StatusForbidden, err: "Only the authors can update this post"}
+	}
+	if current.Status.String == "hidden" {
+		return bulkResult{status: http.StatusForbidden, err: "Post has been hidden by a moderator"}
+	}
+	if op.IfMatch != "" && !matchVersion(op.IfMatch, current.Version) {
+		return bulkResult{status: http.StatusPreconditionFailed, err: "Post has been modified"}
+	}
+
+	if op.Op == "retag" {
+		return retagPost(ctx, qtx, userID, current, op.Tags)
+	}
+
+	// A status change is a PUT of the current post with the new status.
+	req := UpdatePostRequest{
+		Title:         current.Title,
+		ContentFormat: current.ContentFormat,
+		Status:        bulkStatuses[op.Op],
+	}
+	if current.Content.Valid {
+		req.Content = &current.Content.String
+	}
+
+	params, err := updatePostParams(current.ID, current.Slug, req)
+	if err != nil {
+		return bulkResult{status: http.StatusInternalServerError, err: "Failed to render content"}
+	}
+
+	post, err := qtx.UpdatePost(ctx, params)
+	if err != nil {
+		return bulkResult{status: http.StatusInternalServerError, err: "Failed to update post"}
+	}
+
+	// The saved post supersedes the editor's autosave, as with a PUT.
+	if _, err := qtx.DeletePostDraft(ctx, db.DeletePostDraftParams{PostID: post.ID, UserID: userID}); err != nil {
+		return bulkResult{status: http.StatusInternalServerError, err: "Failed to update post"}
+	}
+	return bulkResult{status: http.StatusOK, post: &post, event: postEvent(userID, &current, &post)}
+}
+
+// retagPost replaces the tags of a post. Tags are compared
+// case-insensitively, so duplicates differing only in case are dropped.
+func retagPost(ctx context.Context, qtx *db.Queries, userID int32, current db.Post, tags []string) bulkResult {
+	if err := qtx.DeletePostTags(ctx, current.ID); err != nil {
+		return bulkResult{status: http.StatusInternalServerError, err: "Failed to update tags"}
+	}
+	kept, _ := transfer.NormalizeTags(tags)
+	if kept == nil {
+		kept = []string{}
+	}
+	for _, tag := range kept {
+		if err := qtx.AddPostTag(ctx, db.AddPostTagParams{PostID: current.ID, Tag: tag}); err != nil {
+			return bulkResult{status: http.StatusInternalServerError, err: "Failed to update tags"}
+		}
+	}
+
+	// Tags are part of the post, so its version changes with them.
+	post, err := qtx.TouchPost(ctx, current.ID)
+	if err != nil {
+		return bulkResult{status: http.StatusInternalServerError, err: "Failed to update post"}
+	}
+	return bulkResult{status: http.StatusOK, post: &post, tags: kept, event: postEvent(userID, &current, &post)}
+}
\ No newline at end of file
@@ -0,0 +1,606 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/events"
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct {
+	db      *sql.DB
+	queries *db.Queries
+	bus     *events.Bus
+}
+
+func NewCommentHandler(conn *sql.DB, bus *events.Bus) *CommentHandler {
+	return &CommentHandler{db: conn, queries: db.New(conn), bus: bus}
+}
+
+type CreateCommentRequest struct {
+	Content  string `json:"content" binding:"required,max=10000"`
+	ParentID *int32 `json:"parent_id"`
+}
+
+type UpdateCommentRequest struct {
+	Content string `json:"content" binding:"required,max=10000"`
+}
+
+// List godoc
+// @Summary List comments
+// @Description Get the comments of a post, either as threads or as a flat list. Comments by users who blocked you are left out, together with the replies below them.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param view query string false "threaded or flat" default(threaded)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/comments [get]
+func (h *CommentHandler) List(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	view := c.DefaultQuery("view", "threaded")
+	if view != "threaded" && view != "flat" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid view, must be threaded or flat"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	offset := (page - 1) * limit
+	ctx := c.Request.Context()
+
+	post, err := h.queries.GetPost(ctx, int32(postID))
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+
+	// Comments are as visible as their post.
+	visible, err := canReadPost(c, h.queries, post.Post)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+	if !visible {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	userID, authenticated := currentUserID(c)
+	viewer := sql.NullInt32{Int32: userID, Valid: authenticated}
+
+	var comments []gin.H
+	var total int64
+	if view == "flat" {
+		comments, total, err = h.listFlat(ctx, int32(postID), viewer, int32(limit), int32(offset))
+	} else {
+		comments, total, err = h.listThreaded(ctx, int32(postID), viewer, int32(limit), int32(offset))
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": comments,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (int(total) + limit - 1) / limit,
+			"offset":      offset,
+		},
+	})
+}
+
+// listFlat pages through all comments of a post in creation order.
+func (h *CommentHandler) listFlat(ctx context.Context, postID int32, viewer sql.NullInt32, limit, offset int32) ([]gin.H, int64, error) {
+	rows, err := h.queries.ListComments(ctx, db.ListCommentsParams{
+		PostID:   postID,
+		ViewerID: viewer,
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := h.queries.CountComments(ctx, db.CountCommentsParams{PostID: postID, ViewerID: viewer})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	comments := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		comments = append(comments, commentRowResponse(row.Comment, row.Username, row.AuthorDeletedAt))
+	}
+	return comments, total, nil
+}
+
+// listThreaded pages through top-level comments and nests every reply
+// below its parent, so a page always contains complete threads.
+func (h *CommentHandler) listThreaded(ctx context.Context, postID int32, viewer sql.NullInt32, limit, offset int32) ([]gin.H, int64, error) {
+	rows, err := h.queries.ListRootComments(ctx, db.ListRootCommentsParams{
+		PostID:   postID,
+		ViewerID: viewer,
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := h.queries.CountRootComments(ctx, db.CountRootCommentsParams{PostID: postID, ViewerID: viewer})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	nodes := make(map[int32]gin.H, len(rows))
+	comments := make([]gin.H, 0, len(rows))
+	rootIDs := make([]int32, 0, len(rows))
+	for _, row := range rows {
+		node := commentRowResponse(row.Comment, row.Username, row.AuthorDeletedAt)
+		node["replies"] = []gin.H{}
+		nodes[row.Comment.ID] = node
+		comments = append(comments, node)
+		rootIDs = append(rootIDs, row.Comment.ID)
+	}
+
+	if len(rootIDs) == 0 {
+		return comments, total, nil
+	}
+
+	replies, err := h.queries.ListCommentReplies(ctx, db.ListCommentRepliesParams{RootIds: rootIDs, ViewerID: viewer})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Replies are ordered by creation time, so a parent is always
+	// registered before any of its children. Replies below a comment left
+	// out for the viewer have no parent to attach to and go with it.
+	for _, reply := range replies {
+		node := commentRowResponse(reply.Comment, reply.Username, reply.AuthorDeletedAt)
+		node["replies"] = []gin.H{}
+		nodes[reply.Comment.ID] = node
+
+		if parent, ok := nodes[reply.Comment.ParentID.Int32]; ok {
+			parent["replies"] = append(parent["replies"].([]gin.H), node)
+		}
+	}
+
+	return comments, total, nil
+}
+
+// Create godoc
+// @Summary Create a comment
+// @Description Comment on a published post or reply to another comment. Not allowed if the post author or the parent comment author has blocked you.
+// @Tags comments
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param request body CreateCommentRequest true "Comment details"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/comments [post]
+func (h *CommentHandler) Create(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	var req CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	post, err := h.queries.GetPost(ctx, int32(postID))
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+
+	if post.Post.Status.String != "published" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Comments are only allowed on published posts"})
+		return
+	}
+	if post.Post.CommentsClosed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Comments are closed for this post"})
+		return
+	}
+
+	// The post author and the author of the parent comment can each block
+	// the commenter.
+	authors := []int32{post.Post.UserID}
+
+	var parentID sql.NullInt32
+	if req.ParentID != nil {
+		parent, err := h.queries.GetComment(ctx, *req.ParentID)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && parent.PostID != post.Post.ID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parent comment not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch parent comment"})
+			return
+		}
+		if parent.DeletedAt.Valid {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot reply to a deleted comment"})
+			return
+		}
+		parentID = sql.NullInt32{Int32: parent.ID, Valid: true}
+		authors = append(authors, parent.UserID)
+	}
+
+	blocked, err := blockedByAny(ctx, h.queries, userID, authors...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
+		return
+	}
+	if blocked {
+		c.JSON(http.StatusForbidden, gin.H{"error": "The author has blocked you"})
+		return
+	}
+
+	comment, err := h.queries.CreateComment(ctx, db.CreateCommentParams{
+		PostID:   post.Post.ID,
+		UserID:   userID,
+		ParentID: parentID,
+		Content:  req.Content,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
+		return
+	}
+
+	h.bus.Emit(ctx, events.Event{
+		Type:      events.CommentCreated,
+		ActorID:   userID,
+		UserID:    post.Post.UserID,
+		PostID:    post.Post.ID,
+		CommentID: comment.ID,
+	})
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Comment created successfully",
+		"data":    commentResponse(comment),
+	})
+}
+
+// Update godoc
+// @Summary Update comment
+// @Description Edit the content of your own comment
+// @Tags comments
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Comment ID"
+// @Param request body UpdateCommentRequest true "Comment update details"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /comments/{id} [put]
+func (h *CommentHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	var req UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	comment, err := h.queries.GetComment(ctx, int32(id))
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && comment.DeletedAt.Valid) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comment"})
+		return
+	}
+
+	if comment.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own comments"})
+		return
+	}
+
+	comment, err = h.queries.UpdateComment(ctx, db.UpdateCommentParams{
+		ID:      comment.ID,
+		Content: req.Content,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Comment updated successfully",
+		"data":    commentResponse(comment),
+	})
+}
+
+// Delete godoc
+// @Summary Delete comment
+// @Description Delete a comment. Comments with replies are kept as a tombstone.
+// @Tags comments
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Comment ID"
+// @Success 204
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /comments/{id} [delete]
+func (h *CommentHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	comment, err := h.queries.GetComment(ctx, int32(id))
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && comment.DeletedAt.Valid) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comment"})
+		return
+	}
+
+	// Post authors may remove any comment on their own posts.
+	if comment.UserID != userID {
+		post, err := h.queries.GetPost(ctx, comment.PostID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+			return
+		}
+		if post.Post.UserID != userID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can only delete your own comments"})
+			return
+		}
+	}
+
+	if err := h.removeComment(ctx, comment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// removeComment turns a comment with replies into a tombstone and deletes
+// it outright otherwise. Tombstoned ancestors left without any replies are
+// deleted as well.
+func (h *CommentHandler) removeComment(ctx context.Context, comment db.Comment) error {
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := h.queries.WithTx(tx)
+
+	replies, err := qtx.CountCommentReplies(ctx, sql.NullInt32{Int32: comment.ID, Valid: true})
+	if err != nil {
+		return err
+	}
+
+	if replies > 0 {
+		if err := qtx.SoftDeleteComment(ctx, comment.ID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	if err := qtx.DeleteComment(ctx, comment.ID); err != nil {
+		return err
+	}
+
+	parentID := comment.ParentID
+	for parentID.Valid {
+		parent, err := qtx.GetComment(ctx, parentID.Int32)
+		if err != nil {
+			return err
+		}
+		if !parent.DeletedAt.Valid {
+			break
+		}
+
+		replies, err := qtx.CountCommentReplies(ctx, parentID)
+		if err != nil {
+			return err
+		}
+		if replies > 0 {
+			break
+		}
+
+		if err := qtx.DeleteComment(ctx, parent.ID); err != nil {
+			return err
+		}
+		parentID = parent.ParentID
+	}
+
+	return tx.Commit()
+}
+
+// Close godoc
+// @Summary Close comments
+// @Description Stop accepting new comments on a post
+// @Tags comments
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/comments/close [post]
+func (h *CommentHandler) Close(c *gin.Context) {
+	h.setCommentsClosed(c, true)
+}
+
+// Open godoc
+// @Summary Open comments
+// @Description Accept new comments on a post again
+// @Tags comments
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/comments/open [post]
+func (h *CommentHandler) Open(c *gin.Context) {
+	h.setCommentsClosed(c, false)
+}
+
+func (h *CommentHandler) setCommentsClosed(c *gin.Context, closed bool) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	post, err := h.queries.GetPost(ctx, int32(postID))
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+
+	if post.Post.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the post author can change comment settings"})
+		return
+	}
+
+	updated, err := h.queries.SetPostCommentsClosed(ctx, db.SetPostCommentsClosedParams{
+		ID:             post.Post.ID,
+		CommentsClosed: closed,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update post"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Comment settings updated successfully",
+		"data": gin.H{
+			"id":              updated.ID,
+			"comments_closed": updated.CommentsClosed,
+		},
+	})
+}
+
+// commentResponse converts a comment into its JSON representation. Deleted
+// comments are rendered as tombstones without author or content.
+func commentResponse(comment db.Comment) gin.H {
+	data := gin.H{
+		"id":         comment.ID,
+		"post_id":    comment.PostID,
+		"parent_id":  nullInt32(comment.ParentID),
+		"user_id":    comment.UserID,
+		"content":    comment.Content,
+		"deleted":    false,
+		"created_at": nullTime(comment.CreatedAt),
+		"updated_at": nullTime(comment.UpdatedAt),
+	}
+
+	if comment.DeletedAt.Valid {
+		data["user_id"] = nil
+		data["content"] = nil
+		data["deleted"] = true
+	}
+	return data
+}
+
+// commentRowResponse is commentResponse with the author's username attached.
+// Comments by a deleted account are rendered as tombstones until the account
+// is restored or removed for good.
+func commentRowResponse(comment db.Comment, username string, authorDeletedAt sql.NullTime) gin.H {
+	if !comment.DeletedAt.Valid && authorDeletedAt.Valid {
+		comment.DeletedAt = authorDeletedAt
+	}
+	data := commentResponse(comment)
+	data["username"] = username
+	if comment.DeletedAt.Valid {
+		data["username"] = nil
+	}
+	return data
+}
\ No newline at end of file
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// encodeCursor returns an opaque keyset cursor pointing just after the row
+// with the given sort time and ID.
+func encodeCursor(t time.Time, id int32) string {
+	raw := strconv.FormatInt(t.UnixNano(), 10) + ":" + strconv.Itoa(int(id))
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor parses a cursor produced by encodeCursor.
+func decodeCursor(cursor string) (time.Time, int32, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, errInvalidCursor
+	}
+
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return time.Time{}, 0, errInvalidCursor
+	}
+
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, errInvalidCursor
+	}
+	i, err := strconv.ParseInt(id, 10, 32)
+	if err != nil {
+		return time.Time{}, 0, errInvalidCursor
+	}
+	return time.Unix(0, n).UTC(), int32(i), nil
+}
\ No newline at end of file
@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/signing"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultPreviewTTL is how long a preview link works unless asked
+// otherwise.
+const defaultPreviewTTL = 24 * time.Hour
+
+// DraftHandler keeps autosaved edits of posts and serves private previews
+// of unpublished posts through signed links.
+type DraftHandler struct {
+	db            *sql.DB
+	queries       *db.Queries
+	signer        *signing.Signer
+	maxPreviewTTL time.Duration
+}
+
+// NewDraftHandler creates a DraftHandler. Preview links are disabled when
+// signer is nil.
+func NewDraftHandler(conn *sql.DB, signer *signing.Signer, maxPreviewTTL time.Duration) *DraftHandler {
+	return &DraftHandler{db: conn, queries: db.New(conn), signer: signer, maxPreviewTTL: maxPreviewTTL}
+}
+
+// AutosaveRequest is a snapshot of a post being edited. Unlike a post it
+// may be incomplete. BaseVersion is the version of the post the edits
+// started from.
+type AutosaveRequest struct {
+	Title         string `json:"title" binding:"max=255"`
+	Content       string `json:"content"`
+	ContentFormat string `json:"content_format" binding:"omitempty,oneof=plain markdown"`
+	BaseVersion   int32  `json:"base_version" binding:"required,min=1"`
+}
+
+// PreviewLinkRequest sets how long a preview link works.
+type PreviewLinkRequest struct {
+	ExpiresIn int `json:"expires_in" binding:"omitempty,min=60"`
+}
+
+// GetAutosave godoc
+// @Summary Get your autosave of a post
+// @Description Get the edits you autosaved for a post. stale is true when the post was saved since the edits started, so restoring them would overwrite newer changes.
+// @Tags posts
+// @Security Bearer
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/autosave [get]
+func (h *DraftHandler) GetAutosave(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	post, ok := h.authoredPost(c, int32(id), userID)
+	if !ok {
+		return
+	}
+
+	draft, err := h.queries.GetPostDraft(c.Request.Context(), db.GetPostDraftParams{PostID: post.ID, UserID: userID})
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No autosave for this post"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch autosave"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": draftResponse(draft, post)})
+}
+
+// Autosave godoc
+// @Summary Autosave edits of a post
+// @Description Store a snapshot of your unsaved edits, replacing your previous one. The post itself, its version and its ETag are left unchanged; the snapshot is discarded when you save the post. Only the owner and co-authors can autosave.
+// @Tags posts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param request body AutosaveRequest true "Snapshot"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/autosave [put]
+func (h *DraftHandler) Autosave(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	var req AutosaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	post, ok := h.authoredPost(c, int32(id), userID)
+	if !ok {
+		return
+	}
+	if post.Status.String == "hidden" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Post has been hidden by a moderator"})
+		return
+	}
+
+	if req.ContentFormat == "" {
+		req.ContentFormat = post.ContentFormat
+	}
+
+	draft, err := h.queries.SavePostDraft(c.Request.Context(), db.SavePostDraftParams{
+		PostID:        post.ID,
+		UserID:        userID,
+		Title:         req.Title,
+		Content:       req.Content,
+		ContentFormat: req.ContentFormat,
+		BaseVersion:   req.BaseVersion,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save autosave"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": draftResponse(draft, post)})
+}
+
+// DeleteAutosave godoc
+// @Summary Discard your autosave of a post
+// @Tags posts
+// @Security Bearer
+// @Param id path int true "Post ID"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/autosave [delete]
+func (h *DraftHandler) DeleteAutosave(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.queries.DeletePostDraft(c.Request.Context(), db.DeletePostDraftParams{PostID: int32(id), UserID: userID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete autosave"})
+		return
+	}
+	if deleted == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No autosave for this post"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CreatePreviewLink godoc
+// @Summary Create a preview link
+// @Description Create a link that lets anyone holding it read the post without signing in, whatever its status, until the link expires. Links cannot be revoked, so keep their lifetime short. Only the owner and co-authors can create preview links.
+// @Tags posts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param request body PreviewLinkRequest false "Lifetime in seconds, 24 hours by default"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /posts/{id}/preview-links [post]
+func (h *DraftHandler) CreatePreviewLink(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	var req PreviewLinkRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ttl := defaultPreviewTTL
+	if req.ExpiresIn > 0 {
+		ttl = time.Duration(req.ExpiresIn) * time.Second
+	}
+	if h.maxPreviewTTL > 0 && ttl > h.maxPreviewTTL {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_in must be at most " + strconv.Itoa(int(h.maxPreviewTTL.Seconds())) + " seconds"})
+		return
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	if h.signer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Preview links are not configured"})
+		return
+	}
+
+	post, ok := h.authoredPost(c, int32(id), userID)
+	if !ok {
+		return
+	}
+
+	expires := time.Now().Add(ttl).Truncate(time.Second)
+	query := "expires=" + strconv.FormatInt(expires.Unix(), 10) + "&signature=" + h.signer.Sign(previewResource(post.ID), expires)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Preview link created successfully",
+		"data": gin.H{
+			"url":        baseURL(c, "/posts/") + "/posts/" + strconv.Itoa(int(post.ID)) + "/preview?" + query,
+			"expires_at": expires,
+		},
+	})
+}
+
+// Preview godoc
+// @Summary Preview a post
+// @Description Read a post through a preview link, without signing in and whatever its status. Posts hidden by a moderator cannot be previewed.
+// @Tags posts
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param expires query int true "Expiry time of the link, in Unix seconds"
+// @Param signature query string true "Signature of the link"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 410 {object} map[string]interface{}
+// @Router /posts/{id}/preview [get]
+func (h *DraftHandler) Preview(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	unix, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expires"})
+		return
+	}
+
+	if h.signer == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid preview link"})
+		return
+	}
+
+	err = h.signer.Verify(previewResource(int32(id)), time.Unix(unix, 0), c.Query("signature"), time.Now())
+	if errors.Is(err, signing.ErrExpired) {
+		c.JSON(http.StatusGone, gin.H{"error": "Preview link has expired"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid preview link"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	row, err := h.queries.GetPost(ctx, int32(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+	if row.Post.Status.String == "hidden" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	authors, err := h.queries.ListPostAuthors(ctx, row.Post.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+
+	tags, err := h.queries.ListPostTags(ctx, row.Post.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+
+	post := postResponse(row.Post)
+	post["username"] = row.Username
+	post["comment_count"] = row.CommentCount
+	post["authors"] = authorsResponse(authors)
+	post["tags"] = tags
+
+	// The link is the credential: keep the page out of caches and search
+	// engines.
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("X-Robots-Tag", "noindex")
+	c.JSON(http.StatusOK, gin.H{"data": post})
+}
+
+// authoredPost loads a post the user is an author of. It writes the error
+// response and returns false otherwise.
+func (h *DraftHandler) authoredPost(c *gin.Context, id, userID int32) (db.Post, bool) {
+	ctx := c.Request.Context()
+
+	row, err := h.queries.GetPost(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return db.Post{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return db.Post{}, false
+	}
+
+	author, err := isPostAuthor(ctx, h.queries, id, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return db.Post{}, false
+	}
+	if !author {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the authors can edit this post"})
+		return db.Post{}, false
+	}
+	return row.Post, true
+}
+
+// previewResource names a post in preview link signatures.
+func previewResource(postID int32) string {
+	return "post-preview:" + strconv.Itoa(int(postID))
+}
+
+// draftResponse converts an autosave into its JSON representation.
+func draftResponse(d db.PostDraft, post db.Post) gin.H {
+	return gin.H{
+		"post_id":        d.PostID,
+		"title":          d.Title,
+		"content":        d.Content,
+		"content_format": d.ContentFormat,
+		"base_version":   d.BaseVersion,
+		"post_version":   post.Version,
+		"stale":          d.BaseVersion < post.Version,
+		"saved_at":       nullTime(d.SavedAt),
+	}
+}
\ No newline at end of file
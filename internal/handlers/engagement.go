@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/events"
+	"github.com/gin-gonic/gin"
+)
+
+type EngagementHandler struct {
+	db      *sql.DB
+	queries *db.Queries
+	bus     *events.Bus
+}
+
+func NewEngagementHandler(conn *sql.DB, bus *events.Bus) *EngagementHandler {
+	return &EngagementHandler{db: conn, queries: db.New(conn), bus: bus}
+}
+
+// Like godoc
+// @Summary Like post
+// @Description Like a published post. Liking twice has no further effect.
+// @Tags posts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/like [put]
+func (h *EngagementHandler) Like(c *gin.Context) {
+	h.toggle(c, "liked", true, func(ctx context.Context, post db.Post, userID int32) error {
+		liked, err := h.queries.LikePost(ctx, db.LikePostParams{PostID: post.ID, UserID: userID})
+		if err != nil {
+			return err
+		}
+		if liked > 0 {
+			h.bus.Emit(ctx, events.Event{Type: events.PostLiked, ActorID: userID, UserID: post.UserID, PostID: post.ID})
+		}
+		return nil
+	})
+}
+
+// Unlike godoc
+// @Summary Unlike post
+// @Description Remove your like from a post. Unliking twice has no further effect.
+// @Tags posts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/like [delete]
+func (h *EngagementHandler) Unlike(c *gin.Context) {
+	h.toggle(c, "liked", false, func(ctx context.Context, post db.Post, userID int32) error {
+		return h.queries.UnlikePost(ctx, db.UnlikePostParams{PostID: post.ID, UserID: userID})
+	})
+}
+
+// Bookmark godoc
+// @Summary Bookmark post
+// @Description Bookmark a published post. Bookmarking twice has no further effect.
+// @Tags posts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/bookmark [put]
+func (h *EngagementHandler) Bookmark(c *gin.Context) {
+	h.toggle(c, "bookmarked", true, func(ctx context.Context, post db.Post, userID int32) error {
+		return h.queries.BookmarkPost(ctx, db.BookmarkPostParams{PostID: post.ID, UserID: userID})
+	})
+}
+
+// Unbookmark godoc
+// @Summary Remove bookmark
+// @Description Remove a post from your bookmarks. Removing twice has no further effect.
+// @Tags posts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/bookmark [delete]
+func (h *EngagementHandler) Unbookmark(c *gin.Context) {
+	h.toggle(c, "bookmarked", false, func(ctx context.Context, post db.Post, userID int32) error {
+		return h.queries.UnbookmarkPost(ctx, db.UnbookmarkPostParams{PostID: post.ID, UserID: userID})
+	})
+}
+
+// toggle applies an idempotent like/bookmark change and responds with the
+// caller's resulting state and the post's current counters.
+func (h *EngagementHandler) toggle(c *gin.Context, field string, on bool, apply func(ctx context.Context, post db.Post, userID int32) error) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	post, err := h.queries.GetPost(ctx, int32(postID))
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+
+	// Removing a like or bookmark is always allowed so readers can clean up
+	// after a post is unpublished.
+	if on && post.Post.Status.String != "published" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only published posts can be liked or bookmarked"})
+		return
+	}
+
+	if on {
+		blocked, err := blockedByAny(ctx, h.queries, userID, post.Post.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+			return
+		}
+		if blocked {
+			c.JSON(http.StatusForbidden, gin.H{"error": "The author has blocked you"})
+			return
+		}
+	}
+
+	if err := apply(ctx, post.Post, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update post"})
+		return
+	}
+
+	counters, err := h.queries.GetPostCounters(ctx, post.Post.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"post_id":        post.Post.ID,
+			field:            on,
+			"like_count":     counters.LikeCount,
+			"bookmark_count": counters.BookmarkCount,
+		},
+	})
+}
+
+// ListBookmarks godoc
+// @Summary List bookmarks
+// @Description Get the published posts bookmarked by the current user, most recent first
+// @Tags posts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /users/me/bookmarks [get]
+func (h *EngagementHandler) ListBookmarks(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+	ctx := c.Request.Context()
+
+	rows, err := h.queries.ListBookmarkedPosts(ctx, db.ListBookmarkedPostsParams{
+		UserID: userID,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bookmarks"})
+		return
+	}
+
+	total, err := h.queries.CountBookmarkedPosts(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count bookmarks"})
+		return
+	}
+
+	posts := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		post := postResponse(row.Post)
+		post["username"] = row.Username
+		post["comment_count"] = row.CommentCount
+		post["bookmarked_at"] = nullTime(row.BookmarkedAt)
+		posts = append(posts, post)
+	}
+
+	if err := attachReactions(ctx, h.queries, userID, true, posts); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bookmarks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"posts": posts,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (int(total) + limit - 1) / limit,
+			"offset":      offset,
+		},
+	})
+}
+
+// attachReactions sets liked_by_me and bookmarked_by_me on every post with
+// a single query, so list endpoints stay free of N+1 lookups. Anonymous
+// callers get false for both.
+func attachReactions(ctx context.Context, queries *db.Queries, userID int32, authenticated bool, posts []gin.H) error {
+	for _, post := range posts {
+		post["liked_by_me"] = false
+		post["bookmarked_by_me"] = false
+	}
+
+	if !authenticated || len(posts) == 0 {
+		return nil
+	}
+
+	byID := make(map[int32]gin.H, len(posts))
+	ids := make([]int32, 0, len(posts))
+	for _, post := range posts {
+		id := post["id"].(int32)
+		byID[id] = post
+		ids = append(ids, id)
+	}
+
+	reactions, err := queries.ListPostReactions(ctx, db.ListPostReactionsParams{
+		UserID:  userID,
+		PostIds: ids,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, reaction := range reactions {
+		byID[reaction.ID]["liked_by_me"] = reaction.Liked
+		byID[reaction.ID]["bookmarked_by_me"] = reaction.Bookmarked
+	}
+	return nil
+}
\ No newline at end of file
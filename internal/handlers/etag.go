@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// etag formats a row version as a strong entity tag.
+func etag(version int32) string {
+	return `"` + strconv.Itoa(int(version)) + `"`
+}
+
+// readETag formats the weak entity tag of a read. Counters and per-viewer
+// flags in the response change without an edit bumping the version, so
+// they are part of the tag: a 304 would otherwise keep them stale.
+func readETag(version int32, volatile ...interface{}) string {
+	var tag strings.Builder
+	tag.WriteString(`W/"` + strconv.Itoa(int(version)))
+	for _, value := range volatile {
+		fmt.Fprintf(&tag, "-%v", value)
+	}
+	tag.WriteString(`"`)
+	return tag.String()
+}
+
+// digest condenses parts of a response that are read from other rows, such
+// as a post's co-authors, into a short value for readETag.
+func digest(values ...interface{}) string {
+	h := fnv.New32a()
+	fmt.Fprint(h, values...)
+	return strconv.FormatUint(uint64(h.Sum32()), 36)
+}
+
+// ifMatch reports whether the request carries an If-Match header and, if so,
+// whether it matches the given version.
+func ifMatch(c *gin.Context, version int32) (present, matched bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		return false, false
+	}
+	return true, matchVersion(header, version)
+}
+
+// matchVersion checks a comma-separated list of entity tags against a row
+// version. Tags of reads also cover counters and per-viewer flags, which
+// change without the edits If-Match guards against, so only the version a
+// tag starts with is compared.
+func matchVersion(header string, version int32) bool {
+	current := strconv.Itoa(int(version))
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" {
+			return true
+		}
+		tagVersion, _, _ := strings.Cut(strings.Trim(tag, `"`), "-")
+		if tagVersion == current {
+			return true
+		}
+	}
+	return false
+}
+
+// ifNoneMatch reports whether the If-None-Match header matches the given
+// version, meaning the client's cached copy is still current.
+func ifNoneMatch(c *gin.Context, version int32) bool {
+	return ifNoneMatchTag(c, etag(version))
+}
+
+// ifNoneMatchTag is ifNoneMatch for a formatted entity tag.
+func ifNoneMatchTag(c *gin.Context, tag string) bool {
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+	return matchETag(header, strings.TrimPrefix(tag, "W/"), true)
+}
+
+// matchETag checks a comma-separated list of entity tags against current.
+func matchETag(header, current string, weak bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		if strings.HasPrefix(tag, "W/") {
+			if !weak {
+				continue
+			}
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+		if tag == current {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
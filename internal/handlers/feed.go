@@ -0,0 +1,432 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/render"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// feedSize is the number of most recent posts in a feed.
+	feedSize = 20
+
+	// feedCacheTTL is how long a feed is served from memory before the
+	// database is queried again. Feed readers polling more often than this
+	// never reach the database.
+	feedCacheTTL = time.Minute
+
+	// maxFeedTagLength matches the tag column.
+	maxFeedTagLength = 50
+)
+
+type FeedHandler struct {
+	db      *sql.DB
+	queries *db.Queries
+
+	mu    sync.Mutex
+	cache map[string]cachedFeed
+}
+
+// cachedFeed holds the posts of one feed together with its validators.
+type cachedFeed struct {
+	title        string
+	posts        []db.ListPostsRow
+	version      string
+	lastModified time.Time
+	expires      time.Time
+}
+
+func NewFeedHandler(conn *sql.DB) *FeedHandler {
+	return &FeedHandler{db: conn, queries: db.New(conn), cache: map[string]cachedFeed{}}
+}
+
+// RSS godoc
+// @Summary Posts RSS feed
+// @Description RSS 2.0 feed of the most recent published posts, optionally limited to one author or tag. Honors If-None-Match and If-Modified-Since.
+// @Tags feeds
+// @Produce xml
+// @Param id path int false "Author user ID"
+// @Param tag path string false "Tag"
+// @Success 200 {string} string
+// @Success 304
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /feeds/posts.rss [get]
+// @Router /feeds/users/{id}/posts.rss [get]
+// @Router /feeds/tags/{tag}/posts.rss [get]
+func (h *FeedHandler) RSS(c *gin.Context) {
+	h.serve(c, "rss", "application/rss+xml; charset=utf-8", rssFeed)
+}
+
+// Atom godoc
+// @Summary Posts Atom feed
+// @Description Atom feed of the most recent published posts, optionally limited to one author or tag. Honors If-None-Match and If-Modified-Since.
+// @Tags feeds
+// @Produce xml
+// @Param id path int false "Author user ID"
+// @Param tag path string false "Tag"
+// @Success 200 {string} string
+// @Success 304
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /feeds/posts.atom [get]
+// @Router /feeds/users/{id}/posts.atom [get]
+// @Router /feeds/tags/{tag}/posts.atom [get]
+func (h *FeedHandler) Atom(c *gin.Context) {
+	h.serve(c, "atom", "application/atom+xml; charset=utf-8", atomFeed)
+}
+
+// JSON godoc
+// @Summary Posts JSON feed
+// @Description JSON Feed 1.1 of the most recent published posts, optionally limited to one author or tag. Honors If-None-Match and If-Modified-Since.
+// @Tags feeds
+// @Produce json
+// @Param id path int false "Author user ID"
+// @Param tag path string false "Tag"
+// @Success 200 {object} map[string]interface{}
+// @Success 304
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /feeds/posts.json [get]
+// @Router /feeds/users/{id}/posts.json [get]
+// @Router /feeds/tags/{tag}/posts.json [get]
+func (h *FeedHandler) JSON(c *gin.Context) {
+	h.serve(c, "json", "application/feed+json; charset=utf-8", jsonFeed)
+}
+
+// serve loads a feed, answers conditional requests and otherwise renders the
+// feed with the given encoder.
+func (h *FeedHandler) serve(c *gin.Context, format, contentType string, encode func(feed cachedFeed, base, self string) ([]byte, error)) {
+	var scope feedScope
+	if param := c.Param("id"); param != "" {
+		id, err := strconv.Atoi(param)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		scope.authorID = sql.NullInt32{Int32: int32(id), Valid: true}
+	}
+	if param, ok := c.Params.Get("tag"); ok {
+		tag := strings.TrimSpace(param)
+		if tag == "" || utf8.RuneCountInString(tag) > maxFeedTagLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag"})
+			return
+		}
+		scope.tag = sql.NullString{String: tag, Valid: true}
+	}
+
+	feed, err := h.load(c.Request.Context(), scope)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+		return
+	}
+
+	tag := `"` + format + "-" + feed.version + `"`
+	c.Header("ETag", tag)
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(feedCacheTTL.Seconds())))
+	if !feed.lastModified.IsZero() {
+		c.Header("Last-Modified", feed.lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if feedNotModified(c, tag, feed.lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	base, self := feedURLs(c)
+	body, err := encode(feed, base, self)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render feed"})
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// feedScope limits a feed to the posts of one author or with one tag.
+type feedScope struct {
+	authorID sql.NullInt32
+	tag      sql.NullString
+}
+
+// load returns a feed from the cache, querying the database only once the
+// cached copy has expired.
+func (h *FeedHandler) load(ctx context.Context, scope feedScope) (cachedFeed, error) {
+	key := "all"
+	switch {
+	case scope.authorID.Valid:
+		key = "user:" + strconv.Itoa(int(scope.authorID.Int32))
+	case scope.tag.Valid:
+		key = "tag:" + strings.ToLower(scope.tag.String)
+	}
+
+	h.mu.Lock()
+	feed, ok := h.cache[key]
+	h.mu.Unlock()
+	if ok && time.Now().Before(feed.expires) {
+		return feed, nil
+	}
+
+	feed = cachedFeed{title: "Posts"}
+	if scope.authorID.Valid {
+		user, err := h.queries.GetUser(ctx, scope.authorID.Int32)
+		if err != nil {
+			return cachedFeed{}, err
+		}
+		feed.title = "Posts by " + user.Username
+	}
+	// Unknown tags have an empty feed rather than none, since a tag exists
+	// as soon as a post uses it.
+	if scope.tag.Valid {
+		feed.title = "Posts tagged " + scope.tag.String
+	}
+
+	posts, err := h.queries.ListPosts(ctx, db.ListPostsParams{
+		UserID: scope.authorID,
+		Tag:    scope.tag,
+		Limit:  feedSize,
+		Offset: 0,
+	})
+	if err != nil {
+		return cachedFeed{}, err
+	}
+
+	// The version changes whenever a post in the feed is added, edited or
+	// removed; the timestamp only tracks the newest change.
+	hash := sha256.New()
+	for _, row := range posts {
+		fmt.Fprintf(hash, "%d:%d;", row.Post.ID, row.Post.Version)
+		for _, t := range []sql.NullTime{row.Post.PublishedAt, row.Post.UpdatedAt} {
+			if t.Valid && t.Time.After(feed.lastModified) {
+				feed.lastModified = t.Time
+			}
+		}
+	}
+
+	feed.posts = posts
+	feed.version = hex.EncodeToString(hash.Sum(nil))[:16]
+	feed.expires = time.Now().Add(feedCacheTTL)
+
+	// Anyone can ask for feeds of made-up tags, so expired feeds are dropped
+	// rather than kept until they are asked for again.
+	h.mu.Lock()
+	for k, cached := range h.cache {
+		if !time.Now().Before(cached.expires) {
+			delete(h.cache, k)
+		}
+	}
+	h.cache[key] = feed
+	h.mu.Unlock()
+
+	return feed, nil
+}
+
+// feedNotModified evaluates If-None-Match, falling back to If-Modified-Since
+// only when no entity tag was sent.
+func feedNotModified(c *gin.Context, tag string, lastModified time.Time) bool {
+	if header := c.GetHeader("If-None-Match"); header != "" {
+		return matchETag(header, tag, true)
+	}
+
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil || lastModified.IsZero() {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// feedURLs returns the absolute API base URL and the URL of the requested
+// feed.
+func feedURLs(c *gin.Context) (base, self string) {
+	return baseURL(c, "/feeds/"), requestOrigin(c) + c.Request.URL.Path
+}
+
+// postURL is the permalink of a post in a feed.
+func postURL(base string, p db.Post) string {
+	return base + "/posts/by-slug/" + p.Slug
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate,omitempty"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func rssFeed(feed cachedFeed, base, self string) ([]byte, error) {
+	doc := rssDocument{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       feed.title,
+			Link:        self,
+			Description: feed.title,
+			Items:       []rssItem{},
+		},
+	}
+	if !feed.lastModified.IsZero() {
+		doc.Channel.LastBuildDate = feed.lastModified.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, row := range feed.posts {
+		item := rssItem{
+			Title:       row.Post.Title,
+			Link:        postURL(base, row.Post),
+			GUID:        rssGUID{IsPermaLink: false, Value: strconv.Itoa(int(row.Post.ID))},
+			Description: renderedContent(row.Post),
+		}
+		if row.Post.PublishedAt.Valid {
+			item.PubDate = row.Post.PublishedAt.Time.UTC().Format(time.RFC1123Z)
+		}
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+
+	return encodeXML(doc)
+}
+
+type atomDocument struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Updated   string     `xml:"updated"`
+	Published string     `xml:"published,omitempty"`
+	Link      atomLink   `xml:"link"`
+	Author    atomAuthor `xml:"author"`
+	Summary   string     `xml:"summary"`
+	Content   atomText   `xml:"content"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+func atomFeed(feed cachedFeed, base, self string) ([]byte, error) {
+	updated := feed.lastModified
+	if updated.IsZero() {
+		updated = time.Unix(0, 0)
+	}
+
+	doc := atomDocument{
+		ID:      self,
+		Title:   feed.title,
+		Updated: updated.UTC().Format(time.RFC3339),
+		Link:    atomLink{Href: self, Rel: "self"},
+	}
+
+	for _, row := range feed.posts {
+		link := postURL(base, row.Post)
+		entry := atomEntry{
+			ID:      link,
+			Title:   row.Post.Title,
+			Updated: latest(row.Post.UpdatedAt, row.Post.PublishedAt).UTC().Format(time.RFC3339),
+			Link:    atomLink{Href: link, Rel: "alternate"},
+			Author:  atomAuthor{Name: row.Username},
+			Summary: render.Excerpt(render.Text(renderedContent(row.Post)), excerptLength),
+			Content: atomText{Type: "html", Value: renderedContent(row.Post)},
+		}
+		if row.Post.PublishedAt.Valid {
+			entry.Published = row.Post.PublishedAt.Time.UTC().Format(time.RFC3339)
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return encodeXML(doc)
+}
+
+func jsonFeed(feed cachedFeed, base, self string) ([]byte, error) {
+	items := make([]gin.H, 0, len(feed.posts))
+	for _, row := range feed.posts {
+		item := gin.H{
+			"id":            strconv.Itoa(int(row.Post.ID)),
+			"url":           postURL(base, row.Post),
+			"title":         row.Post.Title,
+			"content_html":  renderedContent(row.Post),
+			"summary":       render.Excerpt(render.Text(renderedContent(row.Post)), excerptLength),
+			"date_modified": latest(row.Post.UpdatedAt, row.Post.PublishedAt).UTC().Format(time.RFC3339),
+			"authors":       []gin.H{{"name": row.Username}},
+		}
+		if row.Post.PublishedAt.Valid {
+			item["date_published"] = row.Post.PublishedAt.Time.UTC().Format(time.RFC3339)
+		}
+		items = append(items, item)
+	}
+
+	return json.Marshal(gin.H{
+		"version":  "https://jsonfeed.org/version/1.1",
+		"title":    feed.title,
+		"feed_url": self,
+		"items":    items,
+	})
+}
+
+func encodeXML(doc interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// latest returns the later of two nullable timestamps.
+func latest(a, b sql.NullTime) time.Time {
+	if !a.Valid || (b.Valid && b.Time.After(a.Time)) {
+		return b.Time
+	}
+	return a.Time
+}
\ No newline at end of file
@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/events"
+	"github.com/gin-gonic/gin"
+)
+
+// homeFeedLimit is the default page size of the home feed.
+const homeFeedLimit = 20
+
+type FollowHandler struct {
+	db      *sql.DB
+	queries *db.Queries
+	bus     *events.Bus
+}
+
+func NewFollowHandler(conn *sql.DB, bus *events.Bus) *FollowHandler {
+	return &FollowHandler{db: conn, queries: db.New(conn), bus: bus}
+}
+
+// Follow godoc
+// @Summary Follow user
+// @Description Follow an active user who has not blocked you. Following twice has no further effect.
+// @Tags users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /users/{id}/follow [put]
+func (h *FollowHandler) Follow(c *gin.Context) {
+	h.toggle(c, true, func(ctx context.Context, followerID, followeeID int32) error {
+		followed, err := h.queries.FollowUser(ctx, db.FollowUserParams{FollowerID: followerID, FolloweeID: followeeID})
+		if err != nil {
+			return err
+		}
+		if followed > 0 {
+			h.bus.Emit(ctx, events.Event{Type: events.UserFollowed, ActorID: followerID, UserID: followeeID})
+		}
+		return nil
+	})
+}
+
+// Unfollow godoc
+// @Summary Unfollow user
+// @Description Stop following a user. Unfollowing twice has no further effect.
+// @Tags users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /users/{id}/follow [delete]
+func (h *FollowHandler) Unfollow(c *gin.Context) {
+	h.toggle(c, false, func(ctx context.Context, followerID, followeeID int32) error {
+		return h.queries.UnfollowUser(ctx, db.UnfollowUserParams{FollowerID: followerID, FolloweeID: followeeID})
+	})
+}
+
+// toggle applies an idempotent follow change and responds with the caller's
+// resulting state and the followed user's current counts.
+func (h *FollowHandler) toggle(c *gin.Context, on bool, apply func(ctx context.Context, followerID, followeeID int32) error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	if int32(id) == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot follow yourself"})
+		return
+	}
+
+	if _, ok := activeUser(c, h.queries); !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	user, err := h.queries.GetUser(ctx, int32(id))
+	// Unfollowing is always allowed so followers can clean up after an
+	// account is deactivated.
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && on && isSuspended(user)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return
+	}
+
+	if on {
+		blocked, err := blockedByAny(ctx, h.queries, userID, user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+			return
+		}
+		if blocked {
+			c.JSON(http.StatusForbidden, gin.H{"error": "This user has blocked you"})
+			return
+		}
+	}
+
+	if err := apply(ctx, userID, user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update follow"})
+		return
+	}
+
+	counts, err := h.queries.GetFollowCounts(ctx, db.GetFollowCountsParams{
+		UserID:   user.ID,
+		ViewerID: sql.NullInt32{Int32: userID, Valid: true},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"user_id":         user.ID,
+			"following":       on,
+			"follower_count":  counts.FollowerCount,
+			"following_count": counts.FollowingCount,
+		},
+	})
+}
+
+// Followers godoc
+// @Summary List followers
+// @Description Get the users following a user, most recent first. Suspended accounts and users who blocked you are left out of the page and the total.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /users/{id}/followers [get]
+func (h *FollowHandler) Followers(c *gin.Context) {
+	h.list(c, func(ctx context.Context, userID int32, viewer sql.NullInt32, limit, offset int32) ([]db.User, []sql.NullTime, error) {
+		rows, err := h.queries.ListFollowers(ctx, db.ListFollowersParams{FolloweeID: userID, ViewerID: viewer, Limit: limit, Offset: offset})
+		if err != nil {
+			return nil, nil, err
+		}
+		users := make([]db.User, 0, len(rows))
+		followedAt := make([]sql.NullTime, 0, len(rows))
+		for _, row := range rows {
+			users = append(users, row.User)
+			followedAt = append(followedAt, row.FollowedAt)
+		}
+		return users, followedAt, nil
+	}, func(counts db.GetFollowCountsRow) int64 {
+		return counts.FollowerCount
+	})
+}
+
+// Following godoc
+// @Summary List followed users
+// @Description Get the users a user follows, most recent first. Suspended accounts and users who blocked you are left out of the page and the total.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /users/{id}/following [get]
+func (h *FollowHandler) Following(c *gin.Context) {
+	h.list(c, func(ctx context.Context, userID int32, viewer sql.NullInt32, limit, offset int32) ([]db.User, []sql.NullTime, error) {
+		rows, err := h.queries.ListFollowing(ctx, db.ListFollowingParams{FollowerID: userID, ViewerID: viewer, Limit: limit, Offset: offset})
+		if err != nil {
+			return nil, nil, err
+		}
+		users := make([]db.User, 0, len(rows))
+		followedAt := make([]sql.NullTime, 0, len(rows))
+		for _, row := range rows {
+			users = append(users, row.User)
+			followedAt = append(followedAt, row.FollowedAt)
+		}
+		return users, followedAt, nil
+	}, func(counts db.GetFollowCountsRow) int64 {
+		return counts.FollowingCount
+	})
+}
+
+// list responds with one page of a user's followers or followed users as
+// public profiles. Users who blocked the caller and suspended accounts are
+// left out of both the page and the total.
+func (h *FollowHandler) list(c *gin.Context, fetch func(ctx context.Context, userID int32, viewer sql.NullInt32, limit, offset int32) ([]db.User, []sql.NullTime, error), count func(db.GetFollowCountsRow) int64) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+	ctx := c.Request.Context()
+
+	if _, err := h.queries.GetUser(ctx, int32(id)); errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return
+	}
+
+	viewerID, authenticated := currentUserID(c)
+	viewer := sql.NullInt32{Int32: viewerID, Valid: authenticated}
+
+	users, followedAt, err := fetch(ctx, int32(id), viewer, int32(limit), int32(offset))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+		return
+	}
+
+	counts, err := h.queries.GetFollowCounts(ctx, db.GetFollowCountsParams{UserID: int32(id), ViewerID: viewer})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count users"})
+		return
+	}
+	total := count(counts)
+
+	base := baseURL(c, "/users/")
+	profiles := make([]gin.H, 0, len(users))
+	for i, user := range users {
+		profile := profileResponse(base, user)
+		profile["followed_at"] = nullTime(followedAt[i])
+		profiles = append(profiles, profile)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users": profiles,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (int(total) + limit - 1) / limit,
+			"offset":      offset,
+		},
+	})
+}
+
+// Home godoc
+// @Summary Home feed
+// @Description Get the published posts of the authors you follow, newest first, leaving out muted authors and authors who blocked you. Pass next_cursor from the previous page as cursor to continue.
+// @Tags posts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /feed/home [get]
+func (h *FollowHandler) Home(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(homeFeedLimit)))
+	if limit < 1 || limit > 100 {
+		limit = homeFeedLimit
+	}
+
+	params := db.ListHomeFeedParams{Limit: int32(limit)}
+	if cursor := c.Query("cursor"); cursor != "" {
+		publishedAt, id, err := decodeCursor(cursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+		params.BeforePublishedAt = sql.NullTime{Time: publishedAt, Valid: true}
+		params.BeforeID = sql.NullInt32{Int32: id, Valid: true}
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	params.FollowerID = userID
+
+	ctx := c.Request.Context()
+
+	rows, err := h.queries.ListHomeFeed(ctx, params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch feed"})
+		return
+	}
+
+	posts := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		post := postResponse(row.Post)
+		post["username"] = row.Username
+		post["comment_count"] = row.CommentCount
+		posts = append(posts, post)
+	}
+
+	if err := attachReactions(ctx, h.queries, userID, true, posts); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch feed"})
+		return
+	}
+
+	var next interface{}
+	if len(rows) == limit {
+		last := rows[len(rows)-1].Post
+		next = encodeCursor(last.PublishedAt.Time, last.ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"posts":       posts,
+		"next_cursor": next,
+	})
+}
\ No newline at end of file
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/listquery"
+	"github.com/gin-gonic/gin"
+)
+
+// currentUserID returns the ID of the authenticated user set by middleware.Auth.
+func currentUserID(c *gin.Context) (int32, bool) {
+	value, exists := c.Get("userID")
+	if !exists {
+		return 0, false
+	}
+
+	switch id := value.(type) {
+	case int:
+		return int32(id), true
+	case int32:
+		return id, true
+	}
+	return 0, false
+}
+
+// activeUser loads the authenticated user of a write and checks that their
+// account has not been suspended, which leaves it read-only. It writes the
+// error response and returns false otherwise.
+func activeUser(c *gin.Context, queries *db.Queries) (db.User, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return db.User{}, false
+	}
+
+	user, err := queries.GetUser(c.Request.Context(), userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return db.User{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return db.User{}, false
+	}
+
+	if isSuspended(user) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Your account is suspended"})
+		return db.User{}, false
+	}
+	return user, true
+}
+
+// currentUserWithRole loads the authenticated, active user and checks that
+// they hold one of roles. It writes the error response, using forbidden as
+// the message when the role does not match, and returns false otherwise.
+func currentUserWithRole(c *gin.Context, queries *db.Queries, forbidden string, roles ...string) (db.User, bool) {
+	user, ok := activeUser(c, queries)
+	if !ok {
+		return db.User{}, false
+	}
+
+	if !slices.Contains(roles, user.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": forbidden})
+		return db.User{}, false
+	}
+	return user, true
+}
+
+// activeUserID is activeUser for handlers that only need the user's ID.
+func activeUserID(c *gin.Context, queries *db.Queries) (int32, bool) {
+	user, ok := activeUser(c, queries)
+	return user.ID, ok
+}
+
+// isSuspended reports whether a moderator has suspended the user's account.
+func isSuspended(u db.User) bool {
+	return u.IsActive.Valid && !u.IsActive.Bool
+}
+
+// nullString converts a nullable column into a JSON-friendly value.
+func nullString(s sql.NullString) interface{} {
+	if !s.Valid {
+		return nil
+	}
+	return s.String
+}
+
+// nullTime converts a nullable timestamp into a JSON-friendly value.
+func nullTime(t sql.NullTime) interface{} {
+	if !t.Valid {
+		return nil
+	}
+	return t.Time
+}
+
+// nullInt32 converts a nullable integer into a JSON-friendly value.
+func nullInt32(i sql.NullInt32) interface{} {
+	if !i.Valid {
+		return nil
+	}
+	return i.Int32
+}
+
+// requestOrigin returns the scheme and host the request was made to.
+func requestOrigin(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+// baseURL returns the absolute URL the API is mounted at, found by cutting
+// the request path before the first occurrence of segment. This keeps
+// generated links correct behind any prefix.
+func baseURL(c *gin.Context, segment string) string {
+	base := requestOrigin(c)
+	if i := strings.Index(c.Request.URL.Path, segment); i >= 0 {
+		base += c.Request.URL.Path[:i]
+	}
+	return base
+}
+
+// listFilter parses the filter and sort parameters of a list request
+// against schema. It writes a 400 listing every invalid parameter and
+// returns false when the request has any.
+func listFilter(c *gin.Context, schema *listquery.Schema) (listquery.Query, bool) {
+	query, err := schema.Parse(c.Request.URL.Query())
+	var errs listquery.Errors
+	if errors.As(err, &errs) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter or sort", "details": errs})
+		return listquery.Query{}, false
+	}
+	return query, true
+}
\ No newline at end of file
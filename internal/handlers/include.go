@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/listquery"
+	"github.com/gin-gonic/gin"
+)
+
+// postFields are the post fields ?fields= can select. Some are only part
+// of single-post responses.
+var postFields = []string{
+	"id", "user_id", "title", "slug", "content", "content_format", "excerpt",
+	"reading_time", "status", "locale", "original_locale", "available_locales",
+	"username", "comment_count", "comments_closed", "like_count", "bookmark_count",
+	"liked_by_me", "bookmarked_by_me", "authors", "series", "tags",
+	"published_at", "created_at", "updated_at", "version",
+}
+
+// postRelations are the relations ?include= can embed in posts: the
+// public profile of the owner and the tags.
+var postRelations = []string{"author", "tags"}
+
+// userFields are the user fields ?fields= can select.
+var userFields = []string{
+	"id", "email", "username", "full_name", "bio", "website", "location",
+	"avatar", "role", "is_active", "created_at", "updated_at", "version",
+	"erasure_scheduled_at",
+}
+
+// userRelations are the relations ?include= can embed in users: follower,
+// following and published post counts.
+var userRelations = []string{"counts"}
+
+// responseFieldset parses the fields and include parameters. It writes a
+// 400 listing every invalid parameter and returns false when the request
+// has any.
+func responseFieldset(c *gin.Context, fields, relations []string) (listquery.Fieldset, bool) {
+	fieldset, err := listquery.ParseFieldset(c.Request.URL.Query(), fields, relations)
+	var errs listquery.Errors
+	if errors.As(err, &errs) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fields or include", "details": errs})
+		return listquery.Fieldset{}, false
+	}
+	return fieldset, true
+}
+
+// includePostRelations embeds the requested relations in posts, loading
+// each relation for all posts in one query.
+func includePostRelations(ctx context.Context, queries *db.Queries, base string, fieldset listquery.Fieldset, posts []gin.H) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	if fieldset.Includes("author") {
+		ids := make([]int32, 0, len(posts))
+		for _, post := range posts {
+			ids = append(ids, post["user_id"].(int32))
+		}
+
+		users, err := queries.ListUsersByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		profiles := make(map[int32]gin.H, len(users))
+		for _, u := range users {
+			profiles[u.ID] = profileResponse(base, u)
+		}
+
+		for _, post := range posts {
+			// Owners in the trash have no public profile.
+			if profile, ok := profiles[post["user_id"].(int32)]; ok {
+				post["author"] = profile
+			} else {
+				post["author"] = nil
+			}
+		}
+	}
+
+	if fieldset.Includes("tags") {
+		ids := make([]int32, 0, len(posts))
+		for _, post := range posts {
+			post["tags"] = []string{}
+			ids = append(ids, post["id"].(int32))
+		}
+
+		tags, err := queries.ListTagsForPosts(ctx, ids)
+		if err != nil {
+			return err
+		}
+		byID := make(map[int32]gin.H, len(posts))
+		for _, post := range posts {
+			byID[post["id"].(int32)] = post
+		}
+		for _, tag := range tags {
+			post := byID[tag.PostID]
+			post["tags"] = append(post["tags"].([]string), tag.Tag)
+		}
+	}
+
+	for _, post := range posts {
+		fieldset.Select(post)
+	}
+	return nil
+}
+
+// includeUserRelations embeds the requested relations in users, loading
+// each relation for all users in one query. Follow counts leave out users
+// who blocked the viewer, as the follow lists do.
+func includeUserRelations(ctx context.Context, queries *db.Queries, viewer sql.NullInt32, fieldset listquery.Fieldset, users []gin.H) error {
+	if len(users) > 0 && fieldset.Includes("counts") {
+		ids := make([]int32, 0, len(users))
+		for _, user := range users {
+			ids = append(ids, user["id"].(int32))
+		}
+
+		counts, err := queries.ListUserCounts(ctx, db.ListUserCountsParams{ViewerID: viewer, UserIds: ids})
+		if err != nil {
+			return err
+		}
+		byID := make(map[int32]db.ListUserCountsRow, len(counts))
+		for _, row := range counts {
+			byID[row.UserID] = row
+		}
+
+		for _, user := range users {
+			row := byID[user["id"].(int32)]
+			user["counts"] = gin.H{
+				"followers": row.FollowerCount,
+				"following": row.FollowingCount,
+				"posts":     row.PostCount,
+			}
+		}
+	}
+
+	for _, user := range users {
+		fieldset.Select(user)
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,567 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/events"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	roleEditor = "editor"
+	roleAdmin  = "admin"
+)
+
+type ModerationHandler struct {
+	db      *sql.DB
+	queries *db.Queries
+	bus     *events.Bus
+}
+
+// NewModerationHandler creates a ModerationHandler. Posts hidden by a
+// moderator are emitted on bus.
+func NewModerationHandler(conn *sql.DB, bus *events.Bus) *ModerationHandler {
+	return &ModerationHandler{db: conn, queries: db.New(conn), bus: bus}
+}
+
+type ResolveReportRequest struct {
+	Action string  `json:"action" binding:"required,oneof=none hide_post suspend_user"`
+	Note   *string `json:"note" binding:"omitempty,max=1000"`
+}
+
+type DismissReportRequest struct {
+	Note *string `json:"note" binding:"omitempty,max=1000"`
+}
+
+// List godoc
+// @Summary List reports
+// @Description Get the moderation queue, oldest first. Editors and admins only.
+// @Tags moderation
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param status query string false "open, claimed, resolved or dismissed" default(open)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /moderation/reports [get]
+func (h *ModerationHandler) List(c *gin.Context) {
+	status := c.DefaultQuery("status", "open")
+	if status != "open" && status != "claimed" && status != "resolved" && status != "dismissed" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status, must be open, claimed, resolved or dismissed"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	offset := (page - 1) * limit
+
+	if _, ok := h.moderator(c); !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	rows, err := h.queries.ListReports(ctx, db.ListReportsParams{
+		Status: status,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reports"})
+		return
+	}
+
+	total, err := h.queries.CountReports(ctx, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count reports"})
+		return
+	}
+
+	reports := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		reports = append(reports, reportResponse(row))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reports": reports,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (int(total) + limit - 1) / limit,
+			"offset":      offset,
+		},
+	})
+}
+
+// Claim godoc
+// @Summary Claim report
+// @Description Assign an open report to yourself so other moderators leave it alone. Claiming a report you already hold has no further effect. Editors and admins only.
+// @Tags moderation
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Report ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /moderation/reports/{id}/claim [post]
+func (h *ModerationHandler) Claim(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	moderator, ok := h.moderator(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to claim report"})
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := h.queries.WithTx(tx)
+
+	report, ok := h.lockReport(c, qtx, int32(id), moderator)
+	if !ok {
+		return
+	}
+
+	if report.Status == "open" {
+		report, err = qtx.ClaimReport(ctx, db.ClaimReportParams{
+			ID:        report.ID,
+			ClaimedBy: sql.NullInt32{Int32: moderator.ID, Valid: true},
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to claim report"})
+			return
+		}
+		if err := logModeration(c, qtx, moderator, report, "claim", report.TargetType, report.TargetID, nil); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to claim report"})
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to claim report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Report claimed successfully",
+		"data":    reportResponse(report),
+	})
+}
+
+// Resolve godoc
+// @Summary Resolve report
+// @Description Close a report and optionally act on it: hide_post sets a reported post's status to hidden, which streams post.unpublished when it was published, suspend_user deactivates the reported user or the author of the reported content. Only admins can suspend editors and admins. Editors and admins only.
+// @Tags moderation
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Report ID"
+// @Param request body ResolveReportRequest true "Resolution"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /moderation/reports/{id}/resolve [post]
+func (h *ModerationHandler) Resolve(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	var req ResolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	moderator, ok := h.moderator(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve report"})
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := h.queries.WithTx(tx)
+
+	report, ok := h.lockReport(c, qtx, int32(id), moderator)
+	if !ok {
+		return
+	}
+
+	// event is emitted once the resolution is committed.
+	var event *events.Event
+	switch req.Action {
+	case "hide_post":
+		if report.TargetType != "post" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "hide_post only applies to post reports"})
+			return
+		}
+		current, err := qtx.GetPostForUpdate(ctx, report.TargetID)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+			return
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hide post"})
+			return
+		}
+		post, err := qtx.SetPostStatus(ctx, db.SetPostStatusParams{
+			ID:     current.ID,
+			Status: sql.NullString{String: "hidden", Valid: true},
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hide post"})
+			return
+		}
+		event = postEvent(moderator.ID, &current, &post)
+		if err := logModeration(c, qtx, moderator, report, "hide_post", "post", report.TargetID, req.Note); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve report"})
+			return
+		}
+
+	case "suspend_user":
+		if !h.suspend(c, qtx, moderator, report, req.Note) {
+			return
+		}
+	}
+
+	params := db.CloseReportParams{
+		Status:     "resolved",
+		ClosedBy:   sql.NullInt32{Int32: moderator.ID, Valid: true},
+		Resolution: sql.NullString{String: req.Action, Valid: true},
+		ID:         report.ID,
+	}
+	if req.Note != nil {
+		params.Note = sql.NullString{String: *req.Note, Valid: true}
+	}
+
+	report, err = qtx.CloseReport(ctx, params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve report"})
+		return
+	}
+	if err := logModeration(c, qtx, moderator, report, "resolve", report.TargetType, report.TargetID, req.Note); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve report"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve report"})
+		return
+	}
+	if event != nil {
+		h.bus.Emit(ctx, *event)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Report resolved successfully",
+		"data":    reportResponse(report),
+	})
+}
+
+// Dismiss godoc
+// @Summary Dismiss report
+// @Description Close a report without action. Editors and admins only.
+// @Tags moderation
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Report ID"
+// @Param request body DismissReportRequest false "Optional note"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /moderation/reports/{id}/dismiss [post]
+func (h *ModerationHandler) Dismiss(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	var req DismissReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	moderator, ok := h.moderator(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to dismiss report"})
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := h.queries.WithTx(tx)
+
+	report, ok := h.lockReport(c, qtx, int32(id), moderator)
+	if !ok {
+		return
+	}
+
+	params := db.CloseReportParams{
+		Status:   "dismissed",
+		ClosedBy: sql.NullInt32{Int32: moderator.ID, Valid: true},
+		ID:       report.ID,
+	}
+	if req.Note != nil {
+		params.Note = sql.NullString{String: *req.Note, Valid: true}
+	}
+
+	report, err = qtx.CloseReport(ctx, params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to dismiss report"})
+		return
+	}
+	if err := logModeration(c, qtx, moderator, report, "dismiss", report.TargetType, report.TargetID, req.Note); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to dismiss report"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to dismiss report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Report dismissed successfully",
+		"data":    reportResponse(report),
+	})
+}
+
+// Log godoc
+// @Summary Moderation log
+// @Description Get every moderation action with the moderator who took it, most recent first. Editors and admins only.
+// @Tags moderation
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /moderation/log [get]
+func (h *ModerationHandler) Log(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	offset := (page - 1) * limit
+
+	if _, ok := h.moderator(c); !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	rows, err := h.queries.ListModerationLog(ctx, db.ListModerationLogParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch moderation log"})
+		return
+	}
+
+	total, err := h.queries.CountModerationLog(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count moderation log"})
+		return
+	}
+
+	entries := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, gin.H{
+			"id":           row.ID,
+			"moderator_id": nullInt32(row.ModeratorID),
+			"report_id":    nullInt32(row.ReportID),
+			"action":       row.Action,
+			"target_type":  row.TargetType,
+			"target_id":    row.TargetID,
+			"note":         nullString(row.Note),
+			"created_at":   nullTime(row.CreatedAt),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (int(total) + limit - 1) / limit,
+			"offset":      offset,
+		},
+	})
+}
+
+// moderator authenticates the caller and checks that they are an editor or
+// admin whose account has not been suspended. It writes the error response
+// and returns false otherwise.
+func (h *ModerationHandler) moderator(c *gin.Context) (db.User, bool) {
+	return currentUserWithRole(c, h.queries, "Only editors and admins can moderate", roleEditor, roleAdmin)
+}
+
+// lockReport loads a report for update and checks that no other moderator
+// has claimed it. It writes the error response and returns false when
+// either fails.
+func (h *ModerationHandler) lockReport(c *gin.Context, qtx *db.Queries, id int32, moderator db.User) (db.Report, bool) {
+	report, err := qtx.GetReportForUpdate(c.Request.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+		return db.Report{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch report"})
+		return db.Report{}, false
+	}
+
+	switch {
+	case report.Status == "claimed" && report.ClaimedBy.Int32 != moderator.ID:
+		c.JSON(http.StatusConflict, gin.H{"error": "Report is claimed by another moderator"})
+		return db.Report{}, false
+	case report.Status == "resolved" || report.Status == "dismissed":
+		c.JSON(http.StatusConflict, gin.H{"error": "Report is already closed"})
+		return db.Report{}, false
+	}
+	return report, true
+}
+
+// suspend deactivates the user a report is about: the reported user, or
+// the author of the reported post or comment. It writes the error response
+// and returns false on failure.
+func (h *ModerationHandler) suspend(c *gin.Context, qtx *db.Queries, moderator db.User, report db.Report, note *string) bool {
+	ctx := c.Request.Context()
+
+	userID := report.TargetID
+	switch report.TargetType {
+	case "post":
+		row, err := qtx.GetPost(ctx, report.TargetID)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+			return false
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+			return false
+		}
+		userID = row.Post.UserID
+	case "comment":
+		comment, err := qtx.GetComment(ctx, report.TargetID)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+			return false
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comment"})
+			return false
+		}
+		userID = comment.UserID
+	}
+
+	user, err := qtx.GetUserForUpdate(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return false
+	}
+
+	if isModerator(user) && moderator.Role != roleAdmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only admins can suspend editors and admins"})
+		return false
+	}
+
+	if _, err := qtx.SetUserActive(ctx, db.SetUserActiveParams{
+		ID:       user.ID,
+		IsActive: sql.NullBool{Bool: false, Valid: true},
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to suspend user"})
+		return false
+	}
+
+	if err := logModeration(c, qtx, moderator, report, "suspend_user", "user", user.ID, note); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to suspend user"})
+		return false
+	}
+	return true
+}
+
+// logModeration records an action taken by a moderator on a report.
+func logModeration(c *gin.Context, qtx *db.Queries, moderator db.User, report db.Report, action, targetType string, targetID int32, note *string) error {
+	params := db.CreateModerationLogEntryParams{
+		ModeratorID: sql.NullInt32{Int32: moderator.ID, Valid: true},
+		ReportID:    sql.NullInt32{Int32: report.ID, Valid: true},
+		Action:      action,
+		TargetType:  targetType,
+		TargetID:    targetID,
+	}
+	if note != nil {
+		params.Note = sql.NullString{String: *note, Valid: true}
+	}
+	return qtx.CreateModerationLogEntry(c.Request.Context(), params)
+}
+
+// isModerator reports whether a user can work the moderation queue.
+func isModerator(u db.User) bool {
+	return u.Role == roleEditor || u.Role == roleAdmin
+}
\ No newline at end of file
@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/notify"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	db      *sql.DB
+	queries *db.Queries
+}
+
+func NewNotificationHandler(conn *sql.DB) *NotificationHandler {
+	return &NotificationHandler{db: conn, queries: db.New(conn)}
+}
+
+// NotificationPreferencesRequest changes notification preferences. Fields
+// left out keep their current value.
+type NotificationPreferencesRequest struct {
+	Comments    *bool  `json:"comments"`
+	Replies     *bool  `json:"replies"`
+	Likes       *bool  `json:"likes"`
+	Follows     *bool  `json:"follows"`
+	EmailDigest string `json:"email_digest" binding:"omitempty,oneof=off daily weekly"`
+}
+
+// List godoc
+// @Summary List notifications
+// @Description Get your notifications, most recent first, with the number of unread ones
+// @Tags notifications
+// @Security Bearer
+// @Produce json
+// @Param unread query bool false "Only unread notifications" default(false)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /notifications [get]
+func (h *NotificationHandler) List(c *gin.Context) {
+	unreadOnly, err := strconv.ParseBool(c.DefaultQuery("unread", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid unread, must be true or false"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+	ctx := c.Request.Context()
+
+	notifications, err := h.queries.ListNotifications(ctx, db.ListNotificationsParams{
+		UserID:     userID,
+		UnreadOnly: unreadOnly,
+		Limit:      int32(limit),
+		Offset:     int32(offset),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+
+	total, err := h.queries.CountNotifications(ctx, db.CountNotificationsParams{UserID: userID, UnreadOnly: unreadOnly})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count notifications"})
+		return
+	}
+
+	unread, err := h.queries.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count notifications"})
+		return
+	}
+
+	items, err := notificationsResponse(ctx, h.queries, baseURL(c, "/users/"), notifications)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": items,
+		"unread_count":  unread,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (int(total) + limit - 1) / limit,
+			"offset":      offset,
+		},
+	})
+}
+
+// MarkRead godoc
+// @Summary Mark notification as read
+// @Description Mark one of your notifications as read. Marking it twice has no further effect.
+// @Tags notifications
+// @Security Bearer
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	notification, err := h.queries.MarkNotificationRead(ctx, db.MarkNotificationReadParams{ID: int32(id), UserID: userID})
+	// Other users' notifications are reported as missing.
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+		return
+	}
+
+	unread, err := h.queries.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count notifications"})
+		return
+	}
+
+	items, err := notificationsResponse(ctx, h.queries, baseURL(c, "/users/"), []db.Notification{notification})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notification"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": items[0], "unread_count": unread})
+}
+
+// MarkAllRead godoc
+// @Summary Mark all notifications as read
+// @Description Mark every unread notification of yours as read
+// @Tags notifications
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	marked, err := h.queries.MarkAllNotificationsRead(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"marked":       marked,
+			"unread_count": 0,
+		},
+	})
+}
+
+// GetPreferences godoc
+// @Summary Get notification preferences
+// @Description Get which notifications you receive and how often they are emailed to you
+// @Tags notifications
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /users/me/notification-preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	prefs, err := notify.Preferences(c.Request.Context(), h.queries, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notification preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": preferencesResponse(prefs)})
+}
+
+// UpdatePreferences godoc
+// @Summary Update notification preferences
+// @Description Turn notification types on or off and choose an email digest of unread notifications: off, daily or weekly
+// @Tags notifications
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body NotificationPreferencesRequest true "Preferences"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /users/me/notification-preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	var req NotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	prefs, err := notify.Preferences(ctx, h.queries, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notification preferences"})
+		return
+	}
+
+	params := db.SaveNotificationPreferencesParams{
+		UserID:      userID,
+		Comments:    prefs.Comments,
+		Replies:     prefs.Replies,
+		Likes:       prefs.Likes,
+		Follows:     prefs.Follows,
+		EmailDigest: prefs.EmailDigest,
+	}
+	if req.Comments != nil {
+		params.Comments = *req.Comments
+	}
+	if req.Replies != nil {
+		params.Replies = *req.Replies
+	}
+	if req.Likes != nil {
+		params.Likes = *req.Likes
+	}
+	if req.Follows != nil {
+		params.Follows = *req.Follows
+	}
+	if req.EmailDigest != "" {
+		params.EmailDigest = req.EmailDigest
+	}
+
+	prefs, err = h.queries.SaveNotificationPreferences(ctx, params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Notification preferences updated successfully",
+		"data":    preferencesResponse(prefs),
+	})
+}
+
+// notificationsResponse converts notifications, embedding the public
+// profile of every actor loaded with one query. Actors whose account is
+// gone are null.
+func notificationsResponse(ctx context.Context, queries *db.Queries, base string, notifications []db.Notification) ([]gin.H, error) {
+	ids := make([]int32, 0, len(notifications))
+	for _, n := range notifications {
+		if n.ActorID.Valid {
+			ids = append(ids, n.ActorID.Int32)
+		}
+	}
+
+	profiles := make(map[int32]gin.H, len(ids))
+	if len(ids) > 0 {
+		actors, err := queries.ListUsersByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range actors {
+			profiles[u.ID] = profileResponse(base, u)
+		}
+	}
+
+	items := make([]gin.H, 0, len(notifications))
+	for _, n := range notifications {
+		var actor interface{}
+		if profile, ok := profiles[n.ActorID.Int32]; ok {
+			actor = profile
+		}
+		items = append(items, gin.H{
+			"id":         n.ID,
+			"type":       n.Type,
+			"actor":      actor,
+			"post_id":    nullInt32(n.PostID),
+			"comment_id": nullInt32(n.CommentID),
+			"read":       n.ReadAt.Valid,
+			"read_at":    nullTime(n.ReadAt),
+			"created_at": nullTime(n.CreatedAt),
+		})
+	}
+	return items, nil
+}
+
+func preferencesResponse(prefs db.NotificationPreference) gin.H {
+	return gin.H{
+		"comments":       prefs.Comments,
+		"replies":        prefs.Replies,
+		"likes":          prefs.Likes,
+		"follows":        prefs.Follows,
+		"email_digest":   prefs.EmailDigest,
+		"last_digest_at": nullTime(prefs.LastDigestAt),
+	}
+}
\ No newline at end of file
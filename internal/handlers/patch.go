@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// mergePatchContentType is the media type of RFC 7396 JSON Merge Patch.
+const mergePatchContentType = "application/merge-patch+json"
+
+var errPatchNotObject = errors.New("patch must be a JSON object")
+
+// readMergePatch reads a JSON Merge Patch request body. Plain
+// application/json is accepted as well; the second result is false when the
+// request uses any other media type.
+func readMergePatch(c *gin.Context) (map[string]interface{}, bool, error) {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType != mergePatchContentType && mediaType != "application/json" {
+		return nil, false, nil
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		return nil, true, err
+	}
+
+	var patch interface{}
+	if err := json.Unmarshal(body, &patch); err != nil {
+		return nil, true, err
+	}
+
+	object, ok := patch.(map[string]interface{})
+	if !ok {
+		return nil, true, errPatchNotObject
+	}
+	return object, true, nil
+}
+
+// mergePatch applies an RFC 7396 merge patch to target: null removes a
+// member, objects are merged recursively and any other value replaces the
+// member.
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = mergePatch(targetObject[name], value)
+	}
+	return targetObject
+}
+
+// decodePatched decodes a patched document into a request struct and runs
+// the same validation as a full replacement, so PATCH and PUT accept exactly
+// the same resulting resources.
+func decodePatched(document map[string]interface{}, req interface{}) error {
+	raw, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(req); err != nil {
+		return err
+	}
+	return binding.Validator.ValidateStruct(req)
+}
\ No newline at end of file
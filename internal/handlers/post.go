@@ -1,44 +1,122 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"net/http"
+	"net/url"
+	"path"
 	"strconv"
+	"strings"
 
+	"github.com/demo/demo-gin/internal/db/listing"
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/events"
+	"github.com/demo/demo-gin/internal/i18n"
+	"github.com/demo/demo-gin/internal/listquery"
+	"github.com/demo/demo-gin/internal/render"
+	"github.com/demo/demo-gin/internal/slugs"
+	"github.com/demo/demo-gin/internal/views"
 	"github.com/gin-gonic/gin"
 )
 
+// excerptLength is the maximum number of characters in a post excerpt.
+const excerptLength = 200
+
+// postFilters are the fields posts can be filtered and sorted on.
+var postFilters = &listquery.Schema{
+	Fields: map[string]listquery.Field{
+		"status":          {Column: "p.status", Values: []string{"published", "draft", "archived", "hidden"}},
+		"user_id":         {Column: "p.user_id", Type: listquery.Integer},
+		"author":          {Column: "u.username"},
+		"locale":          {Column: "p.locale"},
+		"title":           {Column: "p.title", Sortable: true},
+		"comments_closed": {Column: "p.comments_closed", Type: listquery.Boolean},
+		"like_count":      {Column: "p.like_count", Type: listquery.Integer, Sortable: true},
+		"published_at":    {Column: "p.published_at", Type: listquery.Time, Sortable: true},
+		"created_at":      {Column: "p.created_at", Type: listquery.Time, Sortable: true},
+		"updated_at":      {Column: "p.updated_at", Type: listquery.Time, Sortable: true},
+	},
+	DefaultSort: "-published_at",
+	Tiebreaker:  "p.id",
+}
+
 type PostHandler struct {
-	db *sql.DB
+	db      *sql.DB
+	queries *db.Queries
+	lists   *listing.Queries
+	counter *views.Counter
+	locales i18n.Locales
+	bus     *events.Bus
 }
 
-func NewPostHandler(db *sql.DB) *PostHandler {
-	return &PostHandler{db: db}
+// NewPostHandler creates a PostHandler. Views are only counted when counter
+// is not nil. Posts are served in the best match of the requested locales.
+// Changes to posts are emitted on bus.
+func NewPostHandler(conn *sql.DB, counter *views.Counter, locales i18n.Locales, bus *events.Bus) *PostHandler {
+	return &PostHandler{db: conn, queries: db.New(conn), lists: listing.New(conn), counter: counter, locales: locales, bus: bus}
 }
 
 type CreatePostRequest struct {
-	Title   string `json:"title" binding:"required,min=1,max=255"`
-	Content string `json:"content" binding:"required"`
-	Status  string `json:"status"`
+	Title         string `json:"title" binding:"required,min=1,max=255"`
+	Content       string `json:"content" binding:"required"`
+	ContentFormat string `json:"content_format" binding:"omitempty,oneof=plain markdown"`
+	Status        string `json:"status" binding:"omitempty,oneof=draft published"`
+	Locale        string `json:"locale" binding:"omitempty,max=10"`
 }
 
+// UpdatePostRequest is the full representation of an editable post. PUT
+// replaces the post with it, and PATCH validates the patched post against it.
 type UpdatePostRequest struct {
-	Title   string `json:"title,omitempty"`
-	Content string `json:"content,omitempty"`
-	Status  string `json:"status,omitempty"`
+	Title         string  `json:"title" binding:"required,min=1,max=255"`
+	Content       *string `json:"content"`
+	ContentFormat string  `json:"content_format" binding:"omitempty,oneof=plain markdown"`
+	Status        string  `json:"status" binding:"required,oneof=draft published archived"`
 }
 
 // List godoc
 // @Summary List posts
-// @Description Get a list of published posts
+// @Description Get a list of published posts, each in the first of the requested locales it is available in. With a bearer token, each post reports whether the caller liked or bookmarked it, and posts by muted authors and authors who blocked the caller are left out. Filter with filter[field]=value or filter[field][operator]=value and sort with a comma-separated list of fields, each prefixed with - for descending order. Filtering on a status other than published lists the caller's own posts in that status.
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param filter[field][operator] query string false "Filter on status, user_id, author, locale, title, comments_closed, like_count, published_at, created_at or updated_at with eq (the default), ne, gt, gte, lt, lte, in or contains"
+// @Param sort query string false "Sort on title, like_count, published_at, created_at or updated_at" default(-published_at)
+// @Param fields query string false "Comma-separated fields to return; id is always returned"
+// @Param include query string false "Comma-separated relations to embed (author, tags)"
+// @Param lang query string false "Preferred locale, before those in Accept-Language"
+// @Param Accept-Language header string false "Preferred locales"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Router /posts [get]
 func (h *PostHandler) List(c *gin.Context) {
+	preferences, ok := preferredLocales(c, h.locales, "")
+	if !ok {
+		return
+	}
+
+	filter, ok := listFilter(c, postFilters)
+	if !ok {
+		return
+	}
+
+	fieldset, ok := responseFieldset(c, postFields, postRelations)
+	if !ok {
+		return
+	}
+	// Without a status filter only published posts are listed, including
+	// for their authors.
+	if !filter.Has("status") {
+		filter.Conditions = append(filter.Conditions, listquery.Condition{
+			Field:    "status",
+			Operator: listquery.Eq,
+			Values:   []interface{}{"published"},
+		})
+	}
+
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
@@ -50,33 +128,67 @@ func (h *PostHandler) List(c *gin.Context) {
 	}
 
 	offset := (page - 1) * limit
+	ctx := c.Request.Context()
+
+	userID, authenticated := currentUserID(c)
+	viewer := sql.NullInt32{Int32: userID, Valid: authenticated}
+
+	rows, err := h.lists.ListPosts(ctx, listing.ListPostsParams{
+		ViewerID: viewer,
+		Filter:   filter,
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+		return
+	}
+
+	total, err := h.lists.CountPosts(ctx, viewer, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count posts"})
+		return
+	}
+
+	originals := make([]db.Post, 0, len(rows))
+	for _, row := range rows {
+		originals = append(originals, row.Post)
+	}
+	localized, err := localizePosts(ctx, h.queries, originals, preferences)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+		return
+	}
+
+	posts := make([]gin.H, 0, len(rows))
+	for i, row := range rows {
+		post := postResponse(localized[i])
+		post["original_locale"] = row.Post.Locale
+		post["username"] = row.Username
+		post["comment_count"] = row.CommentCount
+		posts = append(posts, post)
+	}
+
+	if fieldset.Wants("liked_by_me") || fieldset.Wants("bookmarked_by_me") {
+		if err := attachReactions(ctx, h.queries, userID, authenticated, posts); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+			return
+		}
+	}
 
-	// TODO: Fetch posts from database using sqlc
+	if err := includePostRelations(ctx, h.queries, baseURL(c, "/posts"), fieldset, posts); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+		return
+	}
 
+	c.Header("Vary", "Accept-Language")
 	c.JSON(http.StatusOK, gin.H{
-		"posts": []gin.H{
-			{
-				"id":           1,
-				"user_id":      1,
-				"title":        "First Post",
-				"content":      "This is the first post",
-				"status":       "published",
-				"published_at": "2024-01-01T00:00:00Z",
-			},
-			{
-				"id":           2,
-				"user_id":      2,
-				"title":        "Second Post",
-				"content":      "This is the second post",
-				"status":       "published",
-				"published_at": "2024-01-02T00:00:00Z",
-			},
-		},
+		"posts": posts,
 		"pagination": gin.H{
 			"page":        page,
 			"limit":       limit,
-			"total":       2,
-			"total_pages": 1,
+			"total":       total,
+			"total_pages": (int(total) + limit - 1) / limit,
 			"offset":      offset,
 		},
 	})
@@ -84,12 +196,20 @@ func (h *PostHandler) List(c *gin.Context) {
 
 // Get godoc
 // @Summary Get post by ID
-// @Description Get post details by ID
+// @Description Get post details by ID, with its authors and, for posts in a series, links to the previous and next published parts. The post is returned in the first of the requested locales it is available in, falling back to the default locale and then to the locale it is written in. With render=html the content is returned as sanitized HTML instead of its source. Drafts are only visible to their authors; share them with a preview link. The weak ETag header tracks edits to the post and its translation, its authors, series links, counters and your reactions; send it back in If-None-Match to get a 304 while none of them changed.
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Param id path int true "Post ID"
+// @Param render query string false "Content representation (raw, html)" default(raw)
+// @Param lang query string false "Preferred locale, before those in Accept-Language"
+// @Param Accept-Language header string false "Preferred locales"
+// @Param fields query string false "Comma-separated fields to return; id is always returned"
+// @Param include query string false "Comma-separated relations to embed (author, tags)"
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} map[string]interface{}
+// @Success 304
+// @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Router /posts/{id} [get]
 func (h *PostHandler) Get(c *gin.Context) {
@@ -99,25 +219,218 @@ func (h *PostHandler) Get(c *gin.Context) {
 		return
 	}
 
-	// TODO: Fetch post from database using sqlc
+	mode := c.DefaultQuery("render", "raw")
+	if mode != "raw" && mode != "html" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid render, must be html or raw"})
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": gin.H{
-			"id":           id,
-			"user_id":      1,
-			"title":        "Sample Post",
-			"content":      "This is a sample post content",
-			"status":       "published",
-			"published_at": "2024-01-01T00:00:00Z",
-			"created_at":   "2024-01-01T00:00:00Z",
-			"updated_at":   "2024-01-01T00:00:00Z",
-		},
-	})
+	preferences, ok := preferredLocales(c, h.locales, "")
+	if !ok {
+		return
+	}
+
+	fieldset, ok := responseFieldset(c, postFields, postRelations)
+	if !ok {
+		return
+	}
+
+	row, err := h.queries.GetPost(c.Request.Context(), int32(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+
+	h.respond(c, row.Post, row.Username, row.CommentCount, mode, preferences, fieldset)
+}
+
+// GetBySlug godoc
+// @Summary Get post by slug
+// @Description Get post details by slug. The slug of a translation selects that translation unless lang asks for another locale. Slugs a post or translation had before a title change redirect with 301 to the current slug of the post.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param slug path string true "Post slug"
+// @Param render query string false "Content representation (raw, html)" default(raw)
+// @Param lang query string false "Preferred locale, before those in Accept-Language"
+// @Param Accept-Language header string false "Preferred locales"
+// @Param fields query string false "Comma-separated fields to return; id is always returned"
+// @Param include query string false "Comma-separated relations to embed (author, tags)"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {object} map[string]interface{}
+// @Success 301
+// @Success 304
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/by-slug/{slug} [get]
+func (h *PostHandler) GetBySlug(c *gin.Context) {
+	postSlug := c.Param("slug")
+
+	mode := c.DefaultQuery("render", "raw")
+	if mode != "raw" && mode != "html" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid render, must be html or raw"})
+		return
+	}
+
+	preferences, ok := preferredLocales(c, h.locales, "")
+	if !ok {
+		return
+	}
+
+	fieldset, ok := responseFieldset(c, postFields, postRelations)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	row, err := h.queries.GetPostBySlug(ctx, postSlug)
+	if errors.Is(err, sql.ErrNoRows) {
+		translated, err := h.queries.GetPostByTranslationSlug(ctx, postSlug)
+		if err == nil {
+			preferences, _ = preferredLocales(c, h.locales, translated.TranslationLocale)
+			h.respond(c, translated.Post, translated.Username, translated.CommentCount, mode, preferences, fieldset)
+			return
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+			return
+		}
+
+		current, err := h.queries.GetSlugRedirect(ctx, postSlug)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+			return
+		}
+
+		location := url.URL{Path: path.Join(path.Dir(c.Request.URL.Path), current), RawQuery: c.Request.URL.RawQuery}
+		c.Redirect(http.StatusMovedPermanently, location.String())
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+
+	h.respond(c, row.Post, row.Username, row.CommentCount, mode, preferences, fieldset)
+}
+
+// respond writes a single post in the preferred locale with its ETag,
+// honoring If-None-Match, the requested content representation and the
+// requested fields and relations.
+func (h *PostHandler) respond(c *gin.Context, p db.Post, username string, commentCount int64, mode string, preferences []string, fieldset listquery.Fieldset) {
+	ctx := c.Request.Context()
+	userID, authenticated := currentUserID(c)
+
+	authors, err := h.queries.ListPostAuthors(ctx, p.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+	isAuthor := authenticated && hasAuthor(authors, userID)
+
+	// Drafts and posts hidden by a moderator are visible to their authors
+	// only; others can read drafts through a preview link.
+	if (p.Status.String == "draft" || p.Status.String == "hidden") && !isAuthor {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	// Posts by authors who blocked the caller look like they do not exist.
+	if authenticated {
+		blocked, err := blockedByAny(ctx, h.queries, userID, p.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+			return
+		}
+		if blocked {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+			return
+		}
+	}
+
+	localized, available, translationVersion, err := localizePost(ctx, h.queries, p, preferences)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+
+	c.Header("Vary", "Accept-Language")
+	c.Header("Content-Language", localized.Locale)
+
+	post := postResponse(localized)
+	post["original_locale"] = p.Locale
+	post["available_locales"] = available
+	post["username"] = username
+	post["comment_count"] = commentCount
+	post["authors"] = authorsResponse(authors)
+	if mode == "html" {
+		post["content"] = renderedContent(localized)
+	}
+
+	if fieldset.Wants("series") {
+		series, err := seriesNavigation(ctx, h.queries, p.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+			return
+		}
+		post["series"] = series
+	}
+
+	// Single posts always come with their tags; include=tags loads them
+	// below like for lists.
+	if fieldset.Wants("tags") && !fieldset.Includes("tags") {
+		tags, err := h.queries.ListPostTags(ctx, p.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+			return
+		}
+		if tags == nil {
+			tags = []string{}
+		}
+		post["tags"] = tags
+	}
+
+	if fieldset.Wants("liked_by_me") || fieldset.Wants("bookmarked_by_me") {
+		if err := attachReactions(ctx, h.queries, userID, authenticated, []gin.H{post}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+			return
+		}
+	}
+
+	if err := includePostRelations(ctx, h.queries, baseURL(c, "/posts"), fieldset, []gin.H{post}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+
+	tag := postETag(p.Version, translationVersion, post)
+	c.Header("ETag", tag)
+	if ifNoneMatchTag(c, tag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	// Only full reads count as views: revalidations answered with 304 come
+	// from feed readers and caches, and authors reading their own posts are
+	// not counted either.
+	if h.counter != nil && p.Status.String == "published" && !isAuthor {
+		h.counter.Record(p.ID, visitorKey(c, userID, authenticated))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": post})
 }
 
 // Create godoc
 // @Summary Create a new post
-// @Description Create a new post
+// @Description Create a new post, written in the given locale or the default one. A unique slug is derived from the title, with non-ASCII titles transliterated.
 // @Tags posts
 // @Security Bearer
 // @Accept json
@@ -126,6 +439,7 @@ func (h *PostHandler) Get(c *gin.Context) {
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Router /posts [post]
 func (h *PostHandler) Create(c *gin.Context) {
 	var req CreatePostRequest
@@ -134,43 +448,85 @@ func (h *PostHandler) Create(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
 		return
 	}
 
 	if req.Status == "" {
 		req.Status = "draft"
 	}
+	if req.ContentFormat == "" {
+		req.ContentFormat = render.FormatPlain
+	}
+	if req.Locale == "" {
+		req.Locale = h.locales.Default
+	}
+	req.Locale = i18n.Normalize(req.Locale)
+	if !h.locales.Supports(req.Locale) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid locale, must be one of " + strings.Join(h.locales.Supported, ", ")})
+		return
+	}
+
+	contentHTML, err := render.HTML(req.ContentFormat, req.Content)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render content"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	postSlug, err := slugs.Unique(ctx, h.queries, req.Title, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create post"})
+		return
+	}
+
+	post, err := h.queries.CreatePost(ctx, db.CreatePostParams{
+		UserID:        userID,
+		Title:         req.Title,
+		Slug:          postSlug,
+		Content:       sql.NullString{String: req.Content, Valid: true},
+		Status:        sql.NullString{String: req.Status, Valid: true},
+		ContentFormat: req.ContentFormat,
+		ContentHtml:   sql.NullString{String: contentHTML, Valid: true},
+		Locale:        req.Locale,
+	})
+	if isUniqueViolation(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Slug already taken, please retry"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create post"})
+		return
+	}
 
-	// TODO: Create post in database using sqlc
+	h.emitPostChange(ctx, userID, nil, &post)
 
+	c.Header("ETag", etag(post.Version))
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Post created successfully",
-		"data": gin.H{
-			"id":      1,
-			"user_id": userID,
-			"title":   req.Title,
-			"content": req.Content,
-			"status":  req.Status,
-		},
+		"data":    postResponse(post),
 	})
 }
 
 // Update godoc
-// @Summary Update post
-// @Description Update post details
+// @Summary Replace post
+// @Description Replace the editable fields of a post; an omitted content is cleared. Changing the title changes the slug, and the old slug keeps redirecting. Only the owner and co-authors can update a post. Send the ETag from GET in If-Match to reject the update with 412 if someone else edited the post in the meantime.
 // @Tags posts
 // @Security Bearer
 // @Accept json
 // @Produce json
 // @Param id path int true "Post ID"
+// @Param If-Match header string false "ETag of the post being edited"
 // @Param request body UpdatePostRequest true "Post update details"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
 // @Router /posts/{id} [put]
 func (h *PostHandler) Update(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -185,27 +541,185 @@ func (h *PostHandler) Update(c *gin.Context) {
 		return
 	}
 
-	// TODO: Update post in database using sqlc
+	h.save(c, int32(id), func(db.Post) (UpdatePostRequest, error) {
+		return req, nil
+	})
+}
+
+// Patch godoc
+// @Summary Patch post
+// @Description Apply a JSON Merge Patch (RFC 7396) to a post: members set to null are cleared and omitted members are left as they are. The patched post must pass the same validation as PUT. Only the owner and co-authors can patch a post.
+// @Tags posts
+// @Security Bearer
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param If-Match header string false "ETag of the post being edited"
+// @Param request body map[string]interface{} true "Merge patch"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Router /posts/{id} [patch]
+func (h *PostHandler) Patch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	patch, supported, err := readMergePatch(c)
+	if !supported {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type must be " + mergePatchContentType})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.save(c, int32(id), func(current db.Post) (UpdatePostRequest, error) {
+		var req UpdatePostRequest
+		document := mergePatch(postDocument(current), patch).(map[string]interface{})
+		return req, decodePatched(document, &req)
+	})
+}
+
+// save replaces a post with the representation built from its locked
+// current state, so PUT and PATCH share authorization, If-Match handling and
+// slug history. Changing the title moves the old slug into the history so
+// existing links keep redirecting.
+func (h *PostHandler) save(c *gin.Context, id int32, build func(current db.Post) (UpdatePostRequest, error)) {
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update post"})
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := h.queries.WithTx(tx)
+
+	// Lock the row so the update applies to the version it was checked
+	// against.
+	current, err := qtx.GetPostForUpdate(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+
+	// Co-authors can edit as well as the owner.
+	author, err := isPostAuthor(ctx, qtx, current.ID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+	if !author {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the authors can update this post"})
+		return
+	}
+
+	if current.Status.String == "hidden" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Post has been hidden by a moderator"})
+		return
+	}
+
+	if conditional, matched := ifMatch(c, current.Version); conditional && !matched {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": "Post has been modified"})
+		return
+	}
+
+	req, err := build(current)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	postSlug := current.Slug
+	if req.Title != current.Title {
+		postSlug, err = slugs.Unique(ctx, qtx, req.Title, current.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update post"})
+			return
+		}
+	}
+
+	if postSlug != current.Slug {
+		if err := qtx.AddSlugHistory(ctx, db.AddSlugHistoryParams{Slug: current.Slug, PostID: current.ID}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update post"})
+			return
+		}
+		// A title changed back reclaims its old slug from the history.
+		if err := qtx.DeleteSlugHistory(ctx, db.DeleteSlugHistoryParams{Slug: postSlug, PostID: current.ID}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update post"})
+			return
+		}
+	}
+
+	params, err := updatePostParams(current.ID, postSlug, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render content"})
+		return
+	}
+
+	post, err := qtx.UpdatePost(ctx, params)
+	if isUniqueViolation(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Slug already taken, please retry"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update post"})
+		return
+	}
+
+	// The saved post supersedes the editor's autosave.
+	if _, err := qtx.DeletePostDraft(ctx, db.DeletePostDraftParams{PostID: post.ID, UserID: userID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update post"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update post"})
+		return
+	}
+
+	h.emitPostChange(ctx, userID, &current, &post)
 
+	c.Header("ETag", etag(post.Version))
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Post updated successfully",
-		"data": gin.H{
-			"id": id,
-		},
+		"data":    postResponse(post),
 	})
 }
 
 // Delete godoc
 // @Summary Delete post
-// @Description Delete a post
+// @Description Move a post to the trash, where the owner can restore it until it is removed for good after the retention period. Only the owner can delete a post; co-authors cannot. Send the ETag from GET in If-Match to reject the delete with 412 if the post was edited in the meantime.
 // @Tags posts
 // @Security Bearer
 // @Accept json
 // @Produce json
 // @Param id path int true "Post ID"
+// @Param If-Match header string false "ETag of the post being deleted"
 // @Success 204
 // @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
 // @Router /posts/{id} [delete]
 func (h *PostHandler) Delete(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -214,8 +728,316 @@ func (h *PostHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	// TODO: Delete post from database using sqlc
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	current, ok := h.editablePost(c, int32(id), userID, "delete")
+	if !ok {
+		return
+	}
+
+	conditional, matched := ifMatch(c, current.Version)
+	if conditional && !matched {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": "Post has been modified"})
+		return
+	}
+
+	deleted, err := h.queries.TrashPost(c.Request.Context(), db.TrashPostParams{
+		ID:      current.ID,
+		Version: sql.NullInt32{Int32: current.Version, Valid: conditional},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete post"})
+		return
+	}
+	if deleted == 0 {
+		if conditional {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": "Post has been modified"})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	h.emitPostChange(c.Request.Context(), userID, &current, nil)
 
 	c.Status(http.StatusNoContent)
-	_ = id
+}
+
+// Trash godoc
+// @Summary List trashed posts
+// @Description Get your own deleted posts, most recently deleted first. They are removed for good after the retention period.
+// @Tags posts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /posts/trash [get]
+func (h *PostHandler) Trash(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+	ctx := c.Request.Context()
+
+	rows, err := h.queries.ListTrashedPosts(ctx, db.ListTrashedPostsParams{
+		UserID: userID,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+		return
+	}
+
+	total, err := h.queries.CountTrashedPosts(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count posts"})
+		return
+	}
+
+	posts := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		post := postResponse(row)
+		post["deleted_at"] = nullTime(row.DeletedAt)
+		posts = append(posts, post)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"posts": posts,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (int(total) + limit - 1) / limit,
+			"offset":      offset,
+		},
+	})
+}
+
+// Restore godoc
+// @Summary Restore post
+// @Description Move one of your deleted posts out of the trash. It keeps its slug, status and reactions.
+// @Tags posts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/restore [post]
+func (h *PostHandler) Restore(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	trashed, err := h.queries.GetTrashedPost(ctx, int32(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found in trash"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+
+	if trashed.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the owner can restore this post"})
+		return
+	}
+
+	post, err := h.queries.RestorePost(ctx, trashed.ID)
+	// Restored or purged concurrently.
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found in trash"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore post"})
+		return
+	}
+
+	h.emitPostChange(ctx, userID, nil, &post)
+
+	c.Header("ETag", etag(post.Version))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Post restored successfully",
+		"data":    postResponse(post),
+	})
+}
+
+// editablePost loads a post for a write by its author. It writes the error
+// response and returns false when the post is missing or owned by someone
+// else.
+func (h *PostHandler) editablePost(c *gin.Context, id, userID int32, action string) (db.Post, bool) {
+	row, err := h.queries.GetPost(c.Request.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return db.Post{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return db.Post{}, false
+	}
+
+	if row.Post.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the owner can " + action + " this post"})
+		return db.Post{}, false
+	}
+	return row.Post, true
+}
+
+// postETag is the read tag of a post response, which covers the translation
+// it is served in, its engagement counters, the caller's reactions, its
+// authors, the included author profile and its series links.
+func postETag(version, translationVersion int32, post gin.H) string {
+	return readETag(version, post["locale"], translationVersion,
+		post["like_count"], post["bookmark_count"], post["comment_count"], post["liked_by_me"], post["bookmarked_by_me"],
+		digest(post["authors"], post["author"], post["series"], post["available_locales"]))
+}
+
+// postDocument returns the editable fields of a post in the shape of
+// UpdatePostRequest, leaving out fields that are NULL.
+func postDocument(p db.Post) map[string]interface{} {
+	document := map[string]interface{}{
+		"title":          p.Title,
+		"content_format": p.ContentFormat,
+	}
+	if p.Content.Valid {
+		document["content"] = p.Content.String
+	}
+	if p.Status.Valid {
+		document["status"] = p.Status.String
+	}
+	return document
+}
+
+// updatePostParams maps a full post representation onto UpdatePost and
+// renders its content, defaulting to plain text.
+func updatePostParams(id int32, slug string, req UpdatePostRequest) (db.UpdatePostParams, error) {
+	if req.ContentFormat == "" {
+		req.ContentFormat = render.FormatPlain
+	}
+
+	params := db.UpdatePostParams{
+		Title:         req.Title,
+		Slug:          slug,
+		ContentFormat: req.ContentFormat,
+		Status:        sql.NullString{String: req.Status, Valid: true},
+		ID:            id,
+	}
+	if req.Content != nil {
+		contentHTML, err := render.HTML(req.ContentFormat, *req.Content)
+		if err != nil {
+			return db.UpdatePostParams{}, err
+		}
+		params.Content = sql.NullString{String: *req.Content, Valid: true}
+		params.ContentHtml = sql.NullString{String: contentHTML, Valid: true}
+	}
+	return params, nil
+}
+
+// renderedContent returns the cached HTML of a post, rendering it on the fly
+// for posts written before HTML was cached.
+func renderedContent(p db.Post) string {
+	if p.ContentHtml.Valid || !p.Content.Valid {
+		return p.ContentHtml.String
+	}
+
+	contentHTML, err := render.HTML(p.ContentFormat, p.Content.String)
+	if err != nil {
+		return ""
+	}
+	return contentHTML
+}
+
+// emitPostChange emits the event for a committed write to a post. before
+// is nil for new and restored posts, after is nil for deleted ones.
+func (h *PostHandler) emitPostChange(ctx context.Context, actorID int32, before, after *db.Post) {
+	if event := postEvent(actorID, before, after); event != nil {
+		h.bus.Emit(ctx, *event)
+	}
+}
+
+// postEvent describes a write to a post by how it changed what readers
+// see. Writes to posts that were and stay unpublished are not worth an
+// event, except deletes.
+func postEvent(actorID int32, before, after *db.Post) *events.Event {
+	published := func(p *db.Post) bool {
+		return p != nil && p.Status.String == "published"
+	}
+
+	post := after
+	event := events.Event{ActorID: actorID, Public: published(before) || published(after)}
+	switch {
+	case after == nil:
+		post = before
+		event.Type = events.PostDeleted
+	case published(before) && published(after):
+		event.Type = events.PostUpdated
+	case published(after):
+		event.Type = events.PostPublished
+	case published(before):
+		event.Type = events.PostUnpublished
+	default:
+		return nil
+	}
+
+	event.UserID = post.UserID
+	event.PostID = post.ID
+	event.Data = postResponse(*post)
+	return &event
+}
+
+// postResponse converts a post row into its JSON representation.
+func postResponse(p db.Post) gin.H {
+	text := render.Text(renderedContent(p))
+	return gin.H{
+		"id":              p.ID,
+		"user_id":         p.UserID,
+		"title":           p.Title,
+		"slug":            p.Slug,
+		"content":         nullString(p.Content),
+		"content_format":  p.ContentFormat,
+		"excerpt":         render.Excerpt(text, excerptLength),
+		"reading_time":    render.ReadingTime(text),
+		"status":          nullString(p.Status),
+		"locale":          p.Locale,
+		"comments_closed": p.CommentsClosed,
+		"like_count":      p.LikeCount,
+		"bookmark_count":  p.BookmarkCount,
+		"published_at":    nullTime(p.PublishedAt),
+		"created_at":      nullTime(p.CreatedAt),
+		"updated_at":      nullTime(p.UpdatedAt),
+		"version":         p.Version,
+	}
 }
\ No newline at end of file
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// exportTokenLength is the length of the hex token in export download links.
+const exportTokenLength = 64
+
+type PrivacyHandler struct {
+	db           *sql.DB
+	queries      *db.Queries
+	storage      storage.Storage
+	erasureGrace time.Duration
+}
+
+func NewPrivacyHandler(conn *sql.DB, store storage.Storage, erasureGrace time.Duration) *PrivacyHandler {
+	return &PrivacyHandler{db: conn, queries: db.New(conn), storage: store, erasureGrace: erasureGrace}
+}
+
+// RequestExport godoc
+// @Summary Request data export
+// @Description Start building a ZIP archive of your profile, posts, comments, sessions and attachments. Poll the returned export until it is ready; its download link works without authentication until it expires. Suspended accounts can still make this request.
+// @Tags users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Success 202 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /users/me/export [post]
+func (h *PrivacyHandler) RequestExport(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	export, err := h.queries.CreateDataExport(c.Request.Context(), sql.NullInt32{Int32: userID, Valid: true})
+	if isUniqueViolation(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "An export is already in progress"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to request export"})
+		return
+	}
+
+	base := baseURL(c, "/users/")
+	c.Header("Location", base+"/users/me/exports/"+strconv.Itoa(int(export.ID)))
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Export requested successfully",
+		"data":    exportResponse(base, export),
+	})
+}
+
+// GetExport godoc
+// @Summary Get data export
+// @Description Get the state of one of your data exports, with its download link once it is ready
+// @Tags users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Export ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /users/me/exports/{id} [get]
+func (h *PrivacyHandler) GetExport(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export ID"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	export, err := h.queries.GetDataExport(c.Request.Context(), db.GetDataExportParams{
+		ID:     int32(id),
+		UserID: sql.NullInt32{Int32: userID, Valid: true},
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch export"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": exportResponse(baseURL(c, "/users/"), export)})
+}
+
+// Download godoc
+// @Summary Download data export
+// @Description Download a data export archive. The token in the link is the only credential, so the link must not be shared.
+// @Tags users
+// @Produce application/zip
+// @Param token path string true "Download token"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]interface{}
+// @Failure 410 {object} map[string]interface{}
+// @Router /exports/{token} [get]
+func (h *PrivacyHandler) Download(c *gin.Context) {
+	token := c.Param("token")
+	if _, err := hex.DecodeString(token); err != nil || len(token) != exportTokenLength {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	export, err := h.queries.GetDataExportByToken(ctx, sql.NullString{String: token, Valid: true})
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch export"})
+		return
+	}
+
+	if !exportDownloadable(export) {
+		c.JSON(http.StatusGone, gin.H{"error": "Export link has expired"})
+		return
+	}
+
+	reader, err := h.storage.Open(ctx, export.StorageKey.String)
+	if errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusGone, gin.H{"error": "Export link has expired"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read export"})
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(http.StatusOK, export.SizeBytes.Int64, "application/zip", reader, map[string]string{
+		"Content-Disposition": `attachment; filename="export-` + strconv.Itoa(int(export.ID)) + `.zip"`,
+		"Cache-Control":       "private, no-store",
+	})
+}
+
+// RequestErasure godoc
+// @Summary Request account erasure
+// @Description Schedule your account for erasure once the grace period has passed. Until then the request can be cancelled. Repeating the request keeps the original schedule. Suspended accounts can still make this request.
+// @Tags users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Success 202 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /users/me/erasure [post]
+func (h *PrivacyHandler) RequestErasure(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	user, err := h.queries.ScheduleUserErasure(c.Request.Context(), db.ScheduleUserErasureParams{
+		ErasureScheduledAt: time.Now().Add(h.erasureGrace),
+		ID:                 userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to schedule erasure"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Account erasure scheduled",
+		"data": gin.H{
+			"user_id":              user.ID,
+			"erasure_scheduled_at": nullTime(user.ErasureScheduledAt),
+		},
+	})
+}
+
+// CancelErasure godoc
+// @Summary Cancel account erasure
+// @Description Cancel a pending erasure request. Cancelling when none is pending has no effect.
+// @Tags users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Success 204
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /users/me/erasure [delete]
+func (h *PrivacyHandler) CancelErasure(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	if _, err := h.queries.CancelUserErasure(c.Request.Context(), userID); errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel erasure"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// exportDownloadable reports whether an export's archive can still be
+// downloaded.
+func exportDownloadable(e db.DataExport) bool {
+	return e.Status == "ready" && e.StorageKey.Valid && e.ExpiresAt.Valid && time.Now().Before(e.ExpiresAt.Time)
+}
+
+// exportResponse converts a data export into its JSON representation. The
+// download URL is only included while the archive can be downloaded.
+func exportResponse(base string, e db.DataExport) gin.H {
+	response := gin.H{
+		"id":           e.ID,
+		"status":       e.Status,
+		"size_bytes":   nil,
+		"download_url": nil,
+		"expires_at":   nullTime(e.ExpiresAt),
+		"created_at":   nullTime(e.CreatedAt),
+		"completed_at": nullTime(e.CompletedAt),
+	}
+	if e.SizeBytes.Valid {
+		response["size_bytes"] = e.SizeBytes.Int64
+	}
+	if exportDownloadable(e) {
+		response["download_url"] = base + "/exports/" + e.Token.String
+	}
+	return response
+}
\ No newline at end of file
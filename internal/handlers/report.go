@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	db      *sql.DB
+	queries *db.Queries
+}
+
+func NewReportHandler(conn *sql.DB) *ReportHandler {
+	return &ReportHandler{db: conn, queries: db.New(conn)}
+}
+
+type CreateReportRequest struct {
+	Reason  string  `json:"reason" binding:"required,oneof=spam harassment hate_speech violence sexual_content misinformation other"`
+	Details *string `json:"details" binding:"omitempty,max=1000"`
+}
+
+// ReportPost godoc
+// @Summary Report post
+// @Description Report a post to the moderators
+// @Tags moderation
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param request body CreateReportRequest true "Report details"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /posts/{id}/report [post]
+func (h *ReportHandler) ReportPost(c *gin.Context) {
+	h.report(c, "post", "Post", func(ctx context.Context, id int32) (int32, error) {
+		row, err := h.queries.GetPost(ctx, id)
+		if err == nil && row.Post.Status.String != "published" {
+			return 0, sql.ErrNoRows
+		}
+		return row.Post.UserID, err
+	})
+}
+
+// ReportUser godoc
+// @Summary Report user
+// @Description Report a user to the moderators
+// @Tags moderation
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body CreateReportRequest true "Report details"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /users/{id}/report [post]
+func (h *ReportHandler) ReportUser(c *gin.Context) {
+	h.report(c, "user", "User", func(ctx context.Context, id int32) (int32, error) {
+		user, err := h.queries.GetUser(ctx, id)
+		return user.ID, err
+	})
+}
+
+// ReportComment godoc
+// @Summary Report comment
+// @Description Report a comment to the moderators
+// @Tags moderation
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Comment ID"
+// @Param request body CreateReportRequest true "Report details"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /comments/{id}/report [post]
+func (h *ReportHandler) ReportComment(c *gin.Context) {
+	h.report(c, "comment", "Comment", func(ctx context.Context, id int32) (int32, error) {
+		comment, err := h.queries.GetComment(ctx, id)
+		if err == nil && comment.DeletedAt.Valid {
+			return 0, sql.ErrNoRows
+		}
+		return comment.UserID, err
+	})
+}
+
+// report files a report against a target; label names the target type in
+// error messages. owner looks the target up and returns the ID of the user
+// responsible for it, or sql.ErrNoRows.
+func (h *ReportHandler) report(c *gin.Context, targetType, label string, owner func(ctx context.Context, id int32) (int32, error)) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + targetType + " ID"})
+		return
+	}
+
+	var req CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ownerID, err := owner(ctx, int32(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": label + " not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch " + targetType})
+		return
+	}
+
+	if ownerID == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot report yourself or your own content"})
+		return
+	}
+
+	params := db.CreateReportParams{
+		ReporterID: sql.NullInt32{Int32: userID, Valid: true},
+		TargetType: targetType,
+		TargetID:   int32(id),
+		Reason:     req.Reason,
+	}
+	if req.Details != nil {
+		params.Details = sql.NullString{String: *req.Details, Valid: true}
+	}
+
+	report, err := h.queries.CreateReport(ctx, params)
+	if isUniqueViolation(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "You have already reported this " + targetType})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create report"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Report submitted successfully",
+		"data":    reportResponse(report),
+	})
+}
+
+// reportResponse converts a report row into its JSON representation.
+func reportResponse(r db.Report) gin.H {
+	return gin.H{
+		"id":          r.ID,
+		"reporter_id": nullInt32(r.ReporterID),
+		"target_type": r.TargetType,
+		"target_id":   r.TargetID,
+		"reason":      r.Reason,
+		"details":     nullString(r.Details),
+		"status":      r.Status,
+		"claimed_by":  nullInt32(r.ClaimedBy),
+		"claimed_at":  nullTime(r.ClaimedAt),
+		"closed_by":   nullInt32(r.ClosedBy),
+		"closed_at":   nullTime(r.ClosedAt),
+		"resolution":  nullString(r.Resolution),
+		"note":        nullString(r.Note),
+		"created_at":  nullTime(r.CreatedAt),
+	}
+}
\ No newline at end of file
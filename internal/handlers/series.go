@@ -0,0 +1,505 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+type SeriesHandler struct {
+	db      *sql.DB
+	queries *db.Queries
+}
+
+func NewSeriesHandler(conn *sql.DB) *SeriesHandler {
+	return &SeriesHandler{db: conn, queries: db.New(conn)}
+}
+
+// SeriesRequest is the full representation of an editable series.
+type SeriesRequest struct {
+	Title       string  `json:"title" binding:"required,min=1,max=255"`
+	Description *string `json:"description"`
+}
+
+// SetSeriesPostsRequest lists the posts of a series in reading order. A
+// series holds at most 100 posts.
+type SetSeriesPostsRequest struct {
+	PostIDs []int32 `json:"post_ids" binding:"required,max=100,dive,min=1"`
+}
+
+// List godoc
+// @Summary List series
+// @Description Get series, newest first, optionally only those of one user
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param user_id query int false "Only series of this user"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /series [get]
+func (h *SeriesHandler) List(c *gin.Context) {
+	var owner sql.NullInt32
+	if value := c.Query("user_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		owner = sql.NullInt32{Int32: int32(id), Valid: true}
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+	ctx := c.Request.Context()
+
+	rows, err := h.queries.ListSeries(ctx, db.ListSeriesParams{
+		UserID: owner,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch series"})
+		return
+	}
+
+	total, err := h.queries.CountSeries(ctx, owner)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count series"})
+		return
+	}
+
+	series := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		s := seriesResponse(db.Series{
+			ID:          row.ID,
+			UserID:      row.UserID,
+			Title:       row.Title,
+			Description: row.Description,
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+		})
+		s["username"] = row.Username
+		series = append(series, s)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"series": series,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (int(total) + limit - 1) / limit,
+			"offset":      offset,
+		},
+	})
+}
+
+// Get godoc
+// @Summary Get series
+// @Description Get a series with its posts in reading order. Only the owner sees posts that are not published.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path int true "Series ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /series/{id} [get]
+func (h *SeriesHandler) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	series, err := h.queries.GetSeries(ctx, int32(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch series"})
+		return
+	}
+
+	userID, authenticated := currentUserID(c)
+
+	rows, err := h.queries.ListSeriesPosts(ctx, db.ListSeriesPostsParams{
+		SeriesID:           series.ID,
+		IncludeUnpublished: authenticated && userID == series.UserID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch series"})
+		return
+	}
+
+	posts := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		post := postResponse(row.Post)
+		post["username"] = row.Username
+		post["position"] = row.Position
+		posts = append(posts, post)
+	}
+
+	response := seriesResponse(series)
+	response["posts"] = posts
+	c.JSON(http.StatusOK, gin.H{"data": response})
+}
+
+// Create godoc
+// @Summary Create series
+// @Description Create an empty series; add posts to it with PUT /series/{id}/posts
+// @Tags series
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body SeriesRequest true "Series details"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /series [post]
+func (h *SeriesHandler) Create(c *gin.Context) {
+	var req SeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	params := db.CreateSeriesParams{UserID: userID, Title: req.Title}
+	if req.Description != nil {
+		params.Description = sql.NullString{String: *req.Description, Valid: true}
+	}
+
+	series, err := h.queries.CreateSeries(c.Request.Context(), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create series"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Series created successfully",
+		"data":    seriesResponse(series),
+	})
+}
+
+// Update godoc
+// @Summary Replace series
+// @Description Replace the title and description of your series; an omitted description is cleared
+// @Tags series
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Series ID"
+// @Param request body SeriesRequest true "Series details"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /series/{id} [put]
+func (h *SeriesHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	var req SeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	if _, ok := ownedSeries(c, h.queries.GetSeries, int32(id), userID); !ok {
+		return
+	}
+
+	params := db.UpdateSeriesParams{ID: int32(id), Title: req.Title}
+	if req.Description != nil {
+		params.Description = sql.NullString{String: *req.Description, Valid: true}
+	}
+
+	series, err := h.queries.UpdateSeries(c.Request.Context(), params)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Series updated successfully",
+		"data":    seriesResponse(series),
+	})
+}
+
+// Delete godoc
+// @Summary Delete series
+// @Description Delete your series. Its posts are kept and no longer belong to a series.
+// @Tags series
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Series ID"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /series/{id} [delete]
+func (h *SeriesHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	if _, ok := ownedSeries(c, h.queries.GetSeries, int32(id), userID); !ok {
+		return
+	}
+
+	if err := h.queries.DeleteSeries(c.Request.Context(), int32(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete series"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetPosts godoc
+// @Summary Set series posts
+// @Description Replace the posts of your series with the given posts in reading order. You must be an author of every post, and a post can belong to only one series. An empty list empties the series.
+// @Tags series
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Series ID"
+// @Param request body SetSeriesPostsRequest true "Post IDs in reading order"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /series/{id}/posts [put]
+func (h *SeriesHandler) SetPosts(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	var req SetSeriesPostsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	seen := make(map[int32]bool, len(req.PostIDs))
+	for _, postID := range req.PostIDs {
+		if seen[postID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Duplicate post ID " + strconv.Itoa(int(postID))})
+			return
+		}
+		seen[postID] = true
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update series"})
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := h.queries.WithTx(tx)
+
+	// Lock the series so concurrent reorders do not interleave.
+	series, ok := ownedSeries(c, qtx.GetSeriesForUpdate, int32(id), userID)
+	if !ok {
+		return
+	}
+
+	for _, postID := range req.PostIDs {
+		if _, err := qtx.GetPost(ctx, postID); errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Post " + strconv.Itoa(int(postID)) + " not found"})
+			return
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+			return
+		}
+
+		author, err := isPostAuthor(ctx, qtx, postID, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+			return
+		}
+		if !author {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can only add posts you wrote"})
+			return
+		}
+	}
+
+	if err := qtx.ClearSeriesPosts(ctx, series.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update series"})
+		return
+	}
+
+	for i, postID := range req.PostIDs {
+		err := qtx.AddSeriesPost(ctx, db.AddSeriesPostParams{
+			SeriesID: series.ID,
+			PostID:   postID,
+			Position: int32(i + 1),
+		})
+		if isUniqueViolation(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Post " + strconv.Itoa(int(postID)) + " already belongs to another series"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update series"})
+			return
+		}
+	}
+
+	rows, err := qtx.ListSeriesPosts(ctx, db.ListSeriesPostsParams{SeriesID: series.ID, IncludeUnpublished: true})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update series"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update series"})
+		return
+	}
+
+	posts := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		post := postResponse(row.Post)
+		post["username"] = row.Username
+		post["position"] = row.Position
+		posts = append(posts, post)
+	}
+
+	response := seriesResponse(series)
+	response["posts"] = posts
+	c.JSON(http.StatusOK, gin.H{"data": response})
+}
+
+// ownedSeries loads a series with get and checks that the caller owns it.
+// It writes the error response and returns false otherwise.
+func ownedSeries(c *gin.Context, get func(context.Context, int32) (db.Series, error), id, userID int32) (db.Series, bool) {
+	series, err := get(c.Request.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+		return db.Series{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch series"})
+		return db.Series{}, false
+	}
+
+	if series.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the owner can manage this series"})
+		return db.Series{}, false
+	}
+	return series, true
+}
+
+// seriesNavigation returns the series a post belongs to, with its part
+// number and links to the previous and next published parts, or nil when
+// the post is not in a series.
+func seriesNavigation(ctx context.Context, queries *db.Queries, postID int32) (gin.H, error) {
+	series, err := queries.GetPostSeries(ctx, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := queries.ListSeriesPosts(ctx, db.ListSeriesPostsParams{SeriesID: series.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	// Parts are in reading order, so the last earlier part is the previous
+	// one and the first later part is the next one.
+	var previous, next gin.H
+	part := 1
+	for _, p := range parts {
+		switch {
+		case p.Position < series.Position:
+			previous = seriesLink(p.Post)
+			part++
+		case p.Position > series.Position && next == nil:
+			next = seriesLink(p.Post)
+		}
+	}
+
+	return gin.H{
+		"id":         series.ID,
+		"title":      series.Title,
+		"part":       part,
+		"part_count": len(parts),
+		"previous":   previous,
+		"next":       next,
+	}, nil
+}
+
+// seriesLink is the short form of a post used for series navigation.
+func seriesLink(p db.Post) gin.H {
+	return gin.H{"id": p.ID, "title": p.Title, "slug": p.Slug}
+}
+
+// seriesResponse converts a series into its JSON representation.
+func seriesResponse(s db.Series) gin.H {
+	return gin.H{
+		"id":          s.ID,
+		"user_id":     s.UserID,
+		"title":       s.Title,
+		"description": nullString(s.Description),
+		"created_at":  nullTime(s.CreatedAt),
+		"updated_at":  nullTime(s.UpdatedAt),
+	}
+}
\ No newline at end of file
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/realtime"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// replayBatchSize is the number of missed events loaded per query when
+	// a client reconnects.
+	replayBatchSize = 500
+	// sseRetry is how long EventSource clients wait before reconnecting,
+	// in milliseconds.
+	sseRetry = 3000
+)
+
+// StreamHandler pushes post changes and the caller's notifications as they
+// happen, over Server-Sent Events or a WebSocket.
+type StreamHandler struct {
+	db        *sql.DB
+	queries   *db.Queries
+	hub       *realtime.Hub
+	heartbeat time.Duration
+}
+
+// NewStreamHandler creates a StreamHandler relaying the messages of hub and
+// writing a heartbeat whenever the stream was idle for heartbeat.
+func NewStreamHandler(conn *sql.DB, hub *realtime.Hub, heartbeat time.Duration) *StreamHandler {
+	return &StreamHandler{db: conn, queries: db.New(conn), hub: hub, heartbeat: heartbeat}
+}
+
+// eventStream writes messages to one client.
+type eventStream interface {
+	send(msg realtime.Message) error
+	sendHeartbeat() error
+}
+
+// Events godoc
+// @Summary Stream events
+// @Description Push post published, updated, unpublished and deleted events and your notifications as Server-Sent Events, or as JSON messages over a WebSocket when the request asks for an upgrade. A heartbeat is sent while the stream is idle. Clients reconnecting with the ID of the last event they received, in the Last-Event-ID header or the last_event_id parameter, first get the events they missed.
+// @Tags events
+// @Security Bearer
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Param last_event_id query string false "ID of the last event received, for clients that cannot set headers"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /events [get]
+func (h *StreamHandler) Events(c *gin.Context) {
+	lastID := c.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = c.Query("last_event_id")
+	}
+	var after int64
+	if lastID != "" {
+		var err error
+		after, err = strconv.ParseInt(lastID, 10, 64)
+		if err != nil || after < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Last-Event-ID"})
+			return
+		}
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	if isWebSocketUpgrade(c.Request) {
+		server := websocket.Server{Handler: func(ws *websocket.Conn) {
+			ctx, cancel := context.WithCancel(c.Request.Context())
+			defer cancel()
+
+			// Nothing is expected from the client; reading only notices
+			// when it goes away.
+			go func() {
+				defer cancel()
+				var discard []byte
+				for websocket.Message.Receive(ws, &discard) == nil {
+				}
+			}()
+
+			h.stream(ctx, &wsStream{conn: ws}, userID, after)
+		}}
+		server.ServeHTTP(c.Writer, c.Request)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Keep reverse proxies from buffering the stream.
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	stream := &sseStream{w: c.Writer}
+	if _, err := fmt.Fprintf(c.Writer, "retry: %d\n\n", sseRetry); err != nil {
+		return
+	}
+	c.Writer.Flush()
+
+	h.stream(c.Request.Context(), stream, userID, after)
+}
+
+// stream replays the events after the given ID, then relays live messages
+// until ctx is cancelled, the client goes away or it falls too far behind.
+func (h *StreamHandler) stream(ctx context.Context, stream eventStream, userID int32, after int64) {
+	// Subscribe before replaying so nothing published meanwhile is lost;
+	// live messages already replayed are skipped.
+	sub := h.hub.Subscribe(userID)
+	defer sub.Close()
+
+	last := after
+	if after > 0 {
+		for {
+			missed, err := h.queries.ListStreamEventsAfter(ctx, db.ListStreamEventsAfterParams{
+				AfterID: last,
+				UserID:  userID,
+				Limit:   replayBatchSize,
+			})
+			if err != nil {
+				return
+			}
+			for _, event := range missed {
+				if err := stream.send(realtime.MessageFrom(event)); err != nil {
+					return
+				}
+				last = event.ID
+			}
+			if len(missed) < replayBatchSize {
+				break
+			}
+		}
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if msg.ID <= last {
+				continue
+			}
+			if err := stream.send(msg); err != nil {
+				return
+			}
+			last = msg.ID
+			heartbeat.Reset(h.heartbeat)
+		case <-heartbeat.C:
+			if err := stream.sendHeartbeat(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// sseStream writes Server-Sent Events.
+type sseStream struct {
+	w gin.ResponseWriter
+}
+
+func (s *sseStream) send(msg realtime.Message) error {
+	// Stored event data is JSON on a single line.
+	if _, err := fmt.Fprintf(s.w, "id: %d\nevent: %s\ndata: %s\n\n", msg.ID, msg.Type, msg.Data); err != nil {
+		return err
+	}
+	s.w.Flush()
+	return nil
+}
+
+func (s *sseStream) sendHeartbeat() error {
+	if _, err := s.w.WriteString(": heartbeat\n\n"); err != nil {
+		return err
+	}
+	s.w.Flush()
+	return nil
+}
+
+// wsStream writes events as JSON text messages.
+type wsStream struct {
+	conn *websocket.Conn
+}
+
+func (s *wsStream) send(msg realtime.Message) error {
+	return websocket.JSON.Send(s.conn, gin.H{
+		"id":    strconv.FormatInt(msg.ID, 10),
+		"event": msg.Type,
+		"data":  json.RawMessage(msg.Data),
+	})
+}
+
+func (s *wsStream) sendHeartbeat() error {
+	return websocket.JSON.Send(s.conn, gin.H{"event": "heartbeat"})
+}
+
+// isWebSocketUpgrade reports whether r asks to switch to the WebSocket
+// protocol.
+func isWebSocketUpgrade(r *http.Request) bool {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return false
+	}
+	for _, option := range strings.Split(r.Header.Get("Connection"), ",") {
+		if strings.EqualFold(strings.TrimSpace(option), "upgrade") {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/i18n"
+	"github.com/demo/demo-gin/internal/transfer"
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize is the largest export file accepted for import.
+const maxImportSize = 50 << 20
+
+// TransferHandler imports posts from other blogs and exports them as
+// Markdown.
+type TransferHandler struct {
+	db       *sql.DB
+	queries  *db.Queries
+	importer *transfer.Importer
+}
+
+func NewTransferHandler(conn *sql.DB, locales i18n.Locales) *TransferHandler {
+	return &TransferHandler{db: conn, queries: db.New(conn), importer: transfer.NewImporter(conn, locales)}
+}
+
+// Import godoc
+// @Summary Import posts
+// @Description Import posts from a zip of Markdown files with YAML front matter or a WordPress WXR export. Authors are matched to users by username, then email; posts by unknown authors are assigned to you. Entries already imported from the same source are skipped, so an import can be re-run. With dry_run=true nothing is written and the report shows what would happen. Admins only.
+// @Tags posts
+// @Security Bearer
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Markdown zip or WXR file"
+// @Param format query string false "Format (markdown, wxr); guessed from the file extension when omitted"
+// @Param dry_run query bool false "Report without importing" default(false)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 413 {object} map[string]interface{}
+// @Router /posts/import [post]
+func (h *TransferHandler) Import(c *gin.Context) {
+	format := c.Query("format")
+	if format != "" && format != transfer.FormatMarkdown && format != transfer.FormatWXR {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, must be markdown or wxr"})
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dry_run, must be true or false"})
+		return
+	}
+
+	admin, ok := currentUserWithRole(c, h.queries, "Only admins can import posts", roleAdmin)
+	if !ok {
+		return
+	}
+
+	// Leave room for the multipart framing around the file itself.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize+1<<20)
+	fileHeader, err := c.FormFile("file")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File exceeds the 50 MiB limit"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+	if fileHeader.Size > maxImportSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File exceeds the 50 MiB limit"})
+		return
+	}
+
+	if format == "" {
+		format = importFormat(fileHeader.Filename)
+	}
+	if format == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown file type, set format to markdown or wxr"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+
+	var documents []transfer.Document
+	if format == transfer.FormatWXR {
+		documents, err = transfer.ParseWXR(bytes.NewReader(data))
+	} else {
+		documents, err = transfer.ParseMarkdownArchive(data)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + format + " file: " + err.Error()})
+		return
+	}
+
+	report, err := h.importer.Import(c.Request.Context(), documents, transfer.Options{
+		Format:     format,
+		DryRun:     dryRun,
+		ImportedBy: admin.ID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import posts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// Export godoc
+// @Summary Export your posts
+// @Description Download your posts outside the trash as a zip of Markdown files with YAML front matter, in the format the import reads
+// @Tags posts
+// @Security Bearer
+// @Produce application/zip
+// @Success 200 {file} binary
+// @Failure 401 {object} map[string]interface{}
+// @Router /users/me/posts/export [get]
+func (h *TransferHandler) Export(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	documents, err := transfer.Export(c.Request.Context(), h.queries, sql.NullInt32{Int32: userID, Valid: true})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export posts"})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := transfer.WriteMarkdownArchive(&buf, documents); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export posts"})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="posts.zip"`)
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// importFormat guesses the import format from a file name.
+func importFormat(filename string) string {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".zip":
+		return transfer.FormatMarkdown
+	case ".xml", ".wxr":
+		return transfer.FormatWXR
+	default:
+		return ""
+	}
+}
\ No newline at end of file
@@ -0,0 +1,619 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/i18n"
+	"github.com/demo/demo-gin/internal/render"
+	"github.com/demo/demo-gin/internal/slugs"
+	"github.com/gin-gonic/gin"
+)
+
+// TranslationHandler manages translations of posts. A post is written in
+// one locale and can be translated into each of the other supported
+// locales, with its own title, slug and content.
+type TranslationHandler struct {
+	db      *sql.DB
+	queries *db.Queries
+	locales i18n.Locales
+}
+
+func NewTranslationHandler(conn *sql.DB, locales i18n.Locales) *TranslationHandler {
+	return &TranslationHandler{db: conn, queries: db.New(conn), locales: locales}
+}
+
+// TranslationRequest is the full representation of a translation.
+type TranslationRequest struct {
+	Title         string `json:"title" binding:"required,min=1,max=255"`
+	Content       string `json:"content" binding:"required"`
+	ContentFormat string `json:"content_format" binding:"omitempty,oneof=plain markdown"`
+}
+
+// List godoc
+// @Summary List post translations
+// @Description Get the translations of a post by locale, with the locale the post is written in and the supported locales it has not been translated into yet
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/translations [get]
+func (h *TranslationHandler) List(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	post, ok := h.readablePost(c, int32(id))
+	if !ok {
+		return
+	}
+
+	translations, err := h.queries.ListPostTranslations(c.Request.Context(), post.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch translations"})
+		return
+	}
+
+	data := make([]gin.H, 0, len(translations))
+	for _, t := range translations {
+		data = append(data, translationResponse(t))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":            data,
+		"locale":          post.Locale,
+		"missing_locales": missingLocales(h.locales, post.Locale, translations),
+	})
+}
+
+// Get godoc
+// @Summary Get post translation
+// @Description Get the translation of a post into a locale
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param locale path string true "Locale"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/translations/{locale} [get]
+func (h *TranslationHandler) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	locale, ok := h.supportedLocale(c, c.Param("locale"))
+	if !ok {
+		return
+	}
+
+	post, ok := h.readablePost(c, int32(id))
+	if !ok {
+		return
+	}
+
+	translation, err := h.queries.GetPostTranslation(c.Request.Context(), db.GetPostTranslationParams{
+		PostID: post.ID,
+		Locale: locale,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Translation not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch translation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": translationResponse(translation)})
+}
+
+// Put godoc
+// @Summary Create or replace post translation
+// @Description Translate a post into a supported locale other than the one it is written in, or replace its existing translation. A unique slug is derived from the translated title; changing the title changes the slug, and the old slug keeps redirecting to the post. Only the owner and co-authors can translate a post.
+// @Tags posts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param locale path string true "Locale"
+// @Param request body TranslationRequest true "Translation"
+// @Success 200 {object} map[string]interface{}
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /posts/{id}/translations/{locale} [put]
+func (h *TranslationHandler) Put(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	locale, ok := h.supportedLocale(c, c.Param("locale"))
+	if !ok {
+		return
+	}
+
+	var req TranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.ContentFormat == "" {
+		req.ContentFormat = render.FormatPlain
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	contentHTML, err := render.HTML(req.ContentFormat, req.Content)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render content"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save translation"})
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := h.queries.WithTx(tx)
+
+	post, ok := translatablePost(c, qtx, int32(id), userID)
+	if !ok {
+		return
+	}
+
+	if post.Locale == locale {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Post is already written in " + locale})
+		return
+	}
+
+	current, err := qtx.GetPostTranslationForUpdate(ctx, db.GetPostTranslationForUpdateParams{
+		PostID: post.ID,
+		Locale: locale,
+	})
+	exists := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch translation"})
+		return
+	}
+
+	translationSlug := current.Slug
+	if !exists || req.Title != current.Title {
+		translationSlug, err = slugs.UniqueTranslation(ctx, qtx, req.Title, post.ID, locale)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save translation"})
+			return
+		}
+	}
+
+	if exists && translationSlug != current.Slug {
+		if err := qtx.AddSlugHistory(ctx, db.AddSlugHistoryParams{Slug: current.Slug, PostID: post.ID}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save translation"})
+			return
+		}
+	}
+	if !exists || translationSlug != current.Slug {
+		// A slug the post had before is reclaimed from the history.
+		if err := qtx.DeleteSlugHistory(ctx, db.DeleteSlugHistoryParams{Slug: translationSlug, PostID: post.ID}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save translation"})
+			return
+		}
+	}
+
+	var translation db.PostTranslation
+	if exists {
+		translation, err = qtx.UpdatePostTranslation(ctx, db.UpdatePostTranslationParams{
+			Title:         req.Title,
+			Slug:          translationSlug,
+			Content:       sql.NullString{String: req.Content, Valid: true},
+			ContentFormat: req.ContentFormat,
+			ContentHtml:   sql.NullString{String: contentHTML, Valid: true},
+			PostID:        post.ID,
+			Locale:        locale,
+		})
+	} else {
+		translation, err = qtx.CreatePostTranslation(ctx, db.CreatePostTranslationParams{
+			PostID:        post.ID,
+			Locale:        locale,
+			Title:         req.Title,
+			Slug:          translationSlug,
+			Content:       sql.NullString{String: req.Content, Valid: true},
+			ContentFormat: req.ContentFormat,
+			ContentHtml:   sql.NullString{String: contentHTML, Valid: true},
+		})
+	}
+	if isUniqueViolation(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Slug already taken, please retry"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save translation"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save translation"})
+		return
+	}
+
+	if exists {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Translation updated successfully",
+			"data":    translationResponse(translation),
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Translation created successfully",
+		"data":    translationResponse(translation),
+	})
+}
+
+// Delete godoc
+// @Summary Delete post translation
+// @Description Delete the translation of a post into a locale. Its slug keeps redirecting to the post. Only the owner and co-authors can delete translations.
+// @Tags posts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param locale path string true "Locale"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/translations/{locale} [delete]
+func (h *TranslationHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	locale, ok := h.supportedLocale(c, c.Param("locale"))
+	if !ok {
+		return
+	}
+
+	userID, ok := activeUserID(c, h.queries)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete translation"})
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := h.queries.WithTx(tx)
+
+	post, ok := translatablePost(c, qtx, int32(id), userID)
+	if !ok {
+		return
+	}
+
+	translation, err := qtx.GetPostTranslationForUpdate(ctx, db.GetPostTranslationForUpdateParams{
+		PostID: post.ID,
+		Locale: locale,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Translation not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch translation"})
+		return
+	}
+
+	if _, err := qtx.DeletePostTranslation(ctx, db.DeletePostTranslationParams{PostID: post.ID, Locale: locale}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete translation"})
+		return
+	}
+
+	// Links to the translation keep working and lead to the post.
+	if err := qtx.AddSlugHistory(ctx, db.AddSlugHistoryParams{Slug: translation.Slug, PostID: post.ID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete translation"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete translation"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Missing godoc
+// @Summary List posts missing a translation
+// @Description Get published posts that are neither written in nor translated into a locale, oldest first
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param locale query string true "Locale"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /posts/translations/missing [get]
+func (h *TranslationHandler) Missing(c *gin.Context) {
+	locale, ok := h.supportedLocale(c, c.Query("locale"))
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+	ctx := c.Request.Context()
+
+	rows, err := h.queries.ListPostsMissingTranslation(ctx, db.ListPostsMissingTranslationParams{
+		Locale: locale,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+		return
+	}
+
+	total, err := h.queries.CountPostsMissingTranslation(ctx, locale)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count posts"})
+		return
+	}
+
+	posts := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		post := postResponse(row.Post)
+		post["username"] = row.Username
+		posts = append(posts, post)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"posts": posts,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (int(total) + limit - 1) / limit,
+			"offset":      offset,
+		},
+	})
+}
+
+// supportedLocale normalizes a locale and checks that it is supported. It
+// writes the error response and returns false otherwise.
+func (h *TranslationHandler) supportedLocale(c *gin.Context, locale string) (string, bool) {
+	locale = i18n.Normalize(locale)
+	if !h.locales.Supports(locale) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid locale, must be one of " + strings.Join(h.locales.Supported, ", ")})
+		return "", false
+	}
+	return locale, true
+}
+
+// readablePost loads a post whose translations the caller may read. Posts
+// hidden by a moderator are readable by their authors only. It writes the
+// error response and returns false otherwise.
+func (h *TranslationHandler) readablePost(c *gin.Context, id int32) (db.Post, bool) {
+	ctx := c.Request.Context()
+
+	row, err := h.queries.GetPost(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return db.Post{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return db.Post{}, false
+	}
+
+	if row.Post.Status.String == "hidden" {
+		userID, authenticated := currentUserID(c)
+		author := false
+		if authenticated {
+			author, err = isPostAuthor(ctx, h.queries, row.Post.ID, userID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+				return db.Post{}, false
+			}
+		}
+		if !author {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+			return db.Post{}, false
+		}
+	}
+	return row.Post, true
+}
+
+// translatablePost locks a post whose translations the caller is about to
+// change. It writes the error response and returns false when the post is
+// missing, hidden, or the caller is not one of its authors.
+func translatablePost(c *gin.Context, qtx *db.Queries, id, userID int32) (db.Post, bool) {
+	ctx := c.Request.Context()
+
+	post, err := qtx.GetPostForUpdate(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return db.Post{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return db.Post{}, false
+	}
+
+	author, err := isPostAuthor(ctx, qtx, post.ID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return db.Post{}, false
+	}
+	if !author {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the authors can translate this post"})
+		return db.Post{}, false
+	}
+
+	if post.Status.String == "hidden" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Post has been hidden by a moderator"})
+		return db.Post{}, false
+	}
+	return post, true
+}
+
+// preferredLocales returns the supported locales the client asked for
+// with the lang query parameter and the Accept-Language header, most
+// preferred first. It writes the error response and returns false when lang
+// is not supported.
+func preferredLocales(c *gin.Context, locales i18n.Locales, fallback string) ([]string, bool) {
+	lang := c.Query("lang")
+	if lang != "" && !locales.Supports(lang) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lang, must be one of " + strings.Join(locales.Supported, ", ")})
+		return nil, false
+	}
+	if lang == "" {
+		lang = fallback
+	}
+	return locales.Preferences(lang, c.GetHeader("Accept-Language")), true
+}
+
+// localizePost returns a post in the first preferred locale it is available
+// in, or as written when none is. It also returns all locales the post is
+// available in, its own first, and the version of the translation used, or
+// zero when the post is returned as written.
+func localizePost(ctx context.Context, queries *db.Queries, p db.Post, preferences []string) (db.Post, []string, int32, error) {
+	translations, err := queries.ListPostTranslations(ctx, p.ID)
+	if err != nil {
+		return db.Post{}, nil, 0, err
+	}
+
+	available := []string{p.Locale}
+	for _, t := range translations {
+		available = append(available, t.Locale)
+	}
+
+	locale := i18n.Match(preferences, available)
+	for _, t := range translations {
+		if t.Locale == locale {
+			return translatedPost(p, t), available, t.Version, nil
+		}
+	}
+	return p, available, 0, nil
+}
+
+// localizePosts is localizePost for a page of posts, fetching their
+// translations at once.
+func localizePosts(ctx context.Context, queries *db.Queries, posts []db.Post, preferences []string) ([]db.Post, error) {
+	ids := make([]int32, 0, len(posts))
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+
+	translations, err := queries.ListTranslationsForPosts(ctx, db.ListTranslationsForPostsParams{
+		PostIds: ids,
+		Locales: preferences,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byPost := make(map[int32]map[string]db.PostTranslation)
+	for _, t := range translations {
+		if byPost[t.PostID] == nil {
+			byPost[t.PostID] = make(map[string]db.PostTranslation)
+		}
+		byPost[t.PostID][t.Locale] = t
+	}
+
+	localized := make([]db.Post, 0, len(posts))
+	for _, p := range posts {
+		available := []string{p.Locale}
+		for locale := range byPost[p.ID] {
+			available = append(available, locale)
+		}
+
+		if t, ok := byPost[p.ID][i18n.Match(preferences, available)]; ok {
+			p = translatedPost(p, t)
+		}
+		localized = append(localized, p)
+	}
+	return localized, nil
+}
+
+// translatedPost returns a post with its translatable fields and locale
+// taken from a translation.
+func translatedPost(p db.Post, t db.PostTranslation) db.Post {
+	p.Locale = t.Locale
+	p.Title = t.Title
+	p.Slug = t.Slug
+	p.Content = t.Content
+	p.ContentFormat = t.ContentFormat
+	p.ContentHtml = t.ContentHtml
+	return p
+}
+
+// missingLocales returns the supported locales a post is neither written in
+// nor translated into.
+func missingLocales(locales i18n.Locales, postLocale string, translations []db.PostTranslation) []string {
+	missing := make([]string, 0, len(locales.Supported))
+	for _, locale := range locales.Supported {
+		if locale == postLocale || slices.ContainsFunc(translations, func(t db.PostTranslation) bool { return t.Locale == locale }) {
+			continue
+		}
+		missing = append(missing, locale)
+	}
+	return missing
+}
+
+// translationResponse converts a translation into its JSON representation.
+func translationResponse(t db.PostTranslation) gin.H {
+	return gin.H{
+		"post_id":        t.PostID,
+		"locale":         t.Locale,
+		"title":          t.Title,
+		"slug":           t.Slug,
+		"content":        nullString(t.Content),
+		"content_format": t.ContentFormat,
+		"created_at":     nullTime(t.CreatedAt),
+		"updated_at":     nullTime(t.UpdatedAt),
+		"version":        t.Version,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// maxStatsDays is the longest daily view history an author can request. It
+// matches how long the trending job keeps hourly view counts.
+const maxStatsDays = 90
+
+// Trending godoc
+// @Summary List trending posts
+// @Description Get published posts ranked by a time-decayed view score, refreshed periodically. Posts by muted authors and authors who blocked the caller are left out.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of posts" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Router /posts/trending [get]
+func (h *PostHandler) Trending(c *gin.Context) {
+	limit := rankingLimit(c)
+	ctx := c.Request.Context()
+
+	userID, authenticated := currentUserID(c)
+
+	rows, err := h.queries.ListTrendingPosts(ctx, db.ListTrendingPostsParams{
+		ViewerID: sql.NullInt32{Int32: userID, Valid: authenticated},
+		Limit:    int32(limit),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+		return
+	}
+
+	posts := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		posts = append(posts, rankedPost(row.Post, row.Username, row.CommentCount, row.ViewCount, row.TrendingScore))
+	}
+
+	if err := attachReactions(ctx, h.queries, userID, authenticated, posts); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"posts": posts})
+}
+
+// MostRead godoc
+// @Summary List most read posts
+// @Description Get published posts with the most views of all time. Posts by muted authors and authors who blocked the caller are left out.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of posts" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Router /posts/most-read [get]
+func (h *PostHandler) MostRead(c *gin.Context) {
+	limit := rankingLimit(c)
+	ctx := c.Request.Context()
+
+	userID, authenticated := currentUserID(c)
+
+	rows, err := h.queries.ListMostReadPosts(ctx, db.ListMostReadPostsParams{
+		ViewerID: sql.NullInt32{Int32: userID, Valid: authenticated},
+		Limit:    int32(limit),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+		return
+	}
+
+	posts := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		posts = append(posts, rankedPost(row.Post, row.Username, row.CommentCount, row.ViewCount, row.TrendingScore))
+	}
+
+	if err := attachReactions(ctx, h.queries, userID, authenticated, posts); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"posts": posts})
+}
+
+// Stats godoc
+// @Summary Get post view stats
+// @Description Get view totals and daily views (UTC) of a post you own or co-authored. Views are written in batches, so the latest few seconds may be missing.
+// @Tags posts
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param days query int false "Days of daily views, at most 90" default(30)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /posts/{id}/stats [get]
+func (h *PostHandler) Stats(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > maxStatsDays {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days, must be between 1 and 90"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	row, err := h.queries.GetPost(ctx, int32(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+	post := row.Post
+
+	author, err := isPostAuthor(ctx, h.queries, post.ID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post"})
+		return
+	}
+	if !author {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the authors can view stats of this post"})
+		return
+	}
+
+	now := time.Now()
+
+	stats, err := h.queries.GetPostStats(ctx, db.GetPostStatsParams{
+		PostID:    post.ID,
+		DaySince:  now.Add(-24 * time.Hour),
+		WeekSince: now.Add(-7 * 24 * time.Hour),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stats"})
+		return
+	}
+
+	today := now.UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, 1-days)
+
+	rows, err := h.queries.ListPostDailyViews(ctx, db.ListPostDailyViewsParams{
+		PostID: post.ID,
+		Since:  since,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stats"})
+		return
+	}
+
+	// Days without views are filled in so the series has one entry per day.
+	byDay := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		byDay[row.Day.Format("2006-01-02")] = row.Views
+	}
+	daily := make([]gin.H, 0, days)
+	for day := since; !day.After(today); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		daily = append(daily, gin.H{"date": date, "views": byDay[date]})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"post_id":         post.ID,
+		"view_count":      stats.ViewCount,
+		"views_last_day":  stats.ViewsLastDay,
+		"views_last_week": stats.ViewsLastWeek,
+		"trending_score":  stats.TrendingScore,
+		"daily":           daily,
+	}})
+}
+
+// rankingLimit returns the number of posts requested from a ranked list.
+func rankingLimit(c *gin.Context) int {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	return limit
+}
+
+// rankedPost converts a post from a ranked list into its JSON
+// representation.
+func rankedPost(p db.Post, username string, commentCount, viewCount int64, trendingScore float64) gin.H {
+	post := postResponse(p)
+	post["username"] = username
+	post["comment_count"] = commentCount
+	post["view_count"] = viewCount
+	post["trending_score"] = trendingScore
+	return post
+}
+
+// visitorKey identifies the reader of a post for view de-duplication:
+// signed-in users by ID, everyone else by address and user agent.
+func visitorKey(c *gin.Context, userID int32, authenticated bool) string {
+	if authenticated {
+		return "user:" + strconv.Itoa(int(userID))
+	}
+	return "anon:" + c.ClientIP() + "|" + c.Request.UserAgent()
+}
\ No newline at end of file
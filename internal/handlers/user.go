@@ -2,33 +2,85 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/demo/demo-gin/internal/db/listing"
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/events"
+	"github.com/demo/demo-gin/internal/listquery"
 	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
 )
 
+// userFilters are the fields users can be filtered and sorted on.
+var userFilters = &listquery.Schema{
+	Fields: map[string]listquery.Field{
+		"username":   {Column: "username", Sortable: true},
+		"email":      {Column: "email"},
+		"full_name":  {Column: "full_name", Sortable: true},
+		"location":   {Column: "location"},
+		"role":       {Column: "role", Values: []string{"user", "editor", "admin"}},
+		"created_at": {Column: "created_at", Type: listquery.Time, Sortable: true},
+		"updated_at": {Column: "updated_at", Type: listquery.Time, Sortable: true},
+	},
+	DefaultSort: "-created_at",
+	Tiebreaker:  "id",
+}
+
 type UserHandler struct {
-	db *sql.DB
+	db      *sql.DB
+	queries *db.Queries
+	lists   *listing.Queries
+	bus     *events.Bus
 }
 
-func NewUserHandler(db *sql.DB) *UserHandler {
-	return &UserHandler{db: db}
+func NewUserHandler(conn *sql.DB, bus *events.Bus) *UserHandler {
+	return &UserHandler{db: conn, queries: db.New(conn), lists: listing.New(conn), bus: bus}
+}
+
+// UpdateUserRequest is the full representation of an editable account. PUT
+// replaces the account with it, and PATCH validates the patched account
+// against it. Whether the account is active is left out: only moderators
+// can suspend and reinstate accounts.
+type UpdateUserRequest struct {
+	Email    string  `json:"email" binding:"required,email"`
+	Username string  `json:"username" binding:"required,min=3,max=30"`
+	FullName *string `json:"full_name" binding:"omitempty,max=255"`
+	Bio      *string `json:"bio" binding:"omitempty,max=500"`
+	Website  *string `json:"website" binding:"omitempty,url,max=255"`
+	Location *string `json:"location" binding:"omitempty,max=100"`
 }
 
 // List godoc
 // @Summary List users
-// @Description Get a list of active users
+// @Description Get a list of active users, leaving out users who blocked you. Filter with filter[field]=value or filter[field][operator]=value and sort with a comma-separated list of fields, each prefixed with - for descending order.
 // @Tags users
 // @Security Bearer
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param filter[field][operator] query string false "Filter on username, email, full_name, location, role, created_at or updated_at with eq (the default), ne, gt, gte, lt, lte, in or contains"
+// @Param sort query string false "Sort on username, full_name, created_at or updated_at" default(-created_at)
+// @Param fields query string false "Comma-separated fields to return; id is always returned"
+// @Param include query string false "Comma-separated relations to embed (counts)"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Router /users [get]
 func (h *UserHandler) List(c *gin.Context) {
+	filter, ok := listFilter(c, userFilters)
+	if !ok {
+		return
+	}
+
+	fieldset, ok := responseFieldset(c, userFields, userRelations)
+	if !ok {
+		return
+	}
+
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
@@ -40,27 +92,46 @@ func (h *UserHandler) List(c *gin.Context) {
 	}
 
 	offset := (page - 1) * limit
+	ctx := c.Request.Context()
+
+	viewerID, authenticated := currentUserID(c)
+	viewer := sql.NullInt32{Int32: viewerID, Valid: authenticated}
+
+	rows, err := h.lists.ListUsers(ctx, listing.ListUsersParams{
+		ViewerID: viewer,
+		Filter:   filter,
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+		return
+	}
+
+	total, err := h.lists.CountUsers(ctx, viewer, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count users"})
+		return
+	}
 
-	// TODO: Fetch users from database using sqlc
+	base := baseURL(c, "/users")
+	users := make([]gin.H, 0, len(rows))
+	for _, u := range rows {
+		users = append(users, userResponse(base, u))
+	}
+
+	if err := includeUserRelations(ctx, h.queries, viewer, fieldset, users); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"users": []gin.H{
-			{
-				"id":       1,
-				"email":    "user1@example.com",
-				"username": "user1",
-			},
-			{
-				"id":       2,
-				"email":    "user2@example.com",
-				"username": "user2",
-			},
-		},
+		"users": users,
 		"pagination": gin.H{
 			"page":        page,
 			"limit":       limit,
-			"total":       2,
-			"total_pages": 1,
+			"total":       total,
+			"total_pages": (int(total) + limit - 1) / limit,
 			"offset":      offset,
 		},
 	})
@@ -68,13 +139,18 @@ func (h *UserHandler) List(c *gin.Context) {
 
 // Get godoc
 // @Summary Get user by ID
-// @Description Get user details by ID
+// @Description Get user details by ID. Users who blocked you are reported as missing. Send the ETag header back in If-None-Match to get a 304 while the user is unchanged; with include=counts the tag is weak and also tracks the counts.
 // @Tags users
 // @Security Bearer
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID"
+// @Param fields query string false "Comma-separated fields to return; id is always returned"
+// @Param include query string false "Comma-separated relations to embed (counts)"
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} map[string]interface{}
+// @Success 304
+// @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Router /users/{id} [get]
@@ -85,34 +161,178 @@ func (h *UserHandler) Get(c *gin.Context) {
 		return
 	}
 
-	// TODO: Fetch user from database using sqlc
+	fieldset, ok := responseFieldset(c, userFields, userRelations)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	user, err := h.queries.GetUser(ctx, int32(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return
+	}
+
+	// Users who blocked the caller look like they do not exist.
+	viewerID, authenticated := currentUserID(c)
+	if authenticated {
+		blocked, err := blockedByAny(ctx, h.queries, viewerID, user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+			return
+		}
+		if blocked {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+	}
+	viewer := sql.NullInt32{Int32: viewerID, Valid: authenticated}
+
+	data := userResponse(baseURL(c, "/users/"), user)
+	if err := includeUserRelations(ctx, h.queries, viewer, fieldset, []gin.H{data}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return
+	}
+
+	tag := userETag(user.Version, data)
+	c.Header("ETag", tag)
+	if ifNoneMatchTag(c, tag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// Profile godoc
+// @Summary Get public profile
+// @Description Get the public profile of an active user with follower counts and their published posts, newest first. Email and account state are not included.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /users/{id}/profile [get]
+func (h *UserHandler) Profile(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+	ctx := c.Request.Context()
+
+	user, err := h.queries.GetUser(ctx, int32(id))
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && isSuspended(user)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return
+	}
+
+	// Users who blocked the caller look like they do not exist.
+	viewerID, authenticated := currentUserID(c)
+	if authenticated {
+		blocked, err := blockedByAny(ctx, h.queries, viewerID, user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+			return
+		}
+		if blocked {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+	}
+
+	published := sql.NullString{String: "published", Valid: true}
+	rows, err := h.queries.ListUserPosts(ctx, db.ListUserPostsParams{
+		UserID: user.ID,
+		Status: published,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+		return
+	}
+
+	total, err := h.queries.CountUserPosts(ctx, db.CountUserPostsParams{UserID: user.ID, Status: published})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count posts"})
+		return
+	}
+
+	counts, err := h.queries.GetFollowCounts(ctx, db.GetFollowCountsParams{
+		UserID:   user.ID,
+		ViewerID: sql.NullInt32{Int32: viewerID, Valid: authenticated},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return
+	}
+
+	posts := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		post := postResponse(row)
+		post["username"] = user.Username
+		posts = append(posts, post)
+	}
+
+	profile := profileResponse(baseURL(c, "/users/"), user)
+	profile["follower_count"] = counts.FollowerCount
+	profile["following_count"] = counts.FollowingCount
 
 	c.JSON(http.StatusOK, gin.H{
-		"data": gin.H{
-			"id":         id,
-			"email":      "user@example.com",
-			"username":   "user",
-			"full_name":  "John Doe",
-			"is_active":  true,
-			"created_at": "2024-01-01T00:00:00Z",
-			"updated_at": "2024-01-01T00:00:00Z",
+		"data":  profile,
+		"posts": posts,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (int(total) + limit - 1) / limit,
+			"offset":      offset,
 		},
 	})
 }
 
 // Update godoc
-// @Summary Update user
-// @Description Update user details
+// @Summary Replace user
+// @Description Replace your own account details; an omitted full_name is cleared. Send the ETag from GET in If-Match to reject the update with 412 if the account changed in the meantime.
 // @Tags users
 // @Security Bearer
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID"
-// @Param request body map[string]interface{} true "User update details"
+// @Param If-Match header string false "ETag of the user being edited"
+// @Param request body UpdateUserRequest true "User update details"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
 // @Router /users/{id} [put]
 func (h *UserHandler) Update(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -121,33 +341,168 @@ func (h *UserHandler) Update(c *gin.Context) {
 		return
 	}
 
-	var req map[string]interface{}
+	var req UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// TODO: Update user in database using sqlc
+	current, conditional, ok := h.editableUser(c, int32(id), "update")
+	if !ok {
+		return
+	}
 
+	user, err := h.queries.UpdateUser(c.Request.Context(), updateUserParams(current.ID, req, sql.NullInt32{Int32: current.Version, Valid: conditional}))
+	if errors.Is(err, sql.ErrNoRows) {
+		if conditional {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": "User has been modified"})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if isUniqueViolation(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Email or username already taken"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		return
+	}
+
+	h.emitUserChange(c, events.UserUpdated, user.ID, user)
+
+	c.Header("ETag", etag(user.Version))
 	c.JSON(http.StatusOK, gin.H{
 		"message": "User updated successfully",
-		"data": gin.H{
-			"id": id,
-		},
+		"data":    userResponse(baseURL(c, "/users/"), user),
+	})
+}
+
+// Patch godoc
+// @Summary Patch user
+// @Description Apply a JSON Merge Patch (RFC 7396) to your own account: members set to null are cleared and omitted members are left as they are. The patched account must pass the same validation as PUT.
+// @Tags users
+// @Security Bearer
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param If-Match header string false "ETag of the user being edited"
+// @Param request body map[string]interface{} true "Merge patch"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Router /users/{id} [patch]
+func (h *UserHandler) Patch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	patch, supported, err := readMergePatch(c)
+	if !supported {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type must be " + mergePatchContentType})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	if int32(id) != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only update your own account"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := h.queries.WithTx(tx)
+
+	// Lock the row so the patch applies to the version it was computed from.
+	current, err := qtx.GetUserForUpdate(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return
+	}
+
+	if isSuspended(current) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Your account is suspended"})
+		return
+	}
+
+	if conditional, matched := ifMatch(c, current.Version); conditional && !matched {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": "User has been modified"})
+		return
+	}
+
+	var req UpdateUserRequest
+	document := mergePatch(userDocument(current), patch).(map[string]interface{})
+	if err := decodePatched(document, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := qtx.UpdateUser(ctx, updateUserParams(current.ID, req, sql.NullInt32{}))
+	if isUniqueViolation(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Email or username already taken"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		return
+	}
+
+	h.emitUserChange(c, events.UserUpdated, user.ID, user)
+
+	c.Header("ETag", etag(user.Version))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User updated successfully",
+		"data":    userResponse(baseURL(c, "/users/"), user),
 	})
 }
 
 // Delete godoc
 // @Summary Delete user
-// @Description Delete a user account
+// @Description Delete your own account. It moves to the trash together with your posts, where an admin can restore it until it is removed for good after the retention period. Send the ETag from GET in If-Match to reject the delete with 412 if the account changed in the meantime.
 // @Tags users
 // @Security Bearer
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID"
+// @Param If-Match header string false "ETag of the user being deleted"
 // @Success 204
 // @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 412 {object} map[string]interface{}
 // @Router /users/{id} [delete]
 func (h *UserHandler) Delete(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -156,8 +511,289 @@ func (h *UserHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	// TODO: Delete user from database using sqlc
+	current, conditional, ok := h.editableUser(c, int32(id), "delete")
+	if !ok {
+		return
+	}
+
+	deleted, err := h.queries.TrashUser(c.Request.Context(), db.TrashUserParams{
+		ID:      current.ID,
+		Version: sql.NullInt32{Int32: current.Version, Valid: conditional},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
+		return
+	}
+	if deleted == 0 {
+		if conditional {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": "User has been modified"})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	h.emitUserChange(c, events.UserDeleted, current.ID, current)
 
 	c.Status(http.StatusNoContent)
-	_ = id
+}
+
+// Trash godoc
+// @Summary List deleted users
+// @Description Get deleted accounts, most recently deleted first. They are removed for good after the retention period. Admins only.
+// @Tags users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /users/trash [get]
+func (h *UserHandler) Trash(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+
+	if _, ok := currentUserWithRole(c, h.queries, "Only admins can manage deleted accounts", roleAdmin); !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	rows, err := h.queries.ListTrashedUsers(ctx, db.ListTrashedUsersParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+		return
+	}
+
+	total, err := h.queries.CountTrashedUsers(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count users"})
+		return
+	}
+
+	base := baseURL(c, "/users/")
+	users := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		user := userResponse(base, row)
+		user["deleted_at"] = nullTime(row.DeletedAt)
+		users = append(users, user)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users": users,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (int(total) + limit - 1) / limit,
+			"offset":      offset,
+		},
+	})
+}
+
+// Restore godoc
+// @Summary Restore user
+// @Description Move a deleted account and its posts out of the trash. Posts the user deleted themselves stay in the trash. Admins only.
+// @Tags users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /users/{id}/restore [post]
+func (h *UserHandler) Restore(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	admin, ok := currentUserWithRole(c, h.queries, "Only admins can manage deleted accounts", roleAdmin)
+	if !ok {
+		return
+	}
+
+	user, err := h.queries.RestoreUser(c.Request.Context(), int32(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found in trash"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore user"})
+		return
+	}
+
+	h.emitUserChange(c, events.UserRestored, admin.ID, user)
+
+	c.Header("ETag", etag(user.Version))
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User restored successfully",
+		"data":    userResponse(baseURL(c, "/users/"), user),
+	})
+}
+
+// emitUserChange announces a write to an account. The event carries the
+// public profile, since hooks may forward it outside the application.
+func (h *UserHandler) emitUserChange(c *gin.Context, eventType string, actorID int32, user db.User) {
+	h.bus.Emit(c.Request.Context(), events.Event{
+		Type:    eventType,
+		ActorID: actorID,
+		UserID:  user.ID,
+		Public:  true,
+		Data:    profileResponse(baseURL(c, "/users/"), user),
+	})
+}
+
+// editableUser authenticates the caller, loads their own account, checks
+// that it has not been suspended and checks If-Match against it. It writes
+// the error response and returns false when any of these fail; conditional
+// reports whether If-Match was sent.
+func (h *UserHandler) editableUser(c *gin.Context, id int32, action string) (user db.User, conditional, ok bool) {
+	userID, authenticated := currentUserID(c)
+	if !authenticated {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return db.User{}, false, false
+	}
+
+	if id != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only " + action + " your own account"})
+		return db.User{}, false, false
+	}
+
+	user, err := h.queries.GetUser(c.Request.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return db.User{}, false, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return db.User{}, false, false
+	}
+
+	if isSuspended(user) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Your account is suspended"})
+		return db.User{}, false, false
+	}
+
+	conditional, matched := ifMatch(c, user.Version)
+	if conditional && !matched {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": "User has been modified"})
+		return db.User{}, false, false
+	}
+	return user, conditional, true
+}
+
+// userDocument returns the editable fields of an account in the shape of
+// UpdateUserRequest, leaving out fields that are NULL.
+func userDocument(u db.User) map[string]interface{} {
+	document := map[string]interface{}{
+		"email":    u.Email,
+		"username": u.Username,
+	}
+	if u.FullName.Valid {
+		document["full_name"] = u.FullName.String
+	}
+	if u.Bio.Valid {
+		document["bio"] = u.Bio.String
+	}
+	if u.Website.Valid {
+		document["website"] = u.Website.String
+	}
+	if u.Location.Valid {
+		document["location"] = u.Location.String
+	}
+	return document
+}
+
+// updateUserParams maps a full account representation onto UpdateUser.
+func updateUserParams(id int32, req UpdateUserRequest, version sql.NullInt32) db.UpdateUserParams {
+	params := db.UpdateUserParams{
+		Email:    req.Email,
+		Username: req.Username,
+		ID:       id,
+		Version:  version,
+	}
+	if req.FullName != nil {
+		params.FullName = sql.NullString{String: *req.FullName, Valid: true}
+	}
+	if req.Bio != nil {
+		params.Bio = sql.NullString{String: *req.Bio, Valid: true}
+	}
+	if req.Website != nil {
+		params.Website = sql.NullString{String: *req.Website, Valid: true}
+	}
+	if req.Location != nil {
+		params.Location = sql.NullString{String: *req.Location, Valid: true}
+	}
+	return params
+}
+
+// isUniqueViolation reports whether err is a PostgreSQL unique constraint
+// violation.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// userETag is the read tag of a user response. With include=counts it is a
+// weak tag that also covers the counts, which change without the user.
+func userETag(version int32, user gin.H) string {
+	counts, ok := user["counts"].(gin.H)
+	if !ok {
+		return etag(version)
+	}
+	return readETag(version, counts["followers"], counts["following"], counts["posts"])
+}
+
+// userResponse converts a user row into its JSON representation, leaving out
+// the password hash. Avatar URLs are relative to base.
+func userResponse(base string, u db.User) gin.H {
+	return gin.H{
+		"id":                   u.ID,
+		"email":                u.Email,
+		"username":             u.Username,
+		"full_name":            nullString(u.FullName),
+		"bio":                  nullString(u.Bio),
+		"website":              nullString(u.Website),
+		"location":             nullString(u.Location),
+		"avatar":               avatarURLs(base, u),
+		"role":                 u.Role,
+		"is_active":            u.IsActive.Bool,
+		"created_at":           nullTime(u.CreatedAt),
+		"updated_at":           nullTime(u.UpdatedAt),
+		"version":              u.Version,
+		"erasure_scheduled_at": nullTime(u.ErasureScheduledAt),
+	}
+}
+
+// profileResponse converts a user row into its public representation, which
+// leaves out the email address and account state.
+func profileResponse(base string, u db.User) gin.H {
+	return gin.H{
+		"id":         u.ID,
+		"username":   u.Username,
+		"full_name":  nullString(u.FullName),
+		"bio":        nullString(u.Bio),
+		"website":    nullString(u.Website),
+		"location":   nullString(u.Location),
+		"avatar":     avatarURLs(base, u),
+		"created_at": nullTime(u.CreatedAt),
+	}
 }
\ No newline at end of file
@@ -0,0 +1,477 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/webhooks"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	db      *sql.DB
+	queries *db.Queries
+}
+
+func NewWebhookHandler(conn *sql.DB) *WebhookHandler {
+	return &WebhookHandler{db: conn, queries: db.New(conn)}
+}
+
+// CreateWebhookRequest subscribes a URL to events. A secret is generated
+// when none is given.
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,url,max=2048"`
+	EventTypes []string `json:"event_types" binding:"required,min=1"`
+	Secret     string   `json:"secret" binding:"omitempty,min=16,max=255"`
+}
+
+// UpdateWebhookRequest replaces the subscription of a webhook. Setting
+// active to true enables a disabled webhook again and clears its failures.
+type UpdateWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,url,max=2048"`
+	EventTypes []string `json:"event_types" binding:"required,min=1"`
+	Active     *bool    `json:"active" binding:"required"`
+}
+
+// Create godoc
+// @Summary Create webhook
+// @Description Subscribe a URL to events. Deliveries are POSTed as JSON and signed with the secret in the X-Webhook-Signature header. The secret is only returned here. Admins only.
+// @Tags webhooks
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body CreateWebhookRequest true "Webhook details"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /webhooks [post]
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if msg := validateWebhook(req.URL, req.EventTypes); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	admin, ok := currentUserWithRole(c, h.queries, "Only admins can manage webhooks", roleAdmin)
+	if !ok {
+		return
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = webhooks.NewSecret(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create webhook"})
+			return
+		}
+	}
+
+	webhook, err := h.queries.CreateWebhook(c.Request.Context(), db.CreateWebhookParams{
+		UserID:     admin.ID,
+		Url:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     secret,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create webhook"})
+		return
+	}
+
+	data := webhookResponse(webhook)
+	data["secret"] = webhook.Secret
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Webhook created successfully",
+		"data":    data,
+	})
+}
+
+// List godoc
+// @Summary List webhooks
+// @Description Get every webhook. Admins only.
+// @Tags webhooks
+// @Security Bearer
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /webhooks [get]
+func (h *WebhookHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+
+	if _, ok := currentUserWithRole(c, h.queries, "Only admins can manage webhooks", roleAdmin); !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	rows, err := h.queries.ListWebhooks(ctx, db.ListWebhooksParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch webhooks"})
+		return
+	}
+
+	total, err := h.queries.CountWebhooks(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count webhooks"})
+		return
+	}
+
+	items := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, webhookResponse(row))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks": items,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (int(total) + limit - 1) / limit,
+			"offset":      offset,
+		},
+	})
+}
+
+// Get godoc
+// @Summary Get webhook
+// @Description Get a webhook by ID. Admins only.
+// @Tags webhooks
+// @Security Bearer
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return
+	}
+
+	if _, ok := currentUserWithRole(c, h.queries, "Only admins can manage webhooks", roleAdmin); !ok {
+		return
+	}
+
+	webhook, err := h.queries.GetWebhook(c.Request.Context(), int32(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch webhook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": webhookResponse(webhook)})
+}
+
+// Update godoc
+// @Summary Update webhook
+// @Description Replace the URL, event types and state of a webhook. Setting active to true enables a webhook that was disabled after repeated failures, and its queued deliveries are sent again. Admins only.
+// @Tags webhooks
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param request body UpdateWebhookRequest true "Webhook details"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return
+	}
+
+	var req UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if msg := validateWebhook(req.URL, req.EventTypes); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	if _, ok := currentUserWithRole(c, h.queries, "Only admins can manage webhooks", roleAdmin); !ok {
+		return
+	}
+
+	webhook, err := h.queries.UpdateWebhook(c.Request.Context(), db.UpdateWebhookParams{
+		ID:         int32(id),
+		Url:        req.URL,
+		EventTypes: req.EventTypes,
+		Active:     *req.Active,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update webhook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook updated successfully",
+		"data":    webhookResponse(webhook),
+	})
+}
+
+// Delete godoc
+// @Summary Delete webhook
+// @Description Delete a webhook together with its delivery log. Queued deliveries are dropped. Admins only.
+// @Tags webhooks
+// @Security Bearer
+// @Param id path int true "Webhook ID"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return
+	}
+
+	if _, ok := currentUserWithRole(c, h.queries, "Only admins can manage webhooks", roleAdmin); !ok {
+		return
+	}
+
+	deleted, err := h.queries.DeleteWebhook(c.Request.Context(), int32(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete webhook"})
+		return
+	}
+	if deleted == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Deliveries godoc
+// @Summary List webhook deliveries
+// @Description Get the delivery log of a webhook, most recent first: the payload, status, number of attempts, time of the next attempt and the last response or error of every delivery. Admins only.
+// @Tags webhooks
+// @Security Bearer
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) Deliveries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+
+	if _, ok := currentUserWithRole(c, h.queries, "Only admins can manage webhooks", roleAdmin); !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if _, err := h.queries.GetWebhook(ctx, int32(id)); errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch webhook"})
+		return
+	}
+
+	rows, err := h.queries.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		WebhookID: int32(id),
+		Limit:     int32(limit),
+		Offset:    int32(offset),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch deliveries"})
+		return
+	}
+
+	total, err := h.queries.CountWebhookDeliveries(ctx, int32(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count deliveries"})
+		return
+	}
+
+	items := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, webhookDeliveryResponse(row))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": items,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (int(total) + limit - 1) / limit,
+			"offset":      offset,
+		},
+	})
+}
+
+// Redeliver godoc
+// @Summary Redeliver webhook delivery
+// @Description Queue a new delivery of the same event, whatever the outcome of the original. Receivers can recognise the repeat by the event ID in the payload. Admins only.
+// @Tags webhooks
+// @Security Bearer
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param delivery_id path int true "Delivery ID"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return
+	}
+
+	deliveryID, err := strconv.ParseInt(c.Param("delivery_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID"})
+		return
+	}
+
+	if _, ok := currentUserWithRole(c, h.queries, "Only admins can manage webhooks", roleAdmin); !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	original, err := h.queries.GetWebhookDelivery(ctx, db.GetWebhookDeliveryParams{ID: deliveryID, WebhookID: int32(id)})
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch delivery"})
+		return
+	}
+
+	delivery, err := h.queries.RedeliverWebhookDelivery(ctx, original.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to queue delivery"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Delivery queued successfully",
+		"data":    webhookDeliveryResponse(delivery),
+	})
+}
+
+// validateWebhook checks what binding cannot: that the URL is http(s) and
+// every event type is supported. It returns the error message, or "" when
+// both are valid.
+func validateWebhook(rawURL string, eventTypes []string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "URL must be an http or https URL"
+	}
+	for _, eventType := range eventTypes {
+		if !webhooks.Supported(eventType) {
+			return "Unknown event type " + strconv.Quote(eventType) + ", must be one of " + strings.Join(webhooks.EventTypes, ", ")
+		}
+	}
+	return ""
+}
+
+// webhookResponse converts a webhook row into its JSON representation,
+// leaving out the secret.
+func webhookResponse(w db.Webhook) gin.H {
+	return gin.H{
+		"id":            w.ID,
+		"user_id":       w.UserID,
+		"url":           w.Url,
+		"event_types":   w.EventTypes,
+		"active":        w.Active,
+		"failure_count": w.FailureCount,
+		"disabled_at":   nullTime(w.DisabledAt),
+		"created_at":    nullTime(w.CreatedAt),
+		"updated_at":    nullTime(w.UpdatedAt),
+	}
+}
+
+// webhookDeliveryResponse converts a delivery row into its JSON
+// representation.
+func webhookDeliveryResponse(d db.WebhookDelivery) gin.H {
+	return gin.H{
+		"id":              d.ID,
+		"webhook_id":      d.WebhookID,
+		"event_id":        d.EventID,
+		"event_type":      d.EventType,
+		"payload":         d.Payload,
+		"status":          d.Status,
+		"attempts":        d.Attempts,
+		"next_attempt_at": d.NextAttemptAt,
+		"last_attempt_at": nullTime(d.LastAttemptAt),
+		"response_status": nullInt32(d.ResponseStatus),
+		"response_body":   nullString(d.ResponseBody),
+		"error":           nullString(d.Error),
+		"created_at":      nullTime(d.CreatedAt),
+	}
+}
\ No newline at end of file
@@ -0,0 +1,126 @@
+// Package i18n negotiates which of the supported locales a response is
+// written in.
+package i18n
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locales is the set of locales content can be written in. Default is the
+// locale used when none of the requested ones is available.
+type Locales struct {
+	Default   string
+	Supported []string
+}
+
+// New returns the locales with every tag normalized. The default locale is
+// always supported.
+func New(defaultLocale string, supported []string) Locales {
+	l := Locales{Default: Normalize(defaultLocale)}
+	for _, tag := range supported {
+		if tag = Normalize(tag); tag != "" && !slices.Contains(l.Supported, tag) {
+			l.Supported = append(l.Supported, tag)
+		}
+	}
+	if l.Default != "" && !slices.Contains(l.Supported, l.Default) {
+		l.Supported = append([]string{l.Default}, l.Supported...)
+	}
+	return l
+}
+
+// Normalize lowercases a language tag and uses hyphens as separators, so
+// "zh_CN" and "zh-cn" compare equal.
+func Normalize(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+}
+
+// Supports reports whether locale is one of the supported locales.
+func (l Locales) Supports(locale string) bool {
+	return slices.Contains(l.Supported, Normalize(locale))
+}
+
+// Preferences returns the supported locales a client asked for, most
+// preferred first: lang if set, then the Accept-Language header by quality,
+// then the default locale. A requested tag that is not supported matches
+// its base language, so "en-US" selects "en".
+func (l Locales) Preferences(lang, acceptLanguage string) []string {
+	var preferences []string
+	add := func(tag string) {
+		tag = Normalize(tag)
+		if !l.Supports(tag) {
+			base, _, found := strings.Cut(tag, "-")
+			if !found || !l.Supports(base) {
+				return
+			}
+			tag = base
+		}
+		if !slices.Contains(preferences, tag) {
+			preferences = append(preferences, tag)
+		}
+	}
+
+	if lang != "" {
+		add(lang)
+	}
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		add(tag)
+	}
+	if l.Default != "" {
+		add(l.Default)
+	}
+	return preferences
+}
+
+// Match returns the first preference that is available, or "" when none
+// is.
+func Match(preferences, available []string) string {
+	for _, locale := range preferences {
+		if slices.Contains(available, locale) {
+			return locale
+		}
+	}
+	return ""
+}
+
+// parseAcceptLanguage returns the tags of an Accept-Language header ordered
+// by quality, leaving out the wildcard and tags with a quality of zero.
+// Tags of equal quality keep their order.
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = q
+		}
+		if quality <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag: tag, quality: quality})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].quality > tags[j].quality })
+
+	ordered := make([]string, 0, len(tags))
+	for _, t := range tags {
+		ordered = append(ordered, t.tag)
+	}
+	return ordered
+}
\ No newline at end of file
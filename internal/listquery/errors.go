@@ -0,0 +1,20 @@
+package listquery
+
+import "strings"
+
+// Error is a problem with one query parameter.
+type Error struct {
+	Parameter string `json:"parameter"`
+	Message   string `json:"message"`
+}
+
+// Errors lists the problems found by Parse.
+type Errors []Error
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Parameter+": "+err.Message)
+	}
+	return strings.Join(messages, "; ")
+}
\ No newline at end of file
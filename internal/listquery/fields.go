@@ -0,0 +1,83 @@
+package listquery
+
+import (
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Fieldset is the response shape asked for with the fields and include
+// parameters: ?fields=id,title&include=author,tags.
+type Fieldset struct {
+	// fields is nil when every field is selected.
+	fields  map[string]bool
+	include map[string]bool
+}
+
+// ParseFieldset reads the fields and include parameters, accepting the
+// given fields and relations. The id field is always selected, and
+// included relations are returned whatever the fields.
+func ParseFieldset(values url.Values, fields, relations []string) (Fieldset, error) {
+	var fieldset Fieldset
+	var errs Errors
+
+	parse := func(parameter string, allowed []string) map[string]bool {
+		list, ok := values[parameter]
+		if !ok {
+			return nil
+		}
+		if len(list) != 1 {
+			errs = append(errs, Error{Parameter: parameter, Message: "Must be given once"})
+			return nil
+		}
+
+		selected := make(map[string]bool)
+		for _, name := range strings.Split(list[0], ",") {
+			name = strings.TrimSpace(name)
+			if !slices.Contains(allowed, name) {
+				errs = append(errs, Error{
+					Parameter: parameter,
+					Message:   "Unknown " + strconv.Quote(name) + ", must be one of " + strings.Join(allowed, ", "),
+				})
+				continue
+			}
+			selected[name] = true
+		}
+		return selected
+	}
+
+	fieldset.fields = parse("fields", fields)
+	if fieldset.fields != nil {
+		fieldset.fields["id"] = true
+	}
+	fieldset.include = parse("include", relations)
+
+	if len(errs) > 0 {
+		return Fieldset{}, errs
+	}
+	return fieldset, nil
+}
+
+// Wants reports whether a field or relation is part of the response, so
+// callers can skip loading what is left out.
+func (f Fieldset) Wants(name string) bool {
+	return f.fields == nil || f.fields[name] || f.include[name]
+}
+
+// Includes reports whether a relation was asked for.
+func (f Fieldset) Includes(relation string) bool {
+	return f.include[relation]
+}
+
+// Select removes the fields that were not asked for from item.
+func (f Fieldset) Select(item map[string]interface{}) {
+	if f.fields == nil {
+		return
+	}
+	for name := range item {
+		if !f.Wants(name) {
+			delete(item, name)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,295 @@
+// Package listquery parses the filter and sort parameters of list endpoints,
+// such as ?filter[status]=draft&filter[created_at][gte]=2024-01-01&sort=-published_at,title,
+// and compiles them to SQL. Only fields of a Schema are accepted, columns
+// come from the Schema and never from the request, and every value is
+// passed as a query argument. It also reads the fields and include
+// parameters that shape list and single-item responses.
+package listquery
+
+import (
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type is the type of a field's values.
+type Type int
+
+const (
+	String Type = iota
+	Integer
+	Time
+	Boolean
+)
+
+// Filter operators.
+const (
+	Eq       = "eq"
+	Ne       = "ne"
+	Gt       = "gt"
+	Gte      = "gte"
+	Lt       = "lt"
+	Lte      = "lte"
+	In       = "in"
+	Contains = "contains"
+)
+
+// maxInValues limits the number of values of an in filter.
+const maxInValues = 50
+
+// Field is a field clients may filter or sort on.
+type Field struct {
+	// Column is the SQL expression of the field.
+	Column string
+	Type   Type
+	// Values lists the allowed values of an enumeration.
+	Values []string
+	// Sortable allows sorting on the field.
+	Sortable bool
+}
+
+// operators returns the operators a field accepts.
+func (f Field) operators() []string {
+	switch {
+	case f.Type == Boolean:
+		return []string{Eq, Ne}
+	case len(f.Values) > 0:
+		return []string{Eq, Ne, In}
+	case f.Type == String:
+		return []string{Eq, Ne, In, Contains}
+	case f.Type == Time:
+		return []string{Eq, Ne, Gt, Gte, Lt, Lte}
+	default:
+		return []string{Eq, Ne, Gt, Gte, Lt, Lte, In}
+	}
+}
+
+// Schema lists the fields of a list endpoint.
+type Schema struct {
+	Fields map[string]Field
+	// DefaultSort is used when the request has no sort, in the sort
+	// parameter syntax.
+	DefaultSort string
+	// Tiebreaker is the column that orders rows equal on every sort key,
+	// in the direction of the last key, so pages do not overlap.
+	Tiebreaker string
+}
+
+// Condition is one filter of a query.
+type Condition struct {
+	Field    string
+	Operator string
+	Values   []interface{}
+}
+
+// Order is one sort key of a query.
+type Order struct {
+	Field      string
+	Descending bool
+}
+
+// Query is a parsed filter and sort.
+type Query struct {
+	schema     *Schema
+	Conditions []Condition
+	Sort       []Order
+}
+
+// Parse reads the filter and sort parameters of a request. Other parameters
+// are ignored. All problems are reported together as Errors.
+func (s *Schema) Parse(values url.Values) (Query, error) {
+	query := Query{schema: s}
+	var errs Errors
+
+	// Sort the keys so errors are reported in a stable order.
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if key == "filter" || strings.HasPrefix(key, "filter[") {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if len(values[key]) != 1 {
+			errs = append(errs, Error{Parameter: key, Message: "Must be given once"})
+			continue
+		}
+		condition, err := s.parseFilter(key, values[key][0])
+		if err != nil {
+			errs = append(errs, *err)
+			continue
+		}
+		query.Conditions = append(query.Conditions, condition)
+	}
+
+	sortValue := s.DefaultSort
+	if list, ok := values["sort"]; ok {
+		if len(list) != 1 {
+			errs = append(errs, Error{Parameter: "sort", Message: "Must be given once"})
+			list = nil
+		}
+		sortValue = strings.Join(list, "")
+	}
+	orders, sortErrs := s.parseSort(sortValue)
+	query.Sort = orders
+	errs = append(errs, sortErrs...)
+
+	if len(errs) > 0 {
+		return Query{}, errs
+	}
+	return query, nil
+}
+
+// parseFilter reads a filter[field] or filter[field][operator] parameter.
+func (s *Schema) parseFilter(key, value string) (Condition, *Error) {
+	fail := func(message string) (Condition, *Error) {
+		return Condition{}, &Error{Parameter: key, Message: message}
+	}
+
+	parts, ok := brackets(strings.TrimPrefix(key, "filter"))
+	if !ok || len(parts) < 1 || len(parts) > 2 {
+		return fail("Invalid filter, must be filter[field] or filter[field][operator]")
+	}
+
+	name := parts[0]
+	field, ok := s.Fields[name]
+	if !ok {
+		return fail("Unknown field " + strconv.Quote(name) + ", must be one of " + strings.Join(s.names(false), ", "))
+	}
+
+	operator := Eq
+	if len(parts) == 2 {
+		operator = parts[1]
+	}
+	allowed := field.operators()
+	if !slices.Contains(allowed, operator) {
+		return fail("Invalid operator " + strconv.Quote(operator) + " for " + name + ", must be one of " + strings.Join(allowed, ", "))
+	}
+
+	raw := []string{value}
+	if operator == In {
+		raw = strings.Split(value, ",")
+		if len(raw) > maxInValues {
+			return fail("Too many values, at most " + strconv.Itoa(maxInValues) + " are allowed")
+		}
+	}
+
+	condition := Condition{Field: name, Operator: operator}
+	for _, v := range raw {
+		parsed, message := field.parse(strings.TrimSpace(v))
+		if message != "" {
+			return fail(message)
+		}
+		condition.Values = append(condition.Values, parsed)
+	}
+	return condition, nil
+}
+
+// parseSort reads a comma-separated list of fields, each optionally
+// prefixed with - for descending order.
+func (s *Schema) parseSort(value string) ([]Order, Errors) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var orders []Order
+	var errs Errors
+	seen := make(map[string]bool)
+	for _, key := range strings.Split(value, ",") {
+		key = strings.TrimSpace(key)
+		order := Order{Field: strings.TrimPrefix(key, "-"), Descending: strings.HasPrefix(key, "-")}
+
+		field, ok := s.Fields[order.Field]
+		switch {
+		case !ok || !field.Sortable:
+			errs = append(errs, Error{
+				Parameter: "sort",
+				Message:   "Cannot sort by " + strconv.Quote(order.Field) + ", must be one of " + strings.Join(s.names(true), ", "),
+			})
+		case seen[order.Field]:
+			errs = append(errs, Error{Parameter: "sort", Message: "Field " + order.Field + " is sorted on twice"})
+		default:
+			seen[order.Field] = true
+			orders = append(orders, order)
+		}
+	}
+	return orders, errs
+}
+
+// parse converts a filter value to the field's type. It returns a message
+// when the value is invalid.
+func (f Field) parse(value string) (interface{}, string) {
+	if len(f.Values) > 0 && !slices.Contains(f.Values, value) {
+		return nil, "Invalid value " + strconv.Quote(value) + ", must be one of " + strings.Join(f.Values, ", ")
+	}
+
+	switch f.Type {
+	case Integer:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, "Invalid value " + strconv.Quote(value) + ", must be an integer"
+		}
+		return n, ""
+	case Time:
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t, ""
+		}
+		if t, err := time.Parse("2006-01-02", value); err == nil {
+			return t, ""
+		}
+		return nil, "Invalid value " + strconv.Quote(value) + ", must be a date (2006-01-02) or an RFC 3339 time"
+	case Boolean:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, "Invalid value " + strconv.Quote(value) + ", must be true or false"
+		}
+		return b, ""
+	default:
+		if value == "" {
+			return nil, "Value must not be empty"
+		}
+		return value, ""
+	}
+}
+
+// names returns the sorted names of the filterable, or sortable, fields.
+func (s *Schema) names(sortable bool) []string {
+	names := make([]string, 0, len(s.Fields))
+	for name, field := range s.Fields {
+		if !sortable || field.Sortable {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Has reports whether the query filters on a field.
+func (q Query) Has(field string) bool {
+	for _, condition := range q.Conditions {
+		if condition.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// brackets splits "[a][b]" into a and b.
+func brackets(s string) ([]string, bool) {
+	var parts []string
+	for s != "" {
+		if s[0] != '[' {
+			return nil, false
+		}
+		end := strings.IndexByte(s, ']')
+		if end < 2 {
+			return nil, false
+		}
+		parts = append(parts, s[1:end])
+		s = s[end+1:]
+	}
+	return parts, true
+}
\ No newline at end of file
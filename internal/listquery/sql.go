@@ -0,0 +1,76 @@
+package listquery
+
+import (
+	"strconv"
+	"strings"
+)
+
+// comparisons maps operators to SQL comparison operators.
+var comparisons = map[string]string{
+	Eq:  "=",
+	Ne:  "IS DISTINCT FROM",
+	Gt:  ">",
+	Gte: ">=",
+	Lt:  "<",
+	Lte: "<=",
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// Where compiles the conditions to SQL to append to a WHERE clause, each
+// starting with AND. Placeholders are numbered after the len(args)
+// arguments the statement already has, and the returned arguments extend
+// args.
+func (q Query) Where(args []interface{}) (string, []interface{}) {
+	placeholder := func(value interface{}) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	var sql strings.Builder
+	for _, condition := range q.Conditions {
+		column := q.schema.Fields[condition.Field].Column
+
+		sql.WriteString(" AND ")
+		switch condition.Operator {
+		case In:
+			placeholders := make([]string, 0, len(condition.Values))
+			for _, value := range condition.Values {
+				placeholders = append(placeholders, placeholder(value))
+			}
+			sql.WriteString(column + " IN (" + strings.Join(placeholders, ", ") + ")")
+		case Contains:
+			pattern := "%" + likeEscaper.Replace(condition.Values[0].(string)) + "%"
+			sql.WriteString(column + " ILIKE " + placeholder(pattern))
+		default:
+			sql.WriteString(column + " " + comparisons[condition.Operator] + " " + placeholder(condition.Values[0]))
+		}
+	}
+	return sql.String(), args
+}
+
+// OrderBy compiles the sort to the list of an ORDER BY clause. Rows with
+// no value sort last in either direction.
+func (q Query) OrderBy() string {
+	keys := make([]string, 0, len(q.Sort)+1)
+	descending := false
+	for _, order := range q.Sort {
+		column := q.schema.Fields[order.Field].Column
+		descending = order.Descending
+		if descending {
+			keys = append(keys, column+" DESC NULLS LAST")
+		} else {
+			keys = append(keys, column+" ASC NULLS LAST")
+		}
+	}
+
+	if tiebreaker := q.schema.Tiebreaker; tiebreaker != "" {
+		if descending {
+			keys = append(keys, tiebreaker+" DESC")
+		} else {
+			keys = append(keys, tiebreaker+" ASC")
+		}
+	}
+	return strings.Join(keys, ", ")
+}
\ No newline at end of file
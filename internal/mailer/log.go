@@ -0,0 +1,18 @@
+package mailer
+
+import (
+	"context"
+	"log"
+)
+
+// Log writes emails to the log instead of sending them, for development.
+type Log struct{}
+
+func NewLog() *Log {
+	return &Log{}
+}
+
+func (l *Log) Send(ctx context.Context, msg Message) error {
+	log.Printf("mail to %s: %s\n%s", msg.To, msg.Subject, msg.Text)
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,39 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/demo/demo-gin/internal/config"
+)
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+}
+
+// Mailer sends the emails of the application: account emails and
+// notification digests.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New creates the mailer selected by the configuration.
+func New(cfg config.MailConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case "", "log":
+		return NewLog(), nil
+	case "smtp":
+		return NewSMTP(SMTPOptions{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.From,
+		})
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.Driver)
+	}
+}
\ No newline at end of file
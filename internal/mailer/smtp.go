@@ -0,0 +1,71 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPOptions configures the SMTP mailer. Without a username the mailer
+// does not authenticate.
+type SMTPOptions struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTP sends emails through an SMTP server.
+type SMTP struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTP(opts SMTPOptions) (*SMTP, error) {
+	if opts.Host == "" || opts.From == "" {
+		return nil, errors.New("smtp mailer requires a host and a from address")
+	}
+
+	m := &SMTP{
+		addr: net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port)),
+		from: opts.From,
+	}
+	if opts.Username != "" {
+		m.auth = smtp.PlainAuth("", opts.Username, opts.Password, opts.Host)
+	}
+	return m, nil
+}
+
+// Send delivers msg. net/smtp has no context support, so ctx is only
+// checked before connecting.
+func (m *SMTP) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, Compose(m.from, msg, time.Now()))
+}
+
+// headerSanitizer keeps header values on one line.
+var headerSanitizer = strings.NewReplacer("\r", "", "\n", " ")
+
+// Compose formats msg as an RFC 5322 message from the given address.
+func Compose(from string, msg Message, date time.Time) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + headerSanitizer.Replace(from) + "\r\n")
+	b.WriteString("To: " + headerSanitizer.Replace(msg.To) + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", headerSanitizer.Replace(msg.Subject)) + "\r\n")
+	b.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Text, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
\ No newline at end of file
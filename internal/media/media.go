@@ -0,0 +1,135 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"strconv"
+
+	// Register decoders for the accepted image formats.
+	_ "image/gif"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxPixels bounds the decoded size of an image so a small compressed file
+// cannot exhaust memory when generating thumbnails.
+const MaxPixels = 40_000_000
+
+// ErrImageTooLarge is returned when an image exceeds MaxPixels.
+var ErrImageTooLarge = errors.New("image dimensions too large")
+
+// AvatarSizes are the square sizes in pixels every avatar is resized to.
+var AvatarSizes = []int{64, 128, 256}
+
+// allowed maps accepted MIME types to the file extension used for storage.
+var allowed = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// Detect sniffs the MIME type of a file from its leading bytes, ignoring any
+// client-supplied type. It reports false for types that are not accepted.
+func Detect(header []byte) (contentType, ext string, ok bool) {
+	contentType = http.DetectContentType(header)
+	ext, ok = allowed[contentType]
+	return contentType, ext, ok
+}
+
+// IsImage reports whether an accepted MIME type is an image.
+func IsImage(contentType string) bool {
+	return contentType != "application/pdf"
+}
+
+// Thumbnail decodes an image and returns a JPEG no larger than size on
+// either side, along with the original dimensions. Transparent areas are
+// flattened onto white.
+func Thumbnail(r io.ReadSeeker, size int) (thumb []byte, width, height int, err error) {
+	src, err := decode(r)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	bounds := src.Bounds()
+	w, h := fit(bounds.Dx(), bounds.Dy(), size)
+	thumb, err = scale(src, bounds, w, h)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return thumb, bounds.Dx(), bounds.Dy(), nil
+}
+
+// Avatar decodes an image, crops the largest centred square out of it and
+// returns it as a size by size JPEG. Transparent areas are flattened onto
+// white.
+func Avatar(r io.ReadSeeker, size int) ([]byte, error) {
+	src, err := decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	x := bounds.Min.X + (bounds.Dx()-side)/2
+	y := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	return scale(src, image.Rect(x, y, x+side, y+side), size, size)
+}
+
+// AvatarKey returns the storage key of one size of the avatar stored under
+// key.
+func AvatarKey(key string, size int) string {
+	return key + "_" + strconv.Itoa(size) + ".jpg"
+}
+
+// decode reads an image after checking its dimensions against MaxPixels.
+func decode(r io.ReadSeeker) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	src, _, err := image.Decode(r)
+	return src, err
+}
+
+// scale resizes the part of src inside rect to width by height on a white
+// background and encodes it as JPEG.
+func scale(src image.Image, rect image.Rectangle, width, height int) ([]byte, error) {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, rect, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// fit scales width and height down to fit within size, keeping the aspect
+// ratio. Images that already fit are left as they are.
+func fit(width, height, size int) (int, int) {
+	if width <= size && height <= size {
+		return width, height
+	}
+	if width >= height {
+		return size, max(1, height*size/width)
+	}
+	return max(1, width*size/height), size
+}
\ No newline at end of file
@@ -36,6 +36,31 @@ func Auth() gin.HandlerFunc {
 		// TODO: Extract and set user ID from token
 		c.Set("userID", 1)
 
+		c.Next()
+	}
+}
+
+// OptionalAuth identifies the caller when an Authorization header is sent
+// but lets anonymous requests through, for public endpoints that
+// personalize their response.
+func OptionalAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.Next()
+			return
+		}
+
+		bearerToken := strings.Split(authHeader, " ")
+		if len(bearerToken) != 2 || bearerToken[0] != "Bearer" || bearerToken[1] == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+			c.Abort()
+			return
+		}
+
+		// TODO: Extract and set user ID from token
+		c.Set("userID", 1)
+
 		c.Next()
 	}
 }
\ No newline at end of file
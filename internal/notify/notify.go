@@ -0,0 +1,144 @@
+// Package notify records notifications for the users events concern,
+// following each user's notification preferences.
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/events"
+)
+
+// Notification types.
+const (
+	Comment = "comment"
+	Reply   = "reply"
+	Like    = "like"
+	Follow  = "follow"
+)
+
+// Digest frequencies.
+const (
+	DigestOff    = "off"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// Publisher is told of every notification recorded, to push it to the
+// recipient.
+type Publisher interface {
+	PublishNotification(ctx context.Context, n db.Notification) error
+}
+
+// Recorder creates notifications from events.
+type Recorder struct {
+	queries   *db.Queries
+	publisher Publisher
+}
+
+// NewRecorder creates a Recorder. Notifications are only pushed when
+// publisher is not nil.
+func NewRecorder(conn *sql.DB, publisher Publisher) *Recorder {
+	return &Recorder{queries: db.New(conn), publisher: publisher}
+}
+
+// Handle is an events.Hook. Comments notify the post owner and replies
+// also the author of the parent comment, likes notify the post owner and
+// follows the followed user. Nobody is notified of their own actions.
+func (r *Recorder) Handle(ctx context.Context, event events.Event) error {
+	switch event.Type {
+	case events.CommentCreated:
+		comment, err := r.queries.GetComment(ctx, event.CommentID)
+		if err != nil {
+			return err
+		}
+
+		if comment.ParentID.Valid {
+			parent, err := r.queries.GetComment(ctx, comment.ParentID.Int32)
+			if err != nil {
+				return err
+			}
+			if err := r.notify(ctx, parent.UserID, Reply, event); err != nil {
+				return err
+			}
+			// A post owner replied to on their own post is told once.
+			if parent.UserID == event.UserID {
+				return nil
+			}
+		}
+		return r.notify(ctx, event.UserID, Comment, event)
+	case events.PostLiked:
+		return r.notify(ctx, event.UserID, Like, event)
+	case events.UserFollowed:
+		return r.notify(ctx, event.UserID, Follow, event)
+	}
+	return nil
+}
+
+func (r *Recorder) notify(ctx context.Context, userID int32, kind string, event events.Event) error {
+	if userID == event.ActorID {
+		return nil
+	}
+
+	prefs, err := Preferences(ctx, r.queries, userID)
+	if err != nil {
+		return err
+	}
+	if !Wants(prefs, kind) {
+		return nil
+	}
+
+	n, err := r.queries.CreateNotification(ctx, db.CreateNotificationParams{
+		UserID:    userID,
+		ActorID:   event.ActorID,
+		Type:      kind,
+		PostID:    sql.NullInt32{Int32: event.PostID, Valid: event.PostID != 0},
+		CommentID: sql.NullInt32{Int32: event.CommentID, Valid: event.CommentID != 0},
+	})
+	// The user muted the actor.
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if r.publisher == nil {
+		return nil
+	}
+	return r.publisher.PublishNotification(ctx, n)
+}
+
+// Preferences returns a user's notification preferences, or the defaults
+// when they never saved any: every notification and no email digest.
+func Preferences(ctx context.Context, queries *db.Queries, userID int32) (db.NotificationPreference, error) {
+	prefs, err := queries.GetNotificationPreferences(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.NotificationPreference{
+			UserID:      userID,
+			Comments:    true,
+			Replies:     true,
+			Likes:       true,
+			Follows:     true,
+			EmailDigest: DigestOff,
+		}, nil
+	}
+	return prefs, err
+}
+
+// Wants reports whether the preferences allow notifications of a type.
+func Wants(prefs db.NotificationPreference, kind string) bool {
+	switch kind {
+	case Comment:
+		return prefs.Comments
+	case Reply:
+		return prefs.Replies
+	case Like:
+		return prefs.Likes
+	case Follow:
+		return prefs.Follows
+	}
+	return false
+}
\ No newline at end of file
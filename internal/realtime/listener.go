@@ -0,0 +1,105 @@
+package realtime
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/lib/pq"
+)
+
+const (
+	// pingInterval is how often an idle listener checks its connection.
+	pingInterval = 90 * time.Second
+	// catchUpBatchSize is the number of events loaded per query after the
+	// listener reconnects.
+	catchUpBatchSize = 500
+)
+
+// Listen relays the stream events announced on Channel to hub until ctx is
+// cancelled. Events published while the listener was reconnecting are
+// loaded from the table once it is back.
+func Listen(ctx context.Context, dsn string, conn *sql.DB, hub *Hub) error {
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("realtime: %v", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(Channel); err != nil {
+		return err
+	}
+
+	queries := db.New(conn)
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	var last int64
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			// A failed ping makes the listener reconnect.
+			_ = listener.Ping()
+		case n := <-listener.Notify:
+			// pq sends nil after reconnecting.
+			if n == nil {
+				caughtUp, err := catchUp(ctx, queries, hub, last)
+				if err != nil {
+					log.Printf("realtime: catching up: %v", err)
+				}
+				last = max(last, caughtUp)
+				continue
+			}
+
+			id, err := strconv.ParseInt(n.Extra, 10, 64)
+			if err != nil {
+				log.Printf("realtime: invalid notification %q", n.Extra)
+				continue
+			}
+			event, err := queries.GetStreamEvent(ctx, id)
+			// Pruned already.
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				log.Printf("realtime: event %d: %v", id, err)
+				continue
+			}
+			hub.Broadcast(MessageFrom(event))
+			last = max(last, id)
+		}
+	}
+}
+
+// catchUp broadcasts every event after the last one relayed and returns
+// the ID of the newest. Without a last event there is nothing to start
+// from and nothing is broadcast.
+func catchUp(ctx context.Context, queries *db.Queries, hub *Hub, last int64) (int64, error) {
+	if last == 0 {
+		return 0, nil
+	}
+
+	for {
+		missed, err := queries.ListAllStreamEventsAfter(ctx, db.ListAllStreamEventsAfterParams{
+			ID:    last,
+			Limit: catchUpBatchSize,
+		})
+		if err != nil {
+			return last, err
+		}
+		for _, event := range missed {
+			hub.Broadcast(MessageFrom(event))
+			last = event.ID
+		}
+		if len(missed) < catchUpBatchSize {
+			return last, nil
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,70 @@
+package realtime
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/events"
+)
+
+// Publisher stores stream events, which announces them to every replica.
+type Publisher struct {
+	queries *db.Queries
+}
+
+func NewPublisher(conn *sql.DB) *Publisher {
+	return &Publisher{queries: db.New(conn)}
+}
+
+// Publish stores an event for userID, or for everyone when userID is 0.
+func (p *Publisher) Publish(ctx context.Context, userID int32, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.queries.CreateStreamEvent(ctx, db.CreateStreamEventParams{
+		UserID: sql.NullInt32{Int32: userID, Valid: userID != 0},
+		Type:   eventType,
+		Data:   payload,
+	})
+	return err
+}
+
+// Handle is an events.Hook publishing post changes: to everyone when the
+// post is or was published, and to the owner otherwise.
+func (p *Publisher) Handle(ctx context.Context, event events.Event) error {
+	switch event.Type {
+	case events.PostPublished, events.PostUpdated, events.PostUnpublished, events.PostDeleted:
+		userID := event.UserID
+		if event.Public {
+			userID = 0
+		}
+		return p.Publish(ctx, userID, event.Type, event.Data)
+	}
+	return nil
+}
+
+// PublishNotification tells the recipient of a notification about it.
+func (p *Publisher) PublishNotification(ctx context.Context, n db.Notification) error {
+	data := map[string]interface{}{
+		"id":         n.ID,
+		"type":       n.Type,
+		"actor_id":   nil,
+		"post_id":    nil,
+		"comment_id": nil,
+		"created_at": n.CreatedAt.Time,
+	}
+	if n.ActorID.Valid {
+		data["actor_id"] = n.ActorID.Int32
+	}
+	if n.PostID.Valid {
+		data["post_id"] = n.PostID.Int32
+	}
+	if n.CommentID.Valid {
+		data["comment_id"] = n.CommentID.Int32
+	}
+	return p.Publish(ctx, n.UserID, NotificationCreated, data)
+}
\ No newline at end of file
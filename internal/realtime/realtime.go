@@ -0,0 +1,108 @@
+// Package realtime pushes events to connected clients. Events are stored
+// in the stream_events table, whose inserts PostgreSQL announces with
+// NOTIFY, so the Hub of every replica hears of events wherever they were
+// published, and clients can replay the ones they missed while
+// disconnected.
+package realtime
+
+import (
+	"encoding/json"
+	"sync"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+)
+
+// Channel is the PostgreSQL channel new stream events are announced on.
+const Channel = "stream_events"
+
+// NotificationCreated is the type of the events telling users of a new
+// notification.
+const NotificationCreated = "notification.created"
+
+// subscriptionBuffer is the number of messages a subscriber can fall
+// behind before it is dropped.
+const subscriptionBuffer = 64
+
+// Message is an event as it is pushed to clients.
+type Message struct {
+	ID   int64
+	Type string
+	// UserID is the only user the message is for, or 0 for everyone.
+	UserID int32
+	Data   json.RawMessage
+}
+
+// MessageFrom converts a stored stream event.
+func MessageFrom(event db.StreamEvent) Message {
+	return Message{
+		ID:     event.ID,
+		Type:   event.Type,
+		UserID: event.UserID.Int32,
+		Data:   event.Data,
+	}
+}
+
+// Hub passes messages to the subscribers of this process.
+type Hub struct {
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: make(map[*Subscription]struct{})}
+}
+
+// Subscription receives the messages for a user on C. C is closed when the
+// subscription is closed, or when the subscriber fell too far behind; it
+// is then up to the client to reconnect and replay what it missed.
+type Subscription struct {
+	C      <-chan Message
+	c      chan Message
+	userID int32
+	hub    *Hub
+}
+
+// Subscribe returns a subscription to the messages for everyone and for
+// userID.
+func (h *Hub) Subscribe(userID int32) *Subscription {
+	c := make(chan Message, subscriptionBuffer)
+	sub := &Subscription{C: c, c: c, userID: userID, hub: h}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subs[sub] = struct{}{}
+	return sub
+}
+
+// Close unsubscribes. Closing twice has no further effect.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+// Broadcast passes msg to every subscriber it is for, without waiting for
+// any of them.
+func (h *Hub) Broadcast(msg Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subs {
+		if msg.UserID != 0 && msg.UserID != sub.userID {
+			continue
+		}
+		select {
+		case sub.c <- msg:
+		default:
+			h.remove(sub)
+		}
+	}
+}
+
+// remove closes and forgets a subscription. h.mu must be held.
+func (h *Hub) remove(sub *Subscription) {
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.c)
+	}
+}
\ No newline at end of file
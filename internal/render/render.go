@@ -0,0 +1,88 @@
+package render
+
+import (
+	"bytes"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// Supported content formats.
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+)
+
+// WordsPerMinute is the reading speed used for reading time estimates.
+const WordsPerMinute = 200
+
+var (
+	markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+	// policy is the allow-list applied to all rendered HTML. Raw HTML in
+	// markdown is already dropped by goldmark; this also strips dangerous
+	// URLs and attributes that slip through links and images.
+	policy = bluemonday.UGCPolicy()
+
+	// textPolicy strips all markup when extracting plain text.
+	textPolicy = bluemonday.StrictPolicy()
+)
+
+// HTML renders content in the given format to sanitized HTML.
+func HTML(format, content string) (string, error) {
+	if format != FormatMarkdown {
+		return plainHTML(content), nil
+	}
+
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(content), &buf); err != nil {
+		return "", err
+	}
+	return policy.Sanitize(buf.String()), nil
+}
+
+// plainHTML escapes plain text and splits it into paragraphs on blank lines.
+func plainHTML(content string) string {
+	var buf strings.Builder
+	for _, paragraph := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		buf.WriteString("<p>")
+		buf.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>\n"))
+		buf.WriteString("</p>\n")
+	}
+	return buf.String()
+}
+
+// Text returns the visible text of rendered HTML with whitespace collapsed.
+func Text(renderedHTML string) string {
+	text := html.UnescapeString(textPolicy.Sanitize(renderedHTML))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// Excerpt shortens text to at most limit characters, cutting at a word
+// boundary and appending an ellipsis when anything was removed.
+func Excerpt(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+
+	cut := string([]rune(text)[:limit])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
+
+// ReadingTime estimates the minutes needed to read text, rounded up. Empty
+// text takes zero minutes.
+func ReadingTime(text string) int {
+	words := len(strings.Fields(text))
+	return (words + WordsPerMinute - 1) / WordsPerMinute
+}
\ No newline at end of file
@@ -0,0 +1,72 @@
+// Package slugs derives unique URL slugs for posts and their translations.
+package slugs
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/gosimple/slug"
+)
+
+// maxSlugLength leaves room for a numeric suffix within the slug column.
+const maxSlugLength = 200
+
+// Unique derives a URL-safe slug from a title, transliterating
+// non-ASCII text (Chinese titles become pinyin). Slugs held by other posts
+// or by translations, including old slugs that still redirect, get a
+// numeric suffix.
+func Unique(ctx context.Context, queries *db.Queries, title string, postID int32) (string, error) {
+	base := baseSlug(title)
+
+	taken, err := queries.ListTakenSlugs(ctx, db.ListTakenSlugsParams{Slug: base, PostID: postID})
+	if err != nil {
+		return "", err
+	}
+	return freeSlug(base, taken), nil
+}
+
+// UniqueTranslation is Unique for the translation of a post into a
+// locale. Translations share the slug namespace with posts, so a slug
+// always identifies one post.
+func UniqueTranslation(ctx context.Context, queries *db.Queries, title string, postID int32, locale string) (string, error) {
+	base := baseSlug(title)
+
+	taken, err := queries.ListTakenTranslationSlugs(ctx, db.ListTakenTranslationSlugsParams{
+		Slug:   base,
+		PostID: postID,
+		Locale: locale,
+	})
+	if err != nil {
+		return "", err
+	}
+	return freeSlug(base, taken), nil
+}
+
+// baseSlug returns the slug for a title before de-duplication.
+func baseSlug(title string) string {
+	base := slug.Make(title)
+	if len(base) > maxSlugLength {
+		base = strings.TrimRight(base[:maxSlugLength], "-")
+	}
+	if base == "" {
+		base = "post"
+	}
+	return base
+}
+
+// freeSlug returns base, or base with the lowest numeric suffix that is not
+// taken.
+func freeSlug(base string, taken []string) string {
+	used := make(map[string]bool, len(taken))
+	for _, s := range taken {
+		used[s] = true
+	}
+
+	candidate := base
+	for n := 2; used[candidate]; n++ {
+		candidate = base + "-" + strconv.Itoa(n)
+	}
+	return candidate
+}
\ No newline at end of file
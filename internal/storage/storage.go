@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/demo/demo-gin/internal/config"
+)
+
+// ErrNotFound is returned by Open when no object exists under the key.
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage stores uploaded files under slash-separated keys.
+type Storage interface {
+	// Put stores size bytes read from r under key, replacing any existing
+	// object.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open returns the contents stored under key.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object under key. Deleting a missing object is not
+	// an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// New creates the storage backend selected by the configuration.
+func New(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return NewLocal(cfg.LocalDir), nil
+	case "s3":
+		return NewS3(S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			UseSSL:    cfg.S3UseSSL,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,62 @@
+package transfer
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+)
+
+// Export returns the posts outside the trash as documents, those of one
+// user or, when userID is not valid, of everyone. Documents are identified
+// by post ID, so importing an export twice elsewhere creates each post
+// once.
+func Export(ctx context.Context, queries *db.Queries, userID sql.NullInt32) ([]Document, error) {
+	rows, err := queries.ListPostsForExport(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int32, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.Post.ID)
+	}
+
+	tags, err := queries.ListTagsForPosts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	tagsByPost := make(map[int32][]string)
+	for _, t := range tags {
+		tagsByPost[t.PostID] = append(tagsByPost[t.PostID], t.Tag)
+	}
+
+	documents := make([]Document, 0, len(rows))
+	for _, row := range rows {
+		p := row.Post
+		document := Document{
+			SourceID:      "post-" + strconv.Itoa(int(p.ID)),
+			Title:         p.Title,
+			Slug:          p.Slug,
+			Content:       p.Content.String,
+			ContentFormat: p.ContentFormat,
+			Status:        p.Status.String,
+			Locale:        p.Locale,
+			Tags:          tagsByPost[p.ID],
+			Author:        row.Username,
+		}
+		// Hidden posts leave as drafts; hiding is a moderation decision of
+		// this service.
+		if document.Status == "hidden" {
+			document.Status = "draft"
+		}
+		if p.PublishedAt.Valid {
+			document.Date = p.PublishedAt.Time
+		} else if p.CreatedAt.Valid {
+			document.Date = p.CreatedAt.Time
+		}
+		documents = append(documents, document)
+	}
+	return documents, nil
+}
\ No newline at end of file
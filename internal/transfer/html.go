@@ -0,0 +1,196 @@
+package transfer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	// blankLines matches runs of blank lines left between blocks.
+	blankLines = regexp.MustCompile(`\n[ \t]*\n(?:[ \t]*\n)+`)
+
+	// markdownSpecial matches characters in text that Markdown would
+	// otherwise read as markup.
+	markdownSpecial = regexp.MustCompile("[\\\\`*_\\[\\]<]")
+)
+
+// list tracks a list being converted and the number of its next item.
+type list struct {
+	ordered bool
+	next    int
+}
+
+// htmlToMarkdown converts the HTML of a blog post to Markdown, keeping
+// paragraphs, headings, emphasis, links, images, lists, quotes and code.
+// Other markup is dropped and its text kept. Blank lines separate
+// paragraphs, as in WordPress posts written without <p> tags.
+func htmlToMarkdown(source string) string {
+	var (
+		buf    strings.Builder
+		lists  []list
+		links  []string
+		quote  int
+		pre    int
+		skip   int
+		inCode bool
+	)
+
+	write := func(s string) {
+		if quote > 0 {
+			s = strings.ReplaceAll(s, "\n", "\n"+strings.Repeat("> ", quote))
+		}
+		buf.WriteString(s)
+	}
+	block := func() { write("\n\n") }
+
+	tokenizer := html.NewTokenizer(strings.NewReader(source))
+	for {
+		tt := tokenizer.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		token := tokenizer.Token()
+
+		switch tt {
+		case html.TextToken:
+			if skip > 0 {
+				continue
+			}
+			if pre > 0 || inCode {
+				write(token.Data)
+				continue
+			}
+			write(markdownSpecial.ReplaceAllString(token.Data, `\$0`))
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			switch token.DataAtom {
+			case atom.Script, atom.Style:
+				if tt == html.StartTagToken {
+					skip++
+				}
+			case atom.P, atom.Div, atom.Figure, atom.Table, atom.Tr:
+				block()
+			case atom.Br:
+				write("  \n")
+			case atom.Hr:
+				block()
+				write("---")
+				block()
+			case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+				block()
+				level, _ := strconv.Atoi(token.Data[1:])
+				write(strings.Repeat("#", level) + " ")
+			case atom.Strong, atom.B:
+				write("**")
+			case atom.Em, atom.I:
+				write("_")
+			case atom.Code:
+				if pre == 0 {
+					write("`")
+					inCode = true
+				}
+			case atom.Pre:
+				block()
+				write("```\n")
+				pre++
+			case atom.Blockquote:
+				block()
+				quote++
+				write("> ")
+			case atom.Ul, atom.Ol:
+				if len(lists) == 0 {
+					block()
+				}
+				lists = append(lists, list{ordered: token.DataAtom == atom.Ol, next: 1})
+			case atom.Li:
+				write("\n" + strings.Repeat("  ", max(len(lists)-1, 0)))
+				if n := len(lists); n > 0 && lists[n-1].ordered {
+					write(strconv.Itoa(lists[n-1].next) + ". ")
+					lists[n-1].next++
+				} else {
+					write("- ")
+				}
+			case atom.A:
+				href := attribute(token, "href")
+				links = append(links, href)
+				if href != "" {
+					write("[")
+				}
+			case atom.Img:
+				if src := attribute(token, "src"); src != "" {
+					write("![" + attribute(token, "alt") + "](" + src + ")")
+				}
+			}
+
+		case html.EndTagToken:
+			switch token.DataAtom {
+			case atom.Script, atom.Style:
+				if skip > 0 {
+					skip--
+				}
+			case atom.P, atom.Div, atom.Figure, atom.Table,
+				atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+				block()
+			case atom.Td, atom.Th:
+				write(" ")
+			case atom.Strong, atom.B:
+				write("**")
+			case atom.Em, atom.I:
+				write("_")
+			case atom.Code:
+				if pre == 0 && inCode {
+					write("`")
+					inCode = false
+				}
+			case atom.Pre:
+				if pre > 0 {
+					pre--
+					write("\n```")
+					block()
+				}
+			case atom.Blockquote:
+				if quote > 0 {
+					quote--
+					block()
+				}
+			case atom.Ul, atom.Ol:
+				if len(lists) > 0 {
+					lists = lists[:len(lists)-1]
+				}
+				if len(lists) == 0 {
+					block()
+				}
+			case atom.A:
+				if n := len(links); n > 0 {
+					if href := links[n-1]; href != "" {
+						write("](" + href + ")")
+					}
+					links = links[:n-1]
+				}
+			}
+		}
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	for i, line := range lines {
+		// Keep the two trailing spaces of hard line breaks.
+		if !strings.HasSuffix(line, "  ") || strings.TrimSpace(line) == "" {
+			lines[i] = strings.TrimRight(line, " \t")
+		}
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// attribute returns the value of an attribute of a tag, or "".
+func attribute(token html.Token, name string) string {
+	for _, attr := range token.Attr {
+		if attr.Key == name {
+			return attr.Val
+		}
+	}
+	return ""
+}
\ No newline at end of file
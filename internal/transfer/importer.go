@@ -0,0 +1,310 @@
+package transfer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/i18n"
+	"github.com/demo/demo-gin/internal/render"
+	"github.com/demo/demo-gin/internal/slugs"
+)
+
+// Import actions reported for each document.
+const (
+	ActionCreate  = "create"
+	ActionCreated = "created"
+	ActionExists  = "exists"
+	ActionError   = "error"
+)
+
+// maxTitleLength matches the title column.
+const maxTitleLength = 255
+
+// Importer creates posts from documents. Every imported document is
+// recorded by its format and source ID, so importing the same export again
+// skips the documents that were already imported.
+type Importer struct {
+	db      *sql.DB
+	queries *db.Queries
+	locales i18n.Locales
+}
+
+func NewImporter(conn *sql.DB, locales i18n.Locales) *Importer {
+	return &Importer{db: conn, queries: db.New(conn), locales: locales}
+}
+
+// Options control an import. Posts whose author has no matching user by
+// username or email go to the importing user.
+type Options struct {
+	Format     string
+	DryRun     bool
+	ImportedBy int32
+}
+
+// Result is the outcome of importing one document.
+type Result struct {
+	SourceID string   `json:"source_id"`
+	Title    string   `json:"title"`
+	Action   string   `json:"action"`
+	PostID   int32    `json:"post_id,omitempty"`
+	Slug     string   `json:"slug,omitempty"`
+	Author   string   `json:"author,omitempty"`
+	Status   string   `json:"status,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// Report summarizes an import. In a dry run nothing is written and
+// documents that would be imported are reported with the create action.
+type Report struct {
+	Format  string   `json:"format"`
+	DryRun  bool     `json:"dry_run"`
+	Created int      `json:"created"`
+	Exists  int      `json:"exists"`
+	Failed  int      `json:"failed"`
+	Results []Result `json:"results"`
+}
+
+// Import imports documents in order, each in its own transaction, so a
+// document that fails does not undo the others. It only returns an error
+// when ctx is done.
+func (i *Importer) Import(ctx context.Context, documents []Document, opts Options) (Report, error) {
+	report := Report{Format: opts.Format, DryRun: opts.DryRun, Results: make([]Result, 0, len(documents))}
+	authors := make(map[string]db.User)
+	seen := make(map[string]bool, len(documents))
+
+	for _, document := range documents {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		result := i.importDocument(ctx, document, opts, authors, seen)
+		switch result.Action {
+		case ActionCreate, ActionCreated:
+			report.Created++
+		case ActionExists:
+			report.Exists++
+		default:
+			report.Failed++
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// importDocument validates and maps one document and, unless this is a
+// dry run, creates its post.
+func (i *Importer) importDocument(ctx context.Context, document Document, opts Options, authors map[string]db.User, seen map[string]bool) Result {
+	result := Result{SourceID: document.SourceID, Title: document.Title, Status: document.Status}
+	fail := func(message string) Result {
+		result.Action = ActionError
+		result.Error = message
+		return result
+	}
+
+	if document.Error != "" {
+		return fail(document.Error)
+	}
+	if utf8.RuneCountInString(document.Title) > maxTitleLength {
+		return fail("title exceeds 255 characters")
+	}
+
+	key := opts.Format + ":" + document.SourceID
+	if seen[key] {
+		return fail("duplicate source ID in this import")
+	}
+	seen[key] = true
+
+	existing, err := i.queries.GetPostImport(ctx, key)
+	if err == nil {
+		result.Action = ActionExists
+		result.PostID = existing.PostID
+		return result
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fail("Failed to check previous imports")
+	}
+
+	author, found, err := i.author(ctx, document, authors)
+	if err != nil {
+		return fail("Failed to fetch author")
+	}
+	if !found {
+		author, err = i.importingUser(ctx, opts.ImportedBy, authors)
+		if err != nil {
+			return fail("Failed to fetch importing user")
+		}
+		if document.Author != "" || document.AuthorEmail != "" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("author %q not found, assigned to %s", document.Author, author.Username))
+		}
+	}
+	result.Author = author.Username
+
+	// Regional locales fall back to their language, anything else to the
+	// default locale.
+	locale := i.locales.Default
+	if preferences := i.locales.Preferences(document.Locale, ""); len(preferences) > 0 {
+		locale = preferences[0]
+	}
+	if requested := i18n.Normalize(document.Locale); requested != "" && requested != locale && !strings.HasPrefix(requested, locale+"-") {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("locale %q not supported, using %s", document.Locale, locale))
+	}
+
+	tags, dropped := NormalizeTags(document.Tags)
+	for _, tag := range dropped {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("tag %q exceeds 50 characters and was dropped", tag))
+	}
+	result.Tags = tags
+
+	if opts.DryRun {
+		result.Action = ActionCreate
+		return result
+	}
+
+	post, created, err := i.create(ctx, document, key, author.ID, locale, tags, opts)
+	if err != nil {
+		return fail("Failed to import post")
+	}
+	result.PostID = post.ID
+	if !created {
+		// Imported concurrently by someone else.
+		result.Action = ActionExists
+		return result
+	}
+	result.Action = ActionCreated
+	result.Slug = post.Slug
+	return result
+}
+
+// create writes the post, its tags and the import record in one
+// transaction. It reports false when the document was imported by a
+// concurrent import in the meantime.
+func (i *Importer) create(ctx context.Context, document Document, key string, authorID int32, locale string, tags []string, opts Options) (db.Post, bool, error) {
+	contentHTML, err := render.HTML(document.ContentFormat, document.Content)
+	if err != nil {
+		return db.Post{}, false, err
+	}
+
+	tx, err := i.db.BeginTx(ctx, nil)
+	if err != nil {
+		return db.Post{}, false, err
+	}
+	defer tx.Rollback()
+
+	qtx := i.queries.WithTx(tx)
+
+	base := document.Slug
+	if base == "" {
+		base = document.Title
+	}
+	postSlug, err := slugs.Unique(ctx, qtx, base, 0)
+	if err != nil {
+		return db.Post{}, false, err
+	}
+
+	created := document.Date
+	if created.IsZero() {
+		created = time.Now()
+	}
+	var published sql.NullTime
+	if document.Status != "draft" {
+		published = sql.NullTime{Time: created, Valid: true}
+	}
+
+	post, err := qtx.ImportPost(ctx, db.ImportPostParams{
+		UserID:        authorID,
+		Title:         document.Title,
+		Slug:          postSlug,
+		Content:       sql.NullString{String: document.Content, Valid: true},
+		Status:        sql.NullString{String: document.Status, Valid: true},
+		ContentFormat: document.ContentFormat,
+		ContentHtml:   sql.NullString{String: contentHTML, Valid: true},
+		Locale:        locale,
+		PublishedAt:   published,
+		CreatedAt:     created,
+	})
+	if err != nil {
+		return db.Post{}, false, err
+	}
+
+	recorded, err := qtx.CreatePostImport(ctx, db.CreatePostImportParams{
+		SourceKey:  key,
+		PostID:     post.ID,
+		Format:     opts.Format,
+		ImportedBy: sql.NullInt32{Int32: opts.ImportedBy, Valid: opts.ImportedBy != 0},
+	})
+	if err != nil {
+		return db.Post{}, false, err
+	}
+	if recorded == 0 {
+		existing, err := i.queries.GetPostImport(ctx, key)
+		return db.Post{ID: existing.PostID}, false, err
+	}
+
+	for _, tag := range tags {
+		if err := qtx.AddPostTag(ctx, db.AddPostTagParams{PostID: post.ID, Tag: tag}); err != nil {
+			return db.Post{}, false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return db.Post{}, false, err
+	}
+	return post, true, nil
+}
+
+// author finds the user a document's author maps to, by username and then
+// by email. Lookups are cached for the rest of the import.
+func (i *Importer) author(ctx context.Context, document Document, cache map[string]db.User) (db.User, bool, error) {
+	lookups := []struct {
+		key   string
+		value string
+		get   func(context.Context, string) (db.User, error)
+	}{
+		{"username:", document.Author, i.queries.GetUserByUsername},
+		{"email:", document.AuthorEmail, i.queries.GetUserByEmail},
+	}
+
+	for _, lookup := range lookups {
+		if lookup.value == "" {
+			continue
+		}
+		if user, ok := cache[lookup.key+lookup.value]; ok {
+			return user, true, nil
+		}
+
+		user, err := lookup.get(ctx, lookup.value)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return db.User{}, false, err
+		}
+		cache[lookup.key+lookup.value] = user
+		return user, true, nil
+	}
+	return db.User{}, false, nil
+}
+
+// importingUser returns the user running the import.
+func (i *Importer) importingUser(ctx context.Context, id int32, cache map[string]db.User) (db.User, error) {
+	key := "id:" + strconv.Itoa(int(id))
+	if user, ok := cache[key]; ok {
+		return user, nil
+	}
+
+	user, err := i.queries.GetUser(ctx, id)
+	if err != nil {
+		return db.User{}, err
+	}
+	cache[key] = user
+	return user, nil
+}
\ No newline at end of file
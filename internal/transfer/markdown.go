@@ -0,0 +1,274 @@
+package transfer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/demo/demo-gin/internal/render"
+	"gopkg.in/yaml.v3"
+)
+
+// maxMarkdownFileSize is the largest Markdown file read from an archive.
+const maxMarkdownFileSize = 5 << 20
+
+// frontMatter is the YAML header of a Markdown post. It reads the fields
+// common to Jekyll, Hugo and Hexo and is also what Export writes.
+type frontMatter struct {
+	ID     string      `yaml:"id,omitempty"`
+	Title  string      `yaml:"title"`
+	Slug   string      `yaml:"slug,omitempty"`
+	Date   interface{} `yaml:"date,omitempty"`
+	Status string      `yaml:"status,omitempty"`
+	Draft  bool        `yaml:"draft,omitempty"`
+	Author string      `yaml:"author,omitempty"`
+	Tags   interface{} `yaml:"tags,omitempty"`
+	Locale string      `yaml:"locale,omitempty"`
+	Lang   string      `yaml:"lang,omitempty"`
+	Format string      `yaml:"format,omitempty"`
+}
+
+// ParseMarkdownArchive reads every .md and .markdown file of a zip
+// archive. Files that cannot be parsed are returned as documents with
+// Error set; only an unreadable archive is an error.
+func ParseMarkdownArchive(data []byte) ([]Document, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("read zip archive: %w", err)
+	}
+
+	var documents []Document
+	for _, file := range archive.File {
+		name := file.Name
+		if file.FileInfo().IsDir() || !isMarkdownFile(name) {
+			continue
+		}
+
+		content, err := readZipFile(file)
+		if err != nil {
+			documents = append(documents, Document{SourceID: name, Error: err.Error()})
+			continue
+		}
+
+		document, err := ParseMarkdown(name, content)
+		if err != nil {
+			document = Document{SourceID: name, Error: err.Error()}
+		}
+		documents = append(documents, document)
+	}
+	return documents, nil
+}
+
+// ParseMarkdown reads a Markdown post with YAML front matter. The front
+// matter id identifies the post, falling back to the file name.
+func ParseMarkdown(name string, data []byte) (Document, error) {
+	header, body, err := splitFrontMatter(data)
+	if err != nil {
+		return Document{}, err
+	}
+
+	var fm frontMatter
+	if err := yaml.Unmarshal(header, &fm); err != nil {
+		return Document{}, fmt.Errorf("invalid front matter: %w", err)
+	}
+
+	document := Document{
+		SourceID:      name,
+		Title:         strings.TrimSpace(fm.Title),
+		Slug:          strings.TrimSpace(fm.Slug),
+		Content:       strings.TrimSpace(string(body)),
+		ContentFormat: render.FormatMarkdown,
+		Status:        markdownStatus(fm.Status, fm.Draft),
+		Author:        strings.TrimSpace(fm.Author),
+		Locale:        fm.Locale,
+		Tags:          stringList(fm.Tags),
+	}
+	if fm.ID != "" {
+		document.SourceID = fm.ID
+	}
+	if document.Locale == "" {
+		document.Locale = fm.Lang
+	}
+	if fm.Format == render.FormatPlain {
+		document.ContentFormat = render.FormatPlain
+	}
+	if document.Title == "" {
+		return Document{}, errors.New("front matter has no title")
+	}
+
+	switch date := fm.Date.(type) {
+	case nil:
+	case time.Time:
+		document.Date = date
+	case string:
+		parsed, ok := parseDate(date)
+		if !ok {
+			return Document{}, fmt.Errorf("invalid date %q", date)
+		}
+		document.Date = parsed
+	default:
+		return Document{}, fmt.Errorf("invalid date %v", date)
+	}
+	return document, nil
+}
+
+// MarshalMarkdown writes a document as Markdown with YAML front matter that
+// ParseMarkdown reads back.
+func MarshalMarkdown(document Document) ([]byte, error) {
+	fm := frontMatter{
+		ID:     document.SourceID,
+		Title:  document.Title,
+		Slug:   document.Slug,
+		Status: document.Status,
+		Author: document.Author,
+		Locale: document.Locale,
+		Format: document.ContentFormat,
+	}
+	if !document.Date.IsZero() {
+		fm.Date = document.Date.UTC().Format(time.RFC3339)
+	}
+	if len(document.Tags) > 0 {
+		fm.Tags = document.Tags
+	}
+
+	header, err := yaml.Marshal(fm)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(header)
+	buf.WriteString("---\n\n")
+	buf.WriteString(document.Content)
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// WriteMarkdownArchive writes documents as a zip of Markdown files named
+// after their slugs.
+func WriteMarkdownArchive(w io.Writer, documents []Document) error {
+	archive := zip.NewWriter(w)
+
+	used := make(map[string]bool, len(documents))
+	for _, document := range documents {
+		content, err := MarshalMarkdown(document)
+		if err != nil {
+			return err
+		}
+
+		base := document.Slug
+		if base == "" {
+			base = "post"
+		}
+		name := base + ".md"
+		for n := 2; used[name]; n++ {
+			name = base + "-" + strconv.Itoa(n) + ".md"
+		}
+		used[name] = true
+
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate}
+		if !document.Date.IsZero() {
+			header.Modified = document.Date
+		}
+		file, err := archive.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// splitFrontMatter separates the YAML between the leading "---" line and
+// the next "---" or "..." line from the body.
+func splitFrontMatter(data []byte) (header, body []byte, err error) {
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if strings.TrimSpace(lines[0]) != "---" {
+		return nil, nil, errors.New("missing front matter")
+	}
+
+	for i := 1; i < len(lines); i++ {
+		if line := strings.TrimSpace(lines[i]); line == "---" || line == "..." {
+			header = []byte(strings.Join(lines[1:i], "\n"))
+			body = []byte(strings.Join(lines[i+1:], "\n"))
+			return header, body, nil
+		}
+	}
+	return nil, nil, errors.New("unterminated front matter")
+}
+
+// markdownStatus maps the status conventions of static site generators
+// onto post statuses. Posts are published unless marked otherwise.
+func markdownStatus(status string, draft bool) string {
+	if draft {
+		return "draft"
+	}
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "draft", "private", "pending":
+		return "draft"
+	case "archived":
+		return "archived"
+	default:
+		return "published"
+	}
+}
+
+// stringList reads a front matter list that may also be written as a
+// single comma-separated string.
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.Split(v, ",")
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			list = append(list, fmt.Sprint(item))
+		}
+		return list
+	default:
+		return nil
+	}
+}
+
+// isMarkdownFile reports whether an archive entry is a Markdown file,
+// leaving out hidden files and macOS resource forks.
+func isMarkdownFile(name string) bool {
+	base := path.Base(name)
+	if strings.HasPrefix(base, ".") || strings.HasPrefix(name, "__MACOSX/") {
+		return false
+	}
+	ext := strings.ToLower(path.Ext(base))
+	return ext == ".md" || ext == ".markdown"
+}
+
+// readZipFile reads an archive entry up to maxMarkdownFileSize.
+func readZipFile(file *zip.File) ([]byte, error) {
+	if file.UncompressedSize64 > maxMarkdownFileSize {
+		return nil, errors.New("file exceeds the 5 MiB limit")
+	}
+
+	r, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(io.LimitReader(r, maxMarkdownFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxMarkdownFileSize {
+		return nil, errors.New("file exceeds the 5 MiB limit")
+	}
+	return data, nil
+}
\ No newline at end of file
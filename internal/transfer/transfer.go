@@ -0,0 +1,89 @@
+// Package transfer moves posts in and out of the service: it reads
+// Markdown files with YAML front matter and WordPress WXR exports, imports
+// them idempotently, and writes posts back out as Markdown.
+package transfer
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Supported import formats.
+const (
+	FormatMarkdown = "markdown"
+	FormatWXR      = "wxr"
+)
+
+// maxTagLength matches the tag column.
+const maxTagLength = 50
+
+// Document is a post on its way in or out, independent of the format it
+// was read from.
+type Document struct {
+	// SourceID identifies the document within its source, so importing
+	// the same export twice can recognize it.
+	SourceID string
+
+	Title         string
+	Slug          string
+	Content       string
+	ContentFormat string
+	Status        string
+	Locale        string
+	Tags          []string
+
+	// Author is the username of the author on the source blog and
+	// AuthorEmail their address, when the format has one.
+	Author      string
+	AuthorEmail string
+
+	// Date is when the post was published, or written for drafts. It is
+	// zero when unknown.
+	Date time.Time
+
+	// Error tells why the document could not be read. Such documents are
+	// reported, not imported.
+	Error string
+}
+
+// NormalizeTags trims tags and drops empty and duplicate ones, compared
+// case-insensitively. Tags too long to store are returned separately.
+func NormalizeTags(tags []string) (kept, dropped []string) {
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			dropped = append(dropped, tag)
+			continue
+		}
+		kept = append(kept, tag)
+	}
+	return kept, dropped
+}
+
+// parseDate reads the date formats found in front matter and WXR exports.
+// Dates without a zone are taken to be UTC.
+func parseDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{
+		time.RFC3339,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05 -0700",
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04",
+		"2006-01-02",
+		time.RFC1123Z,
+		time.RFC1123,
+	} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
\ No newline at end of file
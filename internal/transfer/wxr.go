@@ -0,0 +1,124 @@
+package transfer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/demo/demo-gin/internal/render"
+)
+
+// wxrDocument is the part of a WordPress eXtended RSS export that maps onto
+// posts. The wp namespace changes with the WXR version, so wp elements are
+// matched by local name only; content:encoded is matched by namespace to
+// tell it apart from excerpt:encoded.
+type wxrDocument struct {
+	Channel struct {
+		Language string      `xml:"language"`
+		Authors  []wxrAuthor `xml:"author"`
+		Items    []wxrItem   `xml:"item"`
+	} `xml:"channel"`
+}
+
+type wxrAuthor struct {
+	Login string `xml:"author_login"`
+	Email string `xml:"author_email"`
+}
+
+type wxrItem struct {
+	Title      string        `xml:"title"`
+	GUID       string        `xml:"guid"`
+	PubDate    string        `xml:"pubDate"`
+	Creator    string        `xml:"creator"`
+	Content    string        `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PostID     string        `xml:"post_id"`
+	PostDate   string        `xml:"post_date"`
+	PostDateGM string        `xml:"post_date_gmt"`
+	PostName   string        `xml:"post_name"`
+	Status     string        `xml:"status"`
+	PostType   string        `xml:"post_type"`
+	Categories []wxrCategory `xml:"category"`
+}
+
+type wxrCategory struct {
+	Domain string `xml:"domain,attr"`
+	Name   string `xml:",chardata"`
+}
+
+// ParseWXR reads the posts of a WordPress WXR export. Pages, attachments,
+// trashed posts and auto-drafts are left out. Post bodies are converted
+// from HTML to Markdown, and post tags become tags.
+func ParseWXR(r io.Reader) ([]Document, error) {
+	var export wxrDocument
+	decoder := xml.NewDecoder(r)
+	// WordPress writes UTF-8 but sometimes declares another charset.
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	if err := decoder.Decode(&export); err != nil {
+		return nil, fmt.Errorf("read WXR export: %w", err)
+	}
+
+	emails := make(map[string]string, len(export.Channel.Authors))
+	for _, author := range export.Channel.Authors {
+		emails[author.Login] = author.Email
+	}
+
+	var documents []Document
+	for _, item := range export.Channel.Items {
+		if item.PostType != "post" || item.Status == "trash" || item.Status == "auto-draft" {
+			continue
+		}
+
+		document := Document{
+			SourceID:      strings.TrimSpace(item.GUID),
+			Title:         strings.TrimSpace(item.Title),
+			Slug:          strings.TrimSpace(item.PostName),
+			Content:       htmlToMarkdown(item.Content),
+			ContentFormat: render.FormatMarkdown,
+			Status:        wxrStatus(item.Status),
+			Locale:        export.Channel.Language,
+			Author:        strings.TrimSpace(item.Creator),
+			AuthorEmail:   emails[strings.TrimSpace(item.Creator)],
+			Date:          wxrDate(item),
+		}
+		if document.SourceID == "" {
+			document.SourceID = "post-" + strings.TrimSpace(item.PostID)
+		}
+		for _, category := range item.Categories {
+			if category.Domain == "post_tag" {
+				document.Tags = append(document.Tags, category.Name)
+			}
+		}
+		if document.Title == "" {
+			document.Error = "post has no title"
+		}
+		documents = append(documents, document)
+	}
+	return documents, nil
+}
+
+// wxrStatus maps WordPress post statuses onto post statuses. Scheduled,
+// pending and private posts become drafts.
+func wxrStatus(status string) string {
+	if status == "publish" {
+		return "published"
+	}
+	return "draft"
+}
+
+// wxrDate returns the publication date of an item, preferring the GMT
+// date WordPress records once a post is published.
+func wxrDate(item wxrItem) time.Time {
+	for _, value := range []string{item.PostDateGM, item.PostDate, item.PubDate} {
+		if value == "" || strings.HasPrefix(value, "0000-00-00") {
+			continue
+		}
+		if t, ok := parseDate(value); ok {
+			return t
+		}
+	}
+	return time.Time{}
+}
\ No newline at end of file
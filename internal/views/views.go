@@ -0,0 +1,148 @@
+// Package views counts post views in memory and writes them to the
+// database in batches, so reading a post never waits on a write.
+package views
+
+import (
+	"context"
+	"database/sql"
+	"hash/fnv"
+	"log"
+	"sync"
+	"time"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+)
+
+// Counter buffers post views. A visitor is counted at most once per post
+// within the de-duplication window. Buffered counts are written on every
+// flush interval and once more when Run stops; views recorded after that
+// final flush are lost.
+type Counter struct {
+	queries  *db.Queries
+	window   time.Duration
+	interval time.Duration
+
+	mu      sync.Mutex
+	seen    map[visit]time.Time
+	pending map[bucket]int32
+}
+
+// visit identifies one visitor reading one post.
+type visit struct {
+	postID  int32
+	visitor uint64
+}
+
+// bucket identifies the hour a post was viewed in.
+type bucket struct {
+	postID int32
+	hour   time.Time
+}
+
+func NewCounter(conn *sql.DB, window, interval time.Duration) *Counter {
+	return &Counter{
+		queries:  db.New(conn),
+		window:   window,
+		interval: interval,
+		seen:     make(map[visit]time.Time),
+		pending:  make(map[bucket]int32),
+	}
+}
+
+// Record counts a view of a post by a visitor, identified by any string
+// that is stable for them such as their user ID or address. It reports
+// whether the view was counted, which it is not when the same visitor
+// viewed the post within the de-duplication window.
+func (c *Counter) Record(postID int32, visitor string) bool {
+	h := fnv.New64a()
+	h.Write([]byte(visitor))
+	key := visit{postID: postID, visitor: h.Sum64()}
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if last, ok := c.seen[key]; ok && now.Sub(last) < c.window {
+		return false
+	}
+	c.seen[key] = now
+	c.pending[bucket{postID: postID, hour: now.UTC().Truncate(time.Hour)}]++
+	return true
+}
+
+// Pending returns the number of views recorded but not yet written.
+func (c *Counter) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	total := 0
+	for _, views := range c.pending {
+		total += int(views)
+	}
+	return total
+}
+
+// Run flushes on every tick until ctx is cancelled, then flushes one last
+// time.
+func (c *Counter) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if _, err := c.Flush(flushCtx); err != nil {
+				log.Printf("view counter: %v", err)
+			}
+			return
+		case <-ticker.C:
+			if _, err := c.Flush(ctx); err != nil {
+				log.Printf("view counter: %v", err)
+			}
+		}
+	}
+}
+
+// Flush writes the buffered views and forgets visitors whose window has
+// passed. It returns how many views were written. Views that could not be
+// written stay buffered for the next flush.
+func (c *Counter) Flush(ctx context.Context) (int, error) {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make(map[bucket]int32)
+	now := time.Now()
+	for key, last := range c.seen {
+		if now.Sub(last) >= c.window {
+			delete(c.seen, key)
+		}
+	}
+	c.mu.Unlock()
+
+	written := 0
+	for b, views := range pending {
+		err := c.queries.AddPostViews(ctx, db.AddPostViewsParams{
+			PostID: b.postID,
+			Hour:   b.hour,
+			Views:  views,
+		})
+		if err != nil {
+			c.requeue(pending)
+			return written, err
+		}
+		written += int(views)
+		delete(pending, b)
+	}
+	return written, nil
+}
+
+// requeue adds views that could not be written back to the buffer.
+func (c *Counter) requeue(pending map[bucket]int32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for b, views := range pending {
+		c.pending[b] += views
+	}
+}
\ No newline at end of file
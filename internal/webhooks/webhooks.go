@@ -0,0 +1,160 @@
+// Package webhooks delivers events to the URLs partners subscribe. Events
+// are queued in webhook_deliveries and sent by a worker, so a slow or
+// unreachable receiver never holds up the request that caused the event.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/events"
+)
+
+// EventTypes are the event types webhooks can subscribe to.
+var EventTypes = []string{
+	events.PostPublished,
+	events.PostUpdated,
+	events.PostUnpublished,
+	events.PostDeleted,
+	events.UserUpdated,
+	events.UserDeleted,
+	events.UserRestored,
+}
+
+// Headers sent with every delivery.
+const (
+	HeaderWebhook   = "X-Webhook-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Delivery statuses.
+const (
+	StatusPending    = "pending"
+	StatusDelivering = "delivering"
+	StatusSucceeded  = "succeeded"
+	StatusFailed     = "failed"
+)
+
+const (
+	backoffBase = 30 * time.Second
+	backoffMax  = 6 * time.Hour
+)
+
+// Supported reports whether webhooks can subscribe to eventType.
+func Supported(eventType string) bool {
+	return slices.Contains(EventTypes, eventType)
+}
+
+// NewSecret returns a random signing secret.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign returns the signature header of body sent at t:
+// "t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<body>">". Signing the
+// time lets receivers reject old deliveries replayed to them.
+func Sign(secret string, t time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff returns how long to wait before retrying a delivery after its
+// attempt-th failed attempt: 30 seconds, doubling after every attempt up to
+// 6 hours.
+func Backoff(attempt int) time.Duration {
+	delay := backoffBase
+	for i := 1; i < attempt && delay < backoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, backoffMax)
+}
+
+// Payload is the body of a delivery. ID identifies the event, and is the
+// same for every delivery of it.
+type Payload struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// NewRequest builds the signed POST of a delivery to its webhook.
+func NewRequest(ctx context.Context, webhook db.Webhook, delivery db.WebhookDelivery, now time.Time) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "demo-gin-webhooks/1.0")
+	req.Header.Set(HeaderWebhook, strconv.Itoa(int(webhook.ID)))
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, now, delivery.Payload))
+	return req, nil
+}
+
+// Enqueuer queues deliveries of events to the webhooks subscribed to them.
+type Enqueuer struct {
+	queries *db.Queries
+}
+
+func NewEnqueuer(conn *sql.DB) *Enqueuer {
+	return &Enqueuer{queries: db.New(conn)}
+}
+
+// Handle is an events.Hook queuing supported events. Post events are only
+// sent for posts that are or were published, since drafts are private to
+// their authors.
+func (e *Enqueuer) Handle(ctx context.Context, event events.Event) error {
+	if !Supported(event.Type) {
+		return nil
+	}
+	switch event.Type {
+	case events.PostPublished, events.PostUpdated, events.PostUnpublished, events.PostDeleted:
+		if !event.Public {
+			return nil
+		}
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	id := "evt_" + hex.EncodeToString(b)
+	payload, err := json.Marshal(Payload{
+		ID:        id,
+		Type:      event.Type,
+		CreatedAt: event.At,
+		Data:      event.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = e.queries.EnqueueWebhookDeliveries(ctx, db.EnqueueWebhookDeliveriesParams{
+		EventID:   id,
+		EventType: event.Type,
+		Payload:   payload,
+	})
+	return err
+}
\ No newline at end of file
@@ -0,0 +1,82 @@
+// Package worker contains background jobs that run alongside the API.
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/storage"
+)
+
+// cleanupBatchSize is the number of orphaned attachments removed per query.
+const cleanupBatchSize = 100
+
+// AttachmentCleanup periodically deletes attachments whose post has been
+// deleted, removing the stored files before the database rows.
+type AttachmentCleanup struct {
+	queries  *db.Queries
+	storage  storage.Storage
+	interval time.Duration
+}
+
+func NewAttachmentCleanup(conn *sql.DB, store storage.Storage, interval time.Duration) *AttachmentCleanup {
+	return &AttachmentCleanup{queries: db.New(conn), storage: store, interval: interval}
+}
+
+// Run cleans up on every tick until ctx is cancelled.
+func (w *AttachmentCleanup) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := w.RunOnce(ctx)
+			if err != nil {
+				log.Printf("attachment cleanup: %v", err)
+			}
+			if removed > 0 {
+				log.Printf("attachment cleanup: removed %d orphaned attachments", removed)
+			}
+		}
+	}
+}
+
+// RunOnce removes all currently orphaned attachments and returns how many
+// were deleted. A file that cannot be deleted keeps its row so the next run
+// retries it.
+func (w *AttachmentCleanup) RunOnce(ctx context.Context) (int, error) {
+	removed := 0
+	for {
+		orphans, err := w.queries.ListOrphanedAttachments(ctx, cleanupBatchSize)
+		if err != nil {
+			return removed, err
+		}
+
+		deleted := 0
+		for _, a := range orphans {
+			if a.ThumbnailKey.Valid {
+				if err := w.storage.Delete(ctx, a.ThumbnailKey.String); err != nil {
+					return removed, err
+				}
+			}
+			if err := w.storage.Delete(ctx, a.StorageKey); err != nil {
+				return removed, err
+			}
+			if err := w.queries.DeleteAttachment(ctx, a.ID); err != nil {
+				return removed, err
+			}
+			deleted++
+		}
+
+		removed += deleted
+		if len(orphans) < cleanupBatchSize {
+			return removed, nil
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,155 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/mailer"
+	"github.com/demo/demo-gin/internal/notify"
+)
+
+const (
+	// digestBatchSize is the number of due digests fetched per query.
+	digestBatchSize = 100
+	// digestMaxItems is the number of notifications listed in one digest.
+	digestMaxItems = 50
+)
+
+// Digests emails users who chose a daily or weekly digest the
+// notifications they have neither read nor been emailed about.
+type Digests struct {
+	queries  *db.Queries
+	mailer   mailer.Mailer
+	interval time.Duration
+}
+
+func NewDigests(conn *sql.DB, m mailer.Mailer, interval time.Duration) *Digests {
+	return &Digests{queries: db.New(conn), mailer: m, interval: interval}
+}
+
+// Run sends due digests on every tick until ctx is cancelled.
+func (w *Digests) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sent, err := w.RunOnce(ctx)
+			if err != nil {
+				log.Printf("digests: %v", err)
+			}
+			if sent > 0 {
+				log.Printf("digests: sent %d emails", sent)
+			}
+		}
+	}
+}
+
+// RunOnce sends every due digest and returns how many emails were sent. A
+// digest with nothing to report is skipped until the next period without
+// sending anything. A digest that cannot be sent stops the run so the next
+// one retries it.
+func (w *Digests) RunOnce(ctx context.Context) (sent int, err error) {
+	now := time.Now()
+
+	for {
+		due, err := w.queries.ListDueDigests(ctx, db.ListDueDigestsParams{
+			DailyBefore:  sql.NullTime{Time: now.Add(-24 * time.Hour), Valid: true},
+			WeeklyBefore: sql.NullTime{Time: now.Add(-7 * 24 * time.Hour), Valid: true},
+			Limit:        digestBatchSize,
+		})
+		if err != nil {
+			return sent, err
+		}
+
+		for _, digest := range due {
+			emailed, err := w.send(ctx, digest, now)
+			if err != nil {
+				return sent, fmt.Errorf("user %d: %w", digest.UserID, err)
+			}
+			if emailed {
+				sent++
+			}
+		}
+
+		if len(due) < digestBatchSize {
+			return sent, nil
+		}
+	}
+}
+
+func (w *Digests) send(ctx context.Context, digest db.ListDueDigestsRow, now time.Time) (bool, error) {
+	items, err := w.queries.ListUndigestedNotifications(ctx, db.ListUndigestedNotificationsParams{
+		UserID: digest.UserID,
+		Limit:  digestMaxItems,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if len(items) > 0 {
+		if err := w.mailer.Send(ctx, digestMessage(digest, items)); err != nil {
+			return false, err
+		}
+
+		ids := make([]int32, 0, len(items))
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+		if err := w.queries.MarkNotificationsEmailed(ctx, ids); err != nil {
+			return false, err
+		}
+	}
+
+	err = w.queries.RecordDigestSent(ctx, db.RecordDigestSentParams{
+		UserID:       digest.UserID,
+		LastDigestAt: sql.NullTime{Time: now, Valid: true},
+	})
+	return len(items) > 0, err
+}
+
+// digestMessage lists the notifications of a digest, one per line.
+func digestMessage(digest db.ListDueDigestsRow, items []db.ListUndigestedNotificationsRow) mailer.Message {
+	var text strings.Builder
+	text.WriteString("Hi " + digest.Username + ",\n\n")
+	if len(items) == 1 {
+		text.WriteString("You have 1 new notification:\n\n")
+	} else {
+		text.WriteString("You have " + strconv.Itoa(len(items)) + " new notifications:\n\n")
+	}
+
+	for _, item := range items {
+		actor := item.ActorUsername
+		if actor == "" {
+			actor = "Someone"
+		}
+		title := `"` + item.PostTitle + `"`
+
+		switch item.Type {
+		case notify.Comment:
+			text.WriteString("- " + actor + " commented on " + title + "\n")
+		case notify.Reply:
+			text.WriteString("- " + actor + " replied to your comment on " + title + "\n")
+		case notify.Like:
+			text.WriteString("- " + actor + " liked " + title + "\n")
+		case notify.Follow:
+			text.WriteString("- " + actor + " started following you\n")
+		}
+	}
+	text.WriteString("\nYou can change how often you get this email in your notification preferences.\n")
+
+	return mailer.Message{
+		To:      digest.Email,
+		Subject: "Your " + digest.EmailDigest + " notification digest",
+		Text:    text.String(),
+	}
+}
\ No newline at end of file
@@ -0,0 +1,114 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/media"
+	"github.com/demo/demo-gin/internal/storage"
+)
+
+// erasureBatchSize is the number of due erasures fetched per query.
+const erasureBatchSize = 100
+
+// AccountErasure erases accounts whose erasure grace period has passed. In
+// anonymize mode the user row is kept with every personal field replaced,
+// so their posts stay up under an anonymous author while their comments
+// become tombstones. Otherwise the user is deleted together with
+// everything they own.
+type AccountErasure struct {
+	db        *sql.DB
+	queries   *db.Queries
+	storage   storage.Storage
+	anonymize bool
+	interval  time.Duration
+}
+
+func NewAccountErasure(conn *sql.DB, store storage.Storage, anonymize bool, interval time.Duration) *AccountErasure {
+	return &AccountErasure{db: conn, queries: db.New(conn), storage: store, anonymize: anonymize, interval: interval}
+}
+
+// Run erases due accounts on every tick until ctx is cancelled.
+func (w *AccountErasure) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			erased, err := w.RunOnce(ctx)
+			if err != nil {
+				log.Printf("account erasure: %v", err)
+			}
+			if erased > 0 {
+				log.Printf("account erasure: erased %d accounts", erased)
+			}
+		}
+	}
+}
+
+// RunOnce erases every account whose grace period has passed and returns
+// how many were erased. A user whose avatar files cannot be deleted keeps
+// their schedule so the next run retries it.
+func (w *AccountErasure) RunOnce(ctx context.Context) (int, error) {
+	erased := 0
+
+	for {
+		due, err := w.queries.ListDueErasures(ctx, erasureBatchSize)
+		if err != nil {
+			return erased, err
+		}
+
+		for _, u := range due {
+			if u.AvatarKey.Valid {
+				for _, size := range media.AvatarSizes {
+					if err := w.storage.Delete(ctx, media.AvatarKey(u.AvatarKey.String, size)); err != nil {
+						return erased, err
+					}
+				}
+			}
+			if err := w.erase(ctx, u.ID); err != nil {
+				return erased, err
+			}
+			erased++
+		}
+
+		if len(due) < erasureBatchSize {
+			return erased, nil
+		}
+	}
+}
+
+// erase removes or anonymizes a single user in one transaction.
+func (w *AccountErasure) erase(ctx context.Context, userID int32) error {
+	tx, err := w.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := w.queries.WithTx(tx)
+
+	if !w.anonymize {
+		if err := qtx.DeleteUser(ctx, userID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	if err := qtx.DeleteUserRelationships(ctx, userID); err != nil {
+		return err
+	}
+	if err := qtx.EraseUserComments(ctx, userID); err != nil {
+		return err
+	}
+	if err := qtx.AnonymizeUser(ctx, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
\ No newline at end of file
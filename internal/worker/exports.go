@@ -0,0 +1,332 @@
+package worker
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/storage"
+)
+
+// exportBuildTimeout bounds the time spent building one archive.
+const exportBuildTimeout = 30 * time.Minute
+
+// DataExports builds the personal data archives users request and removes
+// them once their download link has expired or their user has been erased.
+type DataExports struct {
+	queries  *db.Queries
+	storage  storage.Storage
+	ttl      time.Duration
+	interval time.Duration
+}
+
+func NewDataExports(conn *sql.DB, store storage.Storage, ttl, interval time.Duration) *DataExports {
+	return &DataExports{queries: db.New(conn), storage: store, ttl: ttl, interval: interval}
+}
+
+// Run processes exports on every tick until ctx is cancelled.
+func (w *DataExports) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			built, expired, err := w.RunOnce(ctx)
+			if err != nil {
+				log.Printf("data exports: %v", err)
+			}
+			if built > 0 || expired > 0 {
+				log.Printf("data exports: built %d archives, removed %d expired", built, expired)
+			}
+		}
+	}
+}
+
+// RunOnce builds every pending export and removes every expired archive,
+// returning how many of each it handled. An export that cannot be built is
+// marked failed so the user can request a new one. Exports left processing
+// for longer than a build may take belong to a worker that stopped, and are
+// built again.
+func (w *DataExports) RunOnce(ctx context.Context) (built, expired int, err error) {
+	staleBefore := time.Now().Add(-exportBuildTimeout - time.Minute)
+	for {
+		export, err := w.queries.ClaimPendingDataExport(ctx, sql.NullTime{Time: staleBefore, Valid: true})
+		if errors.Is(err, sql.ErrNoRows) {
+			break
+		}
+		if err != nil {
+			return built, expired, err
+		}
+
+		buildCtx, cancel := context.WithTimeout(ctx, exportBuildTimeout)
+		err = w.build(buildCtx, export)
+		cancel()
+		if err != nil {
+			log.Printf("data exports: export %d: %v", export.ID, err)
+			if err := w.queries.FailDataExport(ctx, db.FailDataExportParams{
+				ID:    export.ID,
+				Error: sql.NullString{String: err.Error(), Valid: true},
+			}); err != nil {
+				return built, expired, err
+			}
+			continue
+		}
+		built++
+	}
+
+	for {
+		stale, err := w.queries.ListExpiredDataExports(ctx, cleanupBatchSize)
+		if err != nil {
+			return built, expired, err
+		}
+
+		for _, e := range stale {
+			if err := w.storage.Delete(ctx, e.StorageKey.String); err != nil {
+				return built, expired, err
+			}
+			if err := w.queries.ExpireDataExport(ctx, e.ID); err != nil {
+				return built, expired, err
+			}
+			expired++
+		}
+
+		if len(stale) < cleanupBatchSize {
+			break
+		}
+	}
+
+	return built, expired, w.queries.DeleteOrphanedDataExports(ctx)
+}
+
+// build writes the archive of one export to a temporary file, stores it and
+// marks the export ready with a fresh download token.
+func (w *DataExports) build(ctx context.Context, export db.DataExport) error {
+	if !export.UserID.Valid {
+		return errors.New("user has been erased")
+	}
+
+	user, err := w.queries.GetUser(ctx, export.UserID.Int32)
+	if err != nil {
+		return fmt.Errorf("fetch user: %w", err)
+	}
+
+	file, err := os.CreateTemp("", "export-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if err := w.writeArchive(ctx, file, user); err != nil {
+		return err
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	key := "exports/" + randomHex(16) + ".zip"
+	if err := w.storage.Put(ctx, key, file, size, "application/zip"); err != nil {
+		return fmt.Errorf("store archive: %w", err)
+	}
+
+	if _, err := w.queries.CompleteDataExport(ctx, db.CompleteDataExportParams{
+		StorageKey: sql.NullString{String: key, Valid: true},
+		SizeBytes:  sql.NullInt64{Int64: size, Valid: true},
+		Token:      sql.NullString{String: randomHex(32), Valid: true},
+		ExpiresAt:  sql.NullTime{Time: time.Now().Add(w.ttl), Valid: true},
+		ID:         export.ID,
+	}); err != nil {
+		_ = w.storage.Delete(ctx, key)
+		return err
+	}
+	return nil
+}
+
+// writeArchive loads everything a user's archive contains and writes it.
+func (w *DataExports) writeArchive(ctx context.Context, out io.Writer, user db.User) error {
+	posts, err := w.queries.ListAllUserPosts(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("fetch posts: %w", err)
+	}
+	comments, err := w.queries.ListUserComments(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("fetch comments: %w", err)
+	}
+	attachments, err := w.queries.ListUserAttachments(ctx, sql.NullInt32{Int32: user.ID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("fetch attachments: %w", err)
+	}
+
+	return WriteArchive(ctx, out, w.storage, Archive{
+		User:        user,
+		Posts:       posts,
+		Comments:    comments,
+		Attachments: attachments,
+	})
+}
+
+// Archive is the personal data of one user.
+type Archive struct {
+	User        db.User
+	Posts       []db.Post
+	Comments    []db.Comment
+	Attachments []db.Attachment
+}
+
+// WriteArchive writes the profile, posts, comments, sessions and attachment
+// metadata of an archive as JSON files to a zip file, followed by the
+// attachment files themselves, which are read from store.
+func WriteArchive(ctx context.Context, out io.Writer, store storage.Storage, data Archive) error {
+	archive := zip.NewWriter(out)
+
+	profile := map[string]interface{}{
+		"id":         data.User.ID,
+		"email":      data.User.Email,
+		"username":   data.User.Username,
+		"full_name":  nullValue(data.User.FullName),
+		"bio":        nullValue(data.User.Bio),
+		"website":    nullValue(data.User.Website),
+		"location":   nullValue(data.User.Location),
+		"role":       data.User.Role,
+		"is_active":  nullValue(data.User.IsActive),
+		"created_at": nullValue(data.User.CreatedAt),
+		"updated_at": nullValue(data.User.UpdatedAt),
+	}
+	if err := writeJSON(archive, "profile.json", profile); err != nil {
+		return err
+	}
+
+	postDocuments := make([]map[string]interface{}, 0, len(data.Posts))
+	for _, p := range data.Posts {
+		postDocuments = append(postDocuments, map[string]interface{}{
+			"id":             p.ID,
+			"title":          p.Title,
+			"slug":           p.Slug,
+			"content":        nullValue(p.Content),
+			"content_format": p.ContentFormat,
+			"status":         nullValue(p.Status),
+			"published_at":   nullValue(p.PublishedAt),
+			"created_at":     nullValue(p.CreatedAt),
+			"updated_at":     nullValue(p.UpdatedAt),
+			"deleted_at":     nullValue(p.DeletedAt),
+		})
+	}
+	if err := writeJSON(archive, "posts.json", postDocuments); err != nil {
+		return err
+	}
+
+	commentDocuments := make([]map[string]interface{}, 0, len(data.Comments))
+	for _, c := range data.Comments {
+		commentDocuments = append(commentDocuments, map[string]interface{}{
+			"id":         c.ID,
+			"post_id":    c.PostID,
+			"parent_id":  nullValue(c.ParentID),
+			"content":    c.Content,
+			"created_at": nullValue(c.CreatedAt),
+			"updated_at": nullValue(c.UpdatedAt),
+			"deleted_at": nullValue(c.DeletedAt),
+		})
+	}
+	if err := writeJSON(archive, "comments.json", commentDocuments); err != nil {
+		return err
+	}
+
+	attachmentDocuments := make([]map[string]interface{}, 0, len(data.Attachments))
+	for _, a := range data.Attachments {
+		attachmentDocuments = append(attachmentDocuments, map[string]interface{}{
+			"id":           a.ID,
+			"post_id":      nullValue(a.PostID),
+			"filename":     a.Filename,
+			"content_type": a.ContentType,
+			"size_bytes":   a.SizeBytes,
+			"created_at":   nullValue(a.CreatedAt),
+			"file":         attachmentPath(a),
+		})
+	}
+	if err := writeJSON(archive, "attachments.json", attachmentDocuments); err != nil {
+		return err
+	}
+
+	// Authentication does not issue real sessions yet, so there are none to
+	// export. The file is written anyway so the archive layout stays the
+	// same once logins are recorded.
+	if err := writeJSON(archive, "sessions.json", []map[string]interface{}{}); err != nil {
+		return err
+	}
+
+	for _, a := range data.Attachments {
+		if err := copyFile(ctx, store, archive, attachmentPath(a), a.StorageKey); err != nil {
+			return fmt.Errorf("attachment %d: %w", a.ID, err)
+		}
+	}
+
+	return archive.Close()
+}
+
+// copyFile adds a stored file to the archive under name.
+func copyFile(ctx context.Context, store storage.Storage, archive *zip.Writer, name, key string) error {
+	reader, err := store.Open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	dst, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, reader)
+	return err
+}
+
+// writeJSON adds an indented JSON document to the archive.
+func writeJSON(archive *zip.Writer, name string, v interface{}) error {
+	dst, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(dst)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// attachmentPath returns the path of an attachment inside the archive. The
+// ID keeps paths unique; path.Base strips anything that could escape the
+// attachments directory.
+func attachmentPath(a db.Attachment) string {
+	return "attachments/" + strconv.Itoa(int(a.ID)) + "_" + path.Base(a.Filename)
+}
+
+// nullValue converts a nullable column into a JSON-friendly value.
+func nullValue(v driver.Valuer) interface{} {
+	value, _ := v.Value()
+	return value
+}
+
+// randomHex returns n random bytes encoded as hex.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
\ No newline at end of file
@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+)
+
+// StreamEventPrune removes stream events once they are too old to be
+// replayed.
+type StreamEventPrune struct {
+	queries   *db.Queries
+	retention time.Duration
+	interval  time.Duration
+}
+
+func NewStreamEventPrune(conn *sql.DB, retention, interval time.Duration) *StreamEventPrune {
+	return &StreamEventPrune{queries: db.New(conn), retention: retention, interval: interval}
+}
+
+// Run prunes on every tick until ctx is cancelled.
+func (w *StreamEventPrune) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pruned, err := w.RunOnce(ctx)
+			if err != nil {
+				log.Printf("stream event prune: %v", err)
+			}
+			if pruned > 0 {
+				log.Printf("stream event prune: removed %d events", pruned)
+			}
+		}
+	}
+}
+
+// RunOnce removes every event older than the retention period and returns
+// how many were removed.
+func (w *StreamEventPrune) RunOnce(ctx context.Context) (int64, error) {
+	return w.queries.PruneStreamEvents(ctx, sql.NullTime{Time: time.Now().Add(-w.retention), Valid: true})
+}
\ No newline at end of file
@@ -0,0 +1,102 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/media"
+	"github.com/demo/demo-gin/internal/storage"
+)
+
+// purgeBatchSize is the number of trashed rows removed per query.
+const purgeBatchSize = 100
+
+// TrashPurge periodically removes posts and users that have been in the
+// trash for longer than the retention period. Deleting a user cascades to
+// everything they own; attachments of purged posts are left for
+// AttachmentCleanup.
+type TrashPurge struct {
+	queries   *db.Queries
+	storage   storage.Storage
+	retention time.Duration
+	interval  time.Duration
+}
+
+func NewTrashPurge(conn *sql.DB, store storage.Storage, retention, interval time.Duration) *TrashPurge {
+	return &TrashPurge{queries: db.New(conn), storage: store, retention: retention, interval: interval}
+}
+
+// Run purges on every tick until ctx is cancelled.
+func (w *TrashPurge) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			posts, users, err := w.RunOnce(ctx)
+			if err != nil {
+				log.Printf("trash purge: %v", err)
+			}
+			if posts > 0 || users > 0 {
+				log.Printf("trash purge: removed %d posts and %d users", posts, users)
+			}
+		}
+	}
+}
+
+// RunOnce removes everything that has outlived the retention period and
+// returns how many posts and users were deleted. A user whose avatar files
+// cannot be deleted stays in the trash so the next run retries it.
+func (w *TrashPurge) RunOnce(ctx context.Context) (posts, users int, err error) {
+	before := sql.NullTime{Time: time.Now().Add(-w.retention), Valid: true}
+
+	for {
+		deleted, err := w.queries.PurgeTrashedPosts(ctx, db.PurgeTrashedPostsParams{
+			Before: before,
+			Limit:  purgeBatchSize,
+		})
+		if err != nil {
+			return posts, users, err
+		}
+
+		posts += int(deleted)
+		if deleted < purgeBatchSize {
+			break
+		}
+	}
+
+	for {
+		expired, err := w.queries.ListPurgeableUsers(ctx, db.ListPurgeableUsersParams{
+			Before: before,
+			Limit:  purgeBatchSize,
+		})
+		if err != nil {
+			return posts, users, err
+		}
+
+		for _, u := range expired {
+			if u.AvatarKey.Valid {
+				for _, size := range media.AvatarSizes {
+					if err := w.storage.Delete(ctx, media.AvatarKey(u.AvatarKey.String, size)); err != nil {
+						return posts, users, err
+					}
+				}
+			}
+			deleted, err := w.queries.PurgeUser(ctx, u.ID)
+			if err != nil {
+				return posts, users, err
+			}
+			users += int(deleted)
+		}
+
+		if len(expired) < purgeBatchSize {
+			return posts, users, nil
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+)
+
+// viewRetention is how long hourly view counts are kept. Post totals are
+// kept forever; this only limits the history authors can chart.
+const viewRetention = 90 * 24 * time.Hour
+
+// TrendingScores periodically recomputes the time-decayed trending score of
+// every post viewed within the window and prunes old view counts.
+type TrendingScores struct {
+	queries  *db.Queries
+	window   time.Duration
+	halfLife time.Duration
+	interval time.Duration
+}
+
+func NewTrendingScores(conn *sql.DB, window, halfLife, interval time.Duration) *TrendingScores {
+	return &TrendingScores{queries: db.New(conn), window: window, halfLife: halfLife, interval: interval}
+}
+
+// Run recomputes scores on every tick until ctx is cancelled.
+func (w *TrendingScores) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, _, err := w.RunOnce(ctx); err != nil {
+				log.Printf("trending scores: %v", err)
+			}
+		}
+	}
+}
+
+// RunOnce recomputes the scores and returns how many posts were scored and
+// how many hourly view counts were pruned.
+func (w *TrendingScores) RunOnce(ctx context.Context) (scored, pruned int64, err error) {
+	now := time.Now()
+
+	scored, err = w.queries.RefreshTrendingScores(ctx, db.RefreshTrendingScoresParams{
+		HalfLifeSeconds: w.halfLife.Seconds(),
+		Since:           now.Add(-w.window),
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	pruned, err = w.queries.DeleteOldPostViews(ctx, now.Add(-viewRetention))
+	return scored, pruned, err
+}
\ No newline at end of file
@@ -0,0 +1,160 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/webhooks"
+)
+
+// webhookResponseLimit is how much of a response body the delivery log
+// keeps.
+const webhookResponseLimit = 4 << 10
+
+// WebhookDeliveries sends queued webhook deliveries. A failed attempt is
+// retried with exponential backoff until maxAttempts, and a webhook is
+// disabled once disableAfter attempts in a row have failed.
+type WebhookDeliveries struct {
+	queries      *db.Queries
+	client       *http.Client
+	maxAttempts  int
+	disableAfter int
+	interval     time.Duration
+}
+
+func NewWebhookDeliveries(conn *sql.DB, timeout time.Duration, maxAttempts, disableAfter int, interval time.Duration) *WebhookDeliveries {
+	return &WebhookDeliveries{
+		queries:      db.New(conn),
+		client:       &http.Client{Timeout: timeout},
+		maxAttempts:  maxAttempts,
+		disableAfter: disableAfter,
+		interval:     interval,
+	}
+}
+
+// Run sends deliveries on every tick until ctx is cancelled.
+func (w *WebhookDeliveries) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			succeeded, failed, err := w.RunOnce(ctx)
+			if err != nil {
+				log.Printf("webhook deliveries: %v", err)
+			}
+			if succeeded > 0 || failed > 0 {
+				log.Printf("webhook deliveries: %d succeeded, %d failed", succeeded, failed)
+			}
+		}
+	}
+}
+
+// RunOnce sends every due delivery and returns how many attempts succeeded
+// and failed. Deliveries left delivering for longer than the client timeout
+// allows belong to a worker that stopped, and are sent again.
+func (w *WebhookDeliveries) RunOnce(ctx context.Context) (succeeded, failed int, err error) {
+	staleBefore := time.Now().Add(-2*w.client.Timeout - time.Minute)
+	for {
+		delivery, err := w.queries.ClaimWebhookDelivery(ctx, sql.NullTime{Time: staleBefore, Valid: true})
+		if errors.Is(err, sql.ErrNoRows) {
+			return succeeded, failed, nil
+		}
+		if err != nil {
+			return succeeded, failed, err
+		}
+
+		ok, err := w.deliver(ctx, delivery)
+		if err != nil {
+			return succeeded, failed, err
+		}
+		if ok {
+			succeeded++
+		} else {
+			failed++
+		}
+	}
+}
+
+// deliver makes one attempt at a delivery and records its outcome. It
+// reports whether the receiver accepted the delivery; the error is only
+// set when the outcome could not be recorded.
+func (w *WebhookDeliveries) deliver(ctx context.Context, delivery db.WebhookDelivery) (bool, error) {
+	webhook, err := w.queries.GetWebhook(ctx, delivery.WebhookID)
+	if err != nil {
+		return false, err
+	}
+
+	result := db.CompleteWebhookDeliveryParams{
+		ID:            delivery.ID,
+		Status:        webhooks.StatusSucceeded,
+		NextAttemptAt: delivery.NextAttemptAt,
+	}
+	if err := w.send(ctx, webhook, delivery, &result); err != nil {
+		result.Error = sql.NullString{String: err.Error(), Valid: true}
+		result.Status = webhooks.StatusFailed
+		if int(delivery.Attempts) < w.maxAttempts {
+			result.Status = webhooks.StatusPending
+			result.NextAttemptAt = time.Now().Add(webhooks.Backoff(int(delivery.Attempts)))
+		}
+	}
+
+	if err := w.queries.CompleteWebhookDelivery(ctx, result); err != nil {
+		return false, err
+	}
+
+	if result.Status == webhooks.StatusSucceeded {
+		return true, w.queries.RecordWebhookSuccess(ctx, webhook.ID)
+	}
+	webhook, err = w.queries.RecordWebhookFailure(ctx, db.RecordWebhookFailureParams{
+		ID:           webhook.ID,
+		DisableAfter: int32(w.disableAfter),
+	})
+	if err != nil {
+		return false, err
+	}
+	if !webhook.Active && webhook.FailureCount == int32(w.disableAfter) {
+		log.Printf("webhook deliveries: disabled webhook %d after %d failed attempts", webhook.ID, webhook.FailureCount)
+	}
+	return false, nil
+}
+
+// send posts a delivery, storing the response in result. Responses outside
+// 2xx are errors.
+func (w *WebhookDeliveries) send(ctx context.Context, webhook db.Webhook, delivery db.WebhookDelivery, result *db.CompleteWebhookDeliveryParams) error {
+	req, err := webhooks.NewRequest(ctx, webhook, delivery, time.Now())
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	if err != nil {
+		return err
+	}
+	result.ResponseStatus = sql.NullInt32{Int32: int32(resp.StatusCode), Valid: true}
+	// Postgres text cannot hold invalid UTF-8 or NUL bytes.
+	text := strings.ReplaceAll(strings.ToValidUTF8(string(body), "\uFFFD"), "\x00", "")
+	result.ResponseBody = sql.NullString{String: text, Valid: true}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS update_comments_updated_at ON comments;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_comments_user_id;
+DROP INDEX IF EXISTS idx_comments_parent_id;
+DROP INDEX IF EXISTS idx_comments_post_id;
+
+-- Drop columns
+ALTER TABLE posts DROP COLUMN IF EXISTS comments_closed;
+
+-- Drop tables
+DROP TABLE IF EXISTS comments;
\ No newline at end of file
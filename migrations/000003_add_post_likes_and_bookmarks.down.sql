@@ -0,0 +1,26 @@
+-- Restore the generic updated_at trigger on posts
+DROP TRIGGER IF EXISTS update_posts_updated_at ON posts;
+CREATE TRIGGER update_posts_updated_at BEFORE UPDATE ON posts
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+DROP FUNCTION IF EXISTS update_posts_updated_at_column();
+
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_post_bookmarks_count ON post_bookmarks;
+DROP TRIGGER IF EXISTS update_post_likes_count ON post_likes;
+
+-- Drop trigger functions
+DROP FUNCTION IF EXISTS update_post_bookmark_count();
+DROP FUNCTION IF EXISTS update_post_like_count();
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_post_bookmarks_user_id;
+DROP INDEX IF EXISTS idx_post_likes_user_id;
+
+-- Drop columns
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS bookmark_count,
+    DROP COLUMN IF EXISTS like_count;
+
+-- Drop tables
+DROP TABLE IF EXISTS post_bookmarks;
+DROP TABLE IF EXISTS post_likes;
\ No newline at end of file
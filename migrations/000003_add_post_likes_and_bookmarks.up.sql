@@ -0,0 +1,73 @@
+-- Create post_likes table
+CREATE TABLE IF NOT EXISTS post_likes (
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, user_id)
+);
+
+-- Create post_bookmarks table
+CREATE TABLE IF NOT EXISTS post_bookmarks (
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, user_id)
+);
+
+-- Add denormalized engagement counters to posts
+ALTER TABLE posts
+    ADD COLUMN like_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN bookmark_count INTEGER NOT NULL DEFAULT 0;
+
+-- Create indexes
+CREATE INDEX idx_post_likes_user_id ON post_likes(user_id);
+CREATE INDEX idx_post_bookmarks_user_id ON post_bookmarks(user_id, created_at);
+
+-- Keep counters in sync with the join tables. The increment is a single
+-- row update, so concurrent likes and unlikes never lose a change.
+CREATE OR REPLACE FUNCTION update_post_like_count()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE posts SET like_count = like_count + 1 WHERE id = NEW.post_id;
+    ELSE
+        UPDATE posts SET like_count = like_count - 1 WHERE id = OLD.post_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE OR REPLACE FUNCTION update_post_bookmark_count()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE posts SET bookmark_count = bookmark_count + 1 WHERE id = NEW.post_id;
+    ELSE
+        UPDATE posts SET bookmark_count = bookmark_count - 1 WHERE id = OLD.post_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_post_likes_count AFTER INSERT OR DELETE ON post_likes
+    FOR EACH ROW EXECUTE FUNCTION update_post_like_count();
+
+CREATE TRIGGER update_post_bookmarks_count AFTER INSERT OR DELETE ON post_bookmarks
+    FOR EACH ROW EXECUTE FUNCTION update_post_bookmark_count();
+
+-- Counter changes are not edits, so they must not touch posts.updated_at
+CREATE OR REPLACE FUNCTION update_posts_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.like_count IS DISTINCT FROM OLD.like_count
+        OR NEW.bookmark_count IS DISTINCT FROM OLD.bookmark_count THEN
+        RETURN NEW;
+    END IF;
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS update_posts_updated_at ON posts;
+CREATE TRIGGER update_posts_updated_at BEFORE UPDATE ON posts
+    FOR EACH ROW EXECUTE FUNCTION update_posts_updated_at_column();
\ No newline at end of file
@@ -0,0 +1,22 @@
+-- Restore the generic updated_at trigger on users
+DROP TRIGGER IF EXISTS update_users_updated_at ON users;
+CREATE TRIGGER update_users_updated_at BEFORE UPDATE ON users
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+DROP FUNCTION IF EXISTS update_users_updated_at_column();
+
+-- Restore the posts trigger function without version handling
+CREATE OR REPLACE FUNCTION update_posts_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.like_count IS DISTINCT FROM OLD.like_count
+        OR NEW.bookmark_count IS DISTINCT FROM OLD.bookmark_count THEN
+        RETURN NEW;
+    END IF;
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+-- Drop columns
+ALTER TABLE users DROP COLUMN IF EXISTS version;
+ALTER TABLE posts DROP COLUMN IF EXISTS version;
\ No newline at end of file
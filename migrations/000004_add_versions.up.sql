@@ -0,0 +1,32 @@
+-- Add version columns for optimistic concurrency control
+ALTER TABLE posts ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE users ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- Bump the post version together with updated_at; counter changes still
+-- leave both untouched
+CREATE OR REPLACE FUNCTION update_posts_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.like_count IS DISTINCT FROM OLD.like_count
+        OR NEW.bookmark_count IS DISTINCT FROM OLD.bookmark_count THEN
+        RETURN NEW;
+    END IF;
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    NEW.version = OLD.version + 1;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+-- Bump the user version together with updated_at
+CREATE OR REPLACE FUNCTION update_users_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    NEW.version = OLD.version + 1;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS update_users_updated_at ON users;
+CREATE TRIGGER update_users_updated_at BEFORE UPDATE ON users
+    FOR EACH ROW EXECUTE FUNCTION update_users_updated_at_column();
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Drop columns
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS content_html,
+    DROP COLUMN IF EXISTS content_format;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- Add content format and cached rendered HTML to posts
+ALTER TABLE posts
+    ADD COLUMN content_format VARCHAR(20) NOT NULL DEFAULT 'plain'
+        CHECK (content_format IN ('plain', 'markdown')),
+    ADD COLUMN content_html TEXT;
\ No newline at end of file
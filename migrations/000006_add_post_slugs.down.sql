@@ -0,0 +1,9 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_post_slug_history_post_id;
+DROP INDEX IF EXISTS idx_posts_slug;
+
+-- Drop tables
+DROP TABLE IF EXISTS post_slug_history;
+
+-- Drop columns
+ALTER TABLE posts DROP COLUMN IF EXISTS slug;
\ No newline at end of file
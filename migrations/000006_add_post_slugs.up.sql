@@ -0,0 +1,15 @@
+-- Add slugs to posts; existing posts get an ID-based slug
+ALTER TABLE posts ADD COLUMN slug VARCHAR(255);
+UPDATE posts SET slug = 'post-' || id;
+ALTER TABLE posts ALTER COLUMN slug SET NOT NULL;
+
+-- Create post_slug_history table
+CREATE TABLE IF NOT EXISTS post_slug_history (
+    slug VARCHAR(255) PRIMARY KEY,
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE UNIQUE INDEX idx_posts_slug ON posts(slug);
+CREATE INDEX idx_post_slug_history_post_id ON post_slug_history(post_id);
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_attachments_orphaned;
+DROP INDEX IF EXISTS idx_attachments_post_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS attachments;
\ No newline at end of file
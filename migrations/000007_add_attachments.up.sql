@@ -0,0 +1,20 @@
+-- Create attachments table
+-- Deleting a post or user keeps the row with a NULL reference so the
+-- cleanup worker can remove the stored files before dropping it.
+CREATE TABLE IF NOT EXISTS attachments (
+    id SERIAL PRIMARY KEY,
+    post_id INTEGER REFERENCES posts(id) ON DELETE SET NULL,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    storage_key VARCHAR(255) UNIQUE NOT NULL,
+    thumbnail_key VARCHAR(255),
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    width INTEGER,
+    height INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_attachments_post_id ON attachments(post_id);
+CREATE INDEX idx_attachments_orphaned ON attachments(id) WHERE post_id IS NULL;
\ No newline at end of file
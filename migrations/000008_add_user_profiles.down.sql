@@ -0,0 +1,6 @@
+-- Drop columns
+ALTER TABLE users
+    DROP COLUMN IF EXISTS avatar_key,
+    DROP COLUMN IF EXISTS location,
+    DROP COLUMN IF EXISTS website,
+    DROP COLUMN IF EXISTS bio;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- Add public profile fields to users
+-- avatar_key is the storage key prefix of the resized avatar images.
+ALTER TABLE users
+    ADD COLUMN bio TEXT,
+    ADD COLUMN website VARCHAR(255),
+    ADD COLUMN location VARCHAR(100),
+    ADD COLUMN avatar_key VARCHAR(255);
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_posts_user_published;
+DROP INDEX IF EXISTS idx_follows_followee_id;
+DROP INDEX IF EXISTS idx_follows_follower_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS follows;
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- Create follows table
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followee_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+-- Create indexes
+CREATE INDEX idx_follows_follower_id ON follows(follower_id, created_at);
+CREATE INDEX idx_follows_followee_id ON follows(followee_id, created_at);
+
+-- The home feed reads the newest published posts of each followed author
+-- straight from this index, so every published post needs a published_at.
+UPDATE posts SET published_at = created_at
+WHERE status = 'published' AND published_at IS NULL;
+
+CREATE INDEX idx_posts_user_published ON posts(user_id, published_at DESC, id DESC)
+    WHERE status = 'published';
\ No newline at end of file
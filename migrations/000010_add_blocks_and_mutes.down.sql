@@ -0,0 +1,8 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_user_mutes_created_at;
+DROP INDEX IF EXISTS idx_user_blocks_created_at;
+DROP INDEX IF EXISTS idx_user_blocks_blocked_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS user_mutes;
+DROP TABLE IF EXISTS user_blocks;
\ No newline at end of file
@@ -0,0 +1,23 @@
+-- Create user_blocks table
+CREATE TABLE IF NOT EXISTS user_blocks (
+    blocker_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id)
+);
+
+-- Create user_mutes table
+CREATE TABLE IF NOT EXISTS user_mutes (
+    muter_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    muted_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (muter_id, muted_id),
+    CHECK (muter_id <> muted_id)
+);
+
+-- Create indexes
+-- List queries look up whether a post's author blocked the viewer.
+CREATE INDEX idx_user_blocks_blocked_id ON user_blocks(blocked_id, blocker_id);
+CREATE INDEX idx_user_blocks_created_at ON user_blocks(blocker_id, created_at);
+CREATE INDEX idx_user_mutes_created_at ON user_mutes(muter_id, created_at);
\ No newline at end of file
@@ -0,0 +1,17 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_moderation_log_moderator_id;
+DROP INDEX IF EXISTS idx_moderation_log_created_at;
+DROP INDEX IF EXISTS idx_reports_pending;
+DROP INDEX IF EXISTS idx_reports_target;
+DROP INDEX IF EXISTS idx_reports_queue;
+
+-- Drop tables
+DROP TABLE IF EXISTS moderation_log;
+DROP TABLE IF EXISTS reports;
+
+-- Drop columns
+ALTER TABLE users
+    DROP COLUMN IF EXISTS role;
+
+-- Hidden posts have no meaning without moderation
+UPDATE posts SET status = 'archived' WHERE status = 'hidden';
\ No newline at end of file
@@ -0,0 +1,47 @@
+-- Add roles to users
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user'
+        CHECK (role IN ('user', 'editor', 'admin'));
+
+-- Create reports table
+-- target_id refers to posts, users or comments depending on target_type,
+-- so it has no foreign key; reports outlive the content they are about.
+CREATE TABLE IF NOT EXISTS reports (
+    id SERIAL PRIMARY KEY,
+    reporter_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('post', 'user', 'comment')),
+    target_id INTEGER NOT NULL,
+    reason VARCHAR(30) NOT NULL
+        CHECK (reason IN ('spam', 'harassment', 'hate_speech', 'violence', 'sexual_content', 'misinformation', 'other')),
+    details TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'claimed', 'resolved', 'dismissed')),
+    claimed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    claimed_at TIMESTAMP WITH TIME ZONE,
+    closed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    resolution VARCHAR(20),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create moderation_log table
+CREATE TABLE IF NOT EXISTS moderation_log (
+    id SERIAL PRIMARY KEY,
+    moderator_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    report_id INTEGER REFERENCES reports(id) ON DELETE SET NULL,
+    action VARCHAR(30) NOT NULL,
+    target_type VARCHAR(20) NOT NULL,
+    target_id INTEGER NOT NULL,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_reports_queue ON reports(status, created_at);
+CREATE INDEX idx_reports_target ON reports(target_type, target_id);
+-- A user can have only one pending report per target.
+CREATE UNIQUE INDEX idx_reports_pending ON reports(reporter_id, target_type, target_id)
+    WHERE status IN ('open', 'claimed');
+CREATE INDEX idx_moderation_log_created_at ON moderation_log(created_at);
+CREATE INDEX idx_moderation_log_moderator_id ON moderation_log(moderator_id);
\ No newline at end of file
@@ -0,0 +1,16 @@
+-- Trashed rows were deleted from the user's point of view
+DELETE FROM posts WHERE deleted_at IS NOT NULL;
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+-- Restore indexes
+DROP INDEX IF EXISTS idx_posts_user_published;
+CREATE INDEX idx_posts_user_published ON posts(user_id, published_at DESC, id DESC)
+    WHERE status = 'published';
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_users_trash;
+DROP INDEX IF EXISTS idx_posts_trash;
+
+-- Drop columns
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS deleted_at;
\ No newline at end of file
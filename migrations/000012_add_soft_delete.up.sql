@@ -0,0 +1,13 @@
+-- Deleting a post or user moves it to the trash; the retention worker
+-- removes it for good once it has been there long enough
+ALTER TABLE posts ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Create indexes
+CREATE INDEX idx_posts_trash ON posts(user_id, deleted_at DESC) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_users_trash ON users(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- The home feed only reads posts that are not in the trash
+DROP INDEX IF EXISTS idx_posts_user_published;
+CREATE INDEX idx_posts_user_published ON posts(user_id, published_at DESC, id DESC)
+    WHERE status = 'published' AND deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,14 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_users_erasure;
+DROP INDEX IF EXISTS idx_data_exports_stored;
+DROP INDEX IF EXISTS idx_data_exports_pending;
+DROP INDEX IF EXISTS idx_data_exports_in_progress;
+DROP INDEX IF EXISTS idx_data_exports_user_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS data_exports;
+
+-- Drop columns
+ALTER TABLE users
+    DROP COLUMN IF EXISTS erased_at,
+    DROP COLUMN IF EXISTS erasure_scheduled_at;
\ No newline at end of file
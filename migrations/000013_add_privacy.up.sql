@@ -0,0 +1,32 @@
+-- Account erasure is scheduled after a grace period; anonymized accounts
+-- keep their row so their posts survive
+ALTER TABLE users
+    ADD COLUMN erasure_scheduled_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN erased_at TIMESTAMP WITH TIME ZONE;
+
+-- Create data_exports table
+-- Erasing a user keeps the row with a NULL reference so the export worker
+-- can remove the stored archive before dropping it.
+CREATE TABLE IF NOT EXISTS data_exports (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'processing', 'ready', 'failed', 'expired')),
+    storage_key VARCHAR(255),
+    size_bytes BIGINT,
+    token VARCHAR(64) UNIQUE,
+    error TEXT,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create indexes
+CREATE INDEX idx_data_exports_user_id ON data_exports(user_id, created_at);
+-- A user can have only one export in progress.
+CREATE UNIQUE INDEX idx_data_exports_in_progress ON data_exports(user_id)
+    WHERE status IN ('pending', 'processing');
+CREATE INDEX idx_data_exports_pending ON data_exports(id) WHERE status = 'pending';
+CREATE INDEX idx_data_exports_stored ON data_exports(expires_at) WHERE storage_key IS NOT NULL;
+CREATE INDEX idx_users_erasure ON users(erasure_scheduled_at) WHERE erasure_scheduled_at IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_post_stats_trending;
+DROP INDEX IF EXISTS idx_post_stats_view_count;
+DROP INDEX IF EXISTS idx_post_views_hour;
+
+-- Drop tables
+DROP TABLE IF EXISTS post_stats;
+DROP TABLE IF EXISTS post_views;
\ No newline at end of file
@@ -0,0 +1,24 @@
+-- Create post_views table
+-- Views are counted per hour; the view counter writes buffered counts in
+-- batches and the trending job reads the recent buckets.
+CREATE TABLE IF NOT EXISTS post_views (
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    hour TIMESTAMP WITH TIME ZONE NOT NULL,
+    views INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (post_id, hour)
+);
+
+-- Create post_stats table
+-- Kept apart from posts so counting views does not touch updated_at or
+-- the post version.
+CREATE TABLE IF NOT EXISTS post_stats (
+    post_id INTEGER PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    view_count BIGINT NOT NULL DEFAULT 0,
+    trending_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    scored_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create indexes
+CREATE INDEX idx_post_views_hour ON post_views(hour);
+CREATE INDEX idx_post_stats_view_count ON post_stats(view_count DESC);
+CREATE INDEX idx_post_stats_trending ON post_stats(trending_score DESC) WHERE trending_score > 0;
\ No newline at end of file
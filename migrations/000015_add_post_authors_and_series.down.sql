@@ -0,0 +1,14 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_series_user_id;
+DROP INDEX IF EXISTS idx_post_authors_user_id;
+DROP INDEX IF EXISTS idx_post_authors_owner;
+
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_series_updated_at ON series;
+DROP TRIGGER IF EXISTS add_post_owner ON posts;
+DROP FUNCTION IF EXISTS add_post_owner();
+
+-- Drop tables
+DROP TABLE IF EXISTS series_posts;
+DROP TABLE IF EXISTS series;
+DROP TABLE IF EXISTS post_authors;
\ No newline at end of file
@@ -0,0 +1,54 @@
+-- Create post_authors table
+-- posts.user_id stays the owner; the owner row here mirrors it so
+-- authorization checks only need to look at one table.
+CREATE TABLE IF NOT EXISTS post_authors (
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'co-author')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, user_id)
+);
+
+-- Every existing post gets its author as owner
+INSERT INTO post_authors (post_id, user_id, role, created_at)
+SELECT id, user_id, 'owner', created_at FROM posts
+ON CONFLICT DO NOTHING;
+
+-- New posts get their owner row on insert
+CREATE OR REPLACE FUNCTION add_post_owner()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO post_authors (post_id, user_id, role) VALUES (NEW.id, NEW.user_id, 'owner');
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER add_post_owner AFTER INSERT ON posts
+    FOR EACH ROW EXECUTE FUNCTION add_post_owner();
+
+-- Create series table
+CREATE TABLE IF NOT EXISTS series (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_series_updated_at BEFORE UPDATE ON series
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Create series_posts table
+-- A post belongs to at most one series.
+CREATE TABLE IF NOT EXISTS series_posts (
+    post_id INTEGER PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    series_id INTEGER NOT NULL REFERENCES series(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    UNIQUE (series_id, position)
+);
+
+-- Create indexes
+CREATE UNIQUE INDEX idx_post_authors_owner ON post_authors(post_id) WHERE role = 'owner';
+CREATE INDEX idx_post_authors_user_id ON post_authors(user_id);
+CREATE INDEX idx_series_user_id ON series(user_id, created_at DESC);
\ No newline at end of file
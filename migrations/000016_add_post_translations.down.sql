@@ -0,0 +1,13 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_post_translations_locale;
+DROP INDEX IF EXISTS idx_post_translations_slug;
+
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_post_translations_updated_at ON post_translations;
+DROP FUNCTION IF EXISTS update_post_translations_updated_at_column();
+
+-- Drop tables
+DROP TABLE IF EXISTS post_translations;
+
+-- Drop columns
+ALTER TABLE posts DROP COLUMN IF EXISTS locale;
\ No newline at end of file
@@ -0,0 +1,43 @@
+-- Posts record the locale they are written in. Existing posts are taken
+-- to be in the configured default locale, which make migrate-up passes in
+-- as the app.default_locale setting; without it the migration stops at
+-- SET NOT NULL rather than guess. The API always sets the locale of new
+-- posts, so the column has no default.
+ALTER TABLE posts ADD COLUMN locale VARCHAR(10);
+UPDATE posts SET locale = NULLIF(current_setting('app.default_locale', true), '');
+ALTER TABLE posts ALTER COLUMN locale SET NOT NULL;
+
+-- Create post_translations table
+-- Translation slugs share one namespace with post slugs; the API keeps
+-- them apart when generating them.
+CREATE TABLE IF NOT EXISTS post_translations (
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    locale VARCHAR(10) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    content TEXT,
+    content_format VARCHAR(20) NOT NULL DEFAULT 'plain'
+        CHECK (content_format IN ('plain', 'markdown')),
+    content_html TEXT,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, locale)
+);
+
+-- Bump the translation version together with updated_at
+CREATE OR REPLACE FUNCTION update_post_translations_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    NEW.version = OLD.version + 1;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_post_translations_updated_at BEFORE UPDATE ON post_translations
+    FOR EACH ROW EXECUTE FUNCTION update_post_translations_updated_at_column();
+
+-- Create indexes
+CREATE UNIQUE INDEX idx_post_translations_slug ON post_translations(slug);
+CREATE INDEX idx_post_translations_locale ON post_translations(locale);
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_post_imports_post_id;
+DROP INDEX IF EXISTS idx_post_tags_tag;
+
+-- Drop tables
+DROP TABLE IF EXISTS post_imports;
+DROP TABLE IF EXISTS post_tags;
\ No newline at end of file
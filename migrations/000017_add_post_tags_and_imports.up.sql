@@ -0,0 +1,22 @@
+-- Create post_tags table
+CREATE TABLE IF NOT EXISTS post_tags (
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    tag VARCHAR(50) NOT NULL,
+    PRIMARY KEY (post_id, tag)
+);
+
+-- Create post_imports table
+-- Remembers which post an imported entry became, so importing the same
+-- export again skips it instead of creating a duplicate. A purged post
+-- forgets its entry and is imported again.
+CREATE TABLE IF NOT EXISTS post_imports (
+    source_key VARCHAR(512) PRIMARY KEY,
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    format VARCHAR(20) NOT NULL CHECK (format IN ('markdown', 'wxr')),
+    imported_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    imported_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_post_tags_tag ON post_tags(tag);
+CREATE INDEX idx_post_imports_post_id ON post_imports(post_id);
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_post_drafts_user_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS post_drafts;
\ No newline at end of file
@@ -0,0 +1,18 @@
+-- Create post_drafts table
+-- Autosaved edits, kept apart from the post so saving them neither changes
+-- its version nor its content. Each author has one autosave per post that
+-- every save overwrites.
+CREATE TABLE IF NOT EXISTS post_drafts (
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    content TEXT NOT NULL DEFAULT '',
+    content_format VARCHAR(20) NOT NULL DEFAULT 'plain'
+        CHECK (content_format IN ('plain', 'markdown')),
+    base_version INTEGER NOT NULL,
+    saved_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, user_id)
+);
+
+-- Create indexes
+CREATE INDEX idx_post_drafts_user_id ON post_drafts(user_id);
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_notification_preferences_email_digest;
+DROP INDEX IF EXISTS idx_notifications_unread;
+DROP INDEX IF EXISTS idx_notifications_user_id_created_at;
+
+-- Drop tables
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS notifications;
\ No newline at end of file
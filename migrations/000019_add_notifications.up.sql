@@ -0,0 +1,34 @@
+-- Create notifications table
+-- One row per thing a user is told about. The actor is kept as NULL once
+-- their account is purged so the notification stays in the list.
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    type VARCHAR(20) NOT NULL
+        CHECK (type IN ('comment', 'reply', 'like', 'follow')),
+    post_id INTEGER REFERENCES posts(id) ON DELETE CASCADE,
+    comment_id INTEGER REFERENCES comments(id) ON DELETE CASCADE,
+    read_at TIMESTAMP WITH TIME ZONE,
+    emailed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create notification_preferences table
+-- Users without a row get every notification and no email digest.
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    comments BOOLEAN NOT NULL DEFAULT true,
+    replies BOOLEAN NOT NULL DEFAULT true,
+    likes BOOLEAN NOT NULL DEFAULT true,
+    follows BOOLEAN NOT NULL DEFAULT true,
+    email_digest VARCHAR(10) NOT NULL DEFAULT 'off'
+        CHECK (email_digest IN ('off', 'daily', 'weekly')),
+    last_digest_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_notifications_user_id_created_at ON notifications(user_id, created_at DESC);
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+CREATE INDEX idx_notification_preferences_email_digest ON notification_preferences(email_digest) WHERE email_digest <> 'off';
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS notify_stream_events ON stream_events;
+DROP FUNCTION IF EXISTS notify_stream_event();
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_stream_events_created_at;
+
+-- Drop tables
+DROP TABLE IF EXISTS stream_events;
\ No newline at end of file
@@ -0,0 +1,27 @@
+-- Create stream_events table
+-- Events pushed to connected clients, kept for a while so clients can
+-- replay what they missed after reconnecting. Events without a user are
+-- for everyone.
+CREATE TABLE IF NOT EXISTS stream_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    data JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_stream_events_created_at ON stream_events(created_at);
+
+-- Announce new events to every replica, once the inserting transaction
+-- commits.
+CREATE OR REPLACE FUNCTION notify_stream_event()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('stream_events', NEW.id::text);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER notify_stream_events AFTER INSERT ON stream_events
+    FOR EACH ROW EXECUTE FUNCTION notify_stream_event();
\ No newline at end of file
@@ -0,0 +1,11 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_webhook_deliveries_due;
+DROP INDEX IF EXISTS idx_webhook_deliveries_webhook_id_created_at;
+DROP INDEX IF EXISTS idx_webhooks_user_id;
+
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_webhooks_updated_at ON webhooks;
+
+-- Drop tables
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
\ No newline at end of file
@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/internal/storage"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachmentHandler(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	attachmentHandler := handlers.NewAttachmentHandler(nil, storage.NewLocal(t.TempDir())) // 以下用例均在访问数据库之前返回
+	router.GET("/posts/:id/attachments", attachmentHandler.List)
+	router.GET("/attachments/:id/file", attachmentHandler.File)
+	router.GET("/attachments/:id/thumbnail", attachmentHandler.Thumbnail)
+	router.POST("/posts/:id/attachments", attachmentHandler.Upload)
+	router.DELETE("/attachments/:id", attachmentHandler.Delete)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("upload fails with invalid post ID", func(t *testing.T) {
+		w := client.Post("/posts/abc/attachments", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid post ID", response["error"])
+	})
+
+	t.Run("upload fails without authentication", func(t *testing.T) {
+		w := client.Post("/posts/1/attachments", nil)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("list fails with invalid post ID", func(t *testing.T) {
+		w := client.Get("/posts/abc/attachments")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid post ID", response["error"])
+	})
+
+	for _, path := range []string{"/attachments/abc/file", "/attachments/abc/thumbnail"} {
+		t.Run("download fails with invalid attachment ID "+path, func(t *testing.T) {
+			w := client.Get(path)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response map[string]interface{}
+			err := helpers.ParseJSON(w, &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "Invalid attachment ID", response["error"])
+		})
+	}
+
+	t.Run("delete fails with invalid attachment ID", func(t *testing.T) {
+		w := client.Delete("/attachments/abc")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid attachment ID", response["error"])
+	})
+
+	t.Run("delete fails without authentication", func(t *testing.T) {
+		w := client.Delete("/attachments/1")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+}
\ No newline at end of file
@@ -0,0 +1,109 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/internal/middleware"
+	"github.com/demo/demo-gin/internal/storage"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAvatarHandler(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	avatarHandler := handlers.NewAvatarHandler(nil, storage.NewLocal(t.TempDir())) // 以下用例均在访问数据库之前返回
+	router.GET("/users/:id/avatar", avatarHandler.Get)
+	router.PUT("/users/:id/avatar", avatarHandler.Upload)
+	router.DELETE("/users/:id/avatar", avatarHandler.Delete)
+
+	// 需要认证的路由，当前中间件固定设置userID为1
+	protected := router.Group("/api")
+	protected.Use(middleware.Auth())
+	protected.PUT("/users/:id/avatar", avatarHandler.Upload)
+	protected.DELETE("/users/:id/avatar", avatarHandler.Delete)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("get fails with invalid user ID", func(t *testing.T) {
+		w := client.Get("/users/abc/avatar")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid user ID", response["error"])
+	})
+
+	t.Run("get fails with unsupported size", func(t *testing.T) {
+		w := client.Get("/users/1/avatar?size=100")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid size, must be one of 64, 128, 256", response["error"])
+	})
+
+	t.Run("upload requires authentication", func(t *testing.T) {
+		w := client.Put("/users/1/avatar", nil)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("upload to another account is forbidden", func(t *testing.T) {
+		client.SetAuth("valid_token")
+		defer client.SetAuth("")
+
+		w := client.Put("/api/users/2/avatar", nil)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "You can only update your own account", response["error"])
+	})
+
+	t.Run("upload without a file fails", func(t *testing.T) {
+		client.SetAuth("valid_token")
+		defer client.SetAuth("")
+
+		w := client.Put("/api/users/1/avatar", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "File is required", response["error"])
+	})
+
+	t.Run("delete of another account is forbidden", func(t *testing.T) {
+		client.SetAuth("valid_token")
+		defer client.SetAuth("")
+
+		w := client.Delete("/api/users/2/avatar")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "You can only update your own account", response["error"])
+	})
+}
\ No newline at end of file
@@ -0,0 +1,91 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/internal/middleware"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockHandler(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	blockHandler := handlers.NewBlockHandler(nil) // 以下用例均在访问数据库之前返回
+	router.PUT("/users/:id/block", blockHandler.Block)
+	router.DELETE("/users/:id/block", blockHandler.Unblock)
+	router.PUT("/users/:id/mute", blockHandler.Mute)
+	router.DELETE("/users/:id/mute", blockHandler.Unmute)
+	router.GET("/users/me/blocks", blockHandler.ListBlocks)
+	router.GET("/users/me/mutes", blockHandler.ListMutes)
+
+	// 需要认证的路由，当前中间件固定设置userID为1
+	protected := router.Group("/api")
+	protected.Use(middleware.Auth())
+	protected.PUT("/users/:id/block", blockHandler.Block)
+	protected.PUT("/users/:id/mute", blockHandler.Mute)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	for _, path := range []string{"/users/abc/block", "/users/abc/mute"} {
+		t.Run("fails with invalid user ID "+path, func(t *testing.T) {
+			w := client.Put(path, nil)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response map[string]interface{}
+			err := helpers.ParseJSON(w, &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "Invalid user ID", response["error"])
+		})
+	}
+
+	for _, path := range []string{"/users/2/block", "/users/2/mute"} {
+		t.Run("requires authentication "+path, func(t *testing.T) {
+			w := client.Delete(path)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+			var response map[string]interface{}
+			err := helpers.ParseJSON(w, &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "User not authenticated", response["error"])
+		})
+	}
+
+	for _, path := range []string{"/api/users/1/block", "/api/users/1/mute"} {
+		t.Run("fails for yourself "+path, func(t *testing.T) {
+			client.SetAuth("valid_token")
+			defer client.SetAuth("")
+
+			w := client.Put(path, nil)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response map[string]interface{}
+			err := helpers.ParseJSON(w, &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "You cannot block or mute yourself", response["error"])
+		})
+	}
+
+	for _, path := range []string{"/users/me/blocks", "/users/me/mutes"} {
+		t.Run("list requires authentication "+path, func(t *testing.T) {
+			w := client.Get(path)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+			var response map[string]interface{}
+			err := helpers.ParseJSON(w, &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "User not authenticated", response["error"])
+		})
+	}
+}
\ No newline at end of file
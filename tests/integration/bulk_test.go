@@ -0,0 +1,153 @@
+package integration
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/internal/i18n"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkPosts(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	postHandler := handlers.NewPostHandler(nil, nil, i18n.Locales{}, nil) // 以下用例均在访问数据库之前返回
+	router.POST("/posts/bulk", postHandler.Bulk)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("fails without operations", func(t *testing.T) {
+		w := client.Post("/posts/bulk", map[string]interface{}{
+			"operations": []interface{}{},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("fails with unknown operation", func(t *testing.T) {
+		w := client.Post("/posts/bulk", map[string]interface{}{
+			"operations": []map[string]interface{}{
+				{"op": "publish", "id": 1},
+				{"op": "feature", "id": 2},
+			},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("fails with invalid post ID", func(t *testing.T) {
+		w := client.Post("/posts/bulk", map[string]interface{}{
+			"operations": []map[string]interface{}{
+				{"op": "archive", "id": 0},
+			},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("fails with invalid mode", func(t *testing.T) {
+		w := client.Post("/posts/bulk", map[string]interface{}{
+			"mode": "best_effort",
+			"operations": []map[string]interface{}{
+				{"op": "delete", "id": 1},
+			},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("fails with more than 100 operations", func(t *testing.T) {
+		operations := make([]map[string]interface{}, 101)
+		for i := range operations {
+			operations[i] = map[string]interface{}{"op": "publish", "id": i + 1}
+		}
+
+		w := client.Post("/posts/bulk", map[string]interface{}{
+			"operations": operations,
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("retag fails without tags", func(t *testing.T) {
+		w := client.Post("/posts/bulk", map[string]interface{}{
+			"operations": []map[string]interface{}{
+				{"op": "retag", "id": 1},
+			},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("fails with tags on other operations", func(t *testing.T) {
+		w := client.Post("/posts/bulk", map[string]interface{}{
+			"operations": []map[string]interface{}{
+				{"op": "publish", "id": 1, "tags": []string{"go"}},
+			},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("retag fails with too long tag", func(t *testing.T) {
+		w := client.Post("/posts/bulk", map[string]interface{}{
+			"operations": []map[string]interface{}{
+				{"op": "retag", "id": 1, "tags": []string{strings.Repeat("a", 51)}},
+			},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("retag fails with more than 20 tags", func(t *testing.T) {
+		tags := make([]string, 21)
+		for i := range tags {
+			tags[i] = "tag" + strconv.Itoa(i)
+		}
+
+		w := client.Post("/posts/bulk", map[string]interface{}{
+			"operations": []map[string]interface{}{
+				{"op": "retag", "id": 1, "tags": tags},
+			},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("retag with empty tags requires authentication", func(t *testing.T) {
+		w := client.Post("/posts/bulk", map[string]interface{}{
+			"operations": []map[string]interface{}{
+				{"op": "retag", "id": 1, "tags": []string{}},
+				{"op": "retag", "id": 2, "tags": []string{"go", "Go", "gin"}, "if_match": `"3"`},
+			},
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("requires authentication", func(t *testing.T) {
+		w := client.Post("/posts/bulk", map[string]interface{}{
+			"mode": "per_item",
+			"operations": []map[string]interface{}{
+				{"op": "publish", "id": 1},
+				{"op": "delete", "id": 2, "if_match": `"3"`},
+			},
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+}
\ No newline at end of file
@@ -0,0 +1,117 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentHandler(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	commentHandler := handlers.NewCommentHandler(nil, nil) // 以下用例均在访问数据库之前返回
+	router.GET("/posts/:id/comments", commentHandler.List)
+	router.POST("/posts/:id/comments", commentHandler.Create)
+	router.POST("/posts/:id/comments/close", commentHandler.Close)
+	router.PUT("/comments/:id", commentHandler.Update)
+	router.DELETE("/comments/:id", commentHandler.Delete)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("list fails with invalid post ID", func(t *testing.T) {
+		w := client.Get("/posts/abc/comments")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid post ID", response["error"])
+	})
+
+	t.Run("list fails with unknown view", func(t *testing.T) {
+		w := client.Get("/posts/1/comments?view=nested")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid view, must be threaded or flat", response["error"])
+	})
+
+	t.Run("create fails with missing content", func(t *testing.T) {
+		w := client.Post("/posts/1/comments", map[string]interface{}{
+			"parent_id": 1,
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Contains(t, response, "error")
+	})
+
+	t.Run("create requires authentication", func(t *testing.T) {
+		w := client.Post("/posts/1/comments", map[string]interface{}{
+			"content": "Great post!",
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("update fails with invalid comment ID", func(t *testing.T) {
+		w := client.Put("/comments/abc", map[string]interface{}{
+			"content": "Edited",
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid comment ID", response["error"])
+	})
+
+	t.Run("update fails with empty content", func(t *testing.T) {
+		w := client.Put("/comments/1", map[string]interface{}{
+			"content": "",
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("update requires authentication", func(t *testing.T) {
+		w := client.Put("/comments/1", map[string]interface{}{
+			"content": "Edited",
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("delete requires authentication", func(t *testing.T) {
+		w := client.Delete("/comments/1")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("close requires authentication", func(t *testing.T) {
+		w := client.Post("/posts/1/comments/close", nil)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
\ No newline at end of file
@@ -0,0 +1,151 @@
+package integration
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/internal/signing"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigning(t *testing.T) {
+	signer := signing.New("secret")
+	now := time.Now()
+	expires := now.Add(time.Hour)
+	signature := signer.Sign("post-preview:1", expires)
+
+	t.Run("verifies its own signatures", func(t *testing.T) {
+		assert.NoError(t, signer.Verify("post-preview:1", expires, signature, now))
+	})
+
+	t.Run("rejects another resource or expiry time", func(t *testing.T) {
+		assert.ErrorIs(t, signer.Verify("post-preview:2", expires, signature, now), signing.ErrInvalid)
+		assert.ErrorIs(t, signer.Verify("post-preview:1", expires.Add(time.Hour), signature, now), signing.ErrInvalid)
+	})
+
+	t.Run("rejects signatures made with another secret", func(t *testing.T) {
+		other := signing.New("other").Sign("post-preview:1", expires)
+		assert.ErrorIs(t, signer.Verify("post-preview:1", expires, other, now), signing.ErrInvalid)
+		assert.ErrorIs(t, signer.Verify("post-preview:1", expires, "not base64!", now), signing.ErrInvalid)
+	})
+
+	t.Run("reports expired signatures", func(t *testing.T) {
+		assert.ErrorIs(t, signer.Verify("post-preview:1", expires, signature, expires), signing.ErrExpired)
+	})
+
+	t.Run("is disabled without a secret", func(t *testing.T) {
+		assert.Nil(t, signing.New(""))
+	})
+}
+
+func TestDrafts(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	signer := signing.New("secret")
+
+	// 创建测试路由
+	router := gin.New()
+	draftHandler := handlers.NewDraftHandler(nil, signer, 7*24*time.Hour) // 以下用例均在访问数据库之前返回
+	router.GET("/posts/:id/autosave", draftHandler.GetAutosave)
+	router.PUT("/posts/:id/autosave", draftHandler.Autosave)
+	router.DELETE("/posts/:id/autosave", draftHandler.DeleteAutosave)
+	router.POST("/posts/:id/preview-links", draftHandler.CreatePreviewLink)
+	router.GET("/posts/:id/preview", draftHandler.Preview)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("autosave fails with invalid post ID", func(t *testing.T) {
+		w := client.Put("/posts/abc/autosave", map[string]interface{}{"title": "Draft", "base_version": 1})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("autosave fails without base version", func(t *testing.T) {
+		w := client.Put("/posts/1/autosave", map[string]interface{}{"title": "Draft", "content": "Half a sen"})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("autosave fails with invalid content format", func(t *testing.T) {
+		w := client.Put("/posts/1/autosave", map[string]interface{}{"content_format": "html", "base_version": 1})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("autosave requires authentication", func(t *testing.T) {
+		w := client.Put("/posts/1/autosave", map[string]interface{}{"content": "Half a sen", "base_version": 3})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("get autosave requires authentication", func(t *testing.T) {
+		w := client.Get("/posts/1/autosave")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("delete autosave requires authentication", func(t *testing.T) {
+		w := client.Delete("/posts/1/autosave")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("preview link fails with too short lifetime", func(t *testing.T) {
+		w := client.Post("/posts/1/preview-links", map[string]interface{}{"expires_in": 10})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("preview link fails with too long lifetime", func(t *testing.T) {
+		w := client.Post("/posts/1/preview-links", map[string]interface{}{"expires_in": 8 * 24 * 3600})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("preview link requires authentication", func(t *testing.T) {
+		w := client.Post("/posts/1/preview-links", map[string]interface{}{"expires_in": 3600})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("preview fails with invalid expiry", func(t *testing.T) {
+		w := client.Get("/posts/1/preview?expires=tomorrow&signature=x")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("preview fails with invalid signature", func(t *testing.T) {
+		expires := time.Now().Add(time.Hour)
+		signature := signer.Sign("post-preview:1", expires)
+
+		w := client.Get("/posts/2/preview?expires=" + strconv.FormatInt(expires.Unix(), 10) + "&signature=" + signature)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("preview fails with expired link", func(t *testing.T) {
+		expires := time.Now().Add(-time.Minute).Truncate(time.Second)
+		signature := signer.Sign("post-preview:1", expires)
+
+		w := client.Get("/posts/1/preview?expires=" + strconv.FormatInt(expires.Unix(), 10) + "&signature=" + signature)
+
+		assert.Equal(t, http.StatusGone, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Preview link has expired", response["error"])
+	})
+}
\ No newline at end of file
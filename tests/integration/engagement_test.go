@@ -0,0 +1,60 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngagementHandler(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	engagementHandler := handlers.NewEngagementHandler(nil, nil) // 以下用例均在访问数据库之前返回
+	router.PUT("/posts/:id/like", engagementHandler.Like)
+	router.DELETE("/posts/:id/like", engagementHandler.Unlike)
+	router.PUT("/posts/:id/bookmark", engagementHandler.Bookmark)
+	router.DELETE("/posts/:id/bookmark", engagementHandler.Unbookmark)
+	router.GET("/users/me/bookmarks", engagementHandler.ListBookmarks)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("like fails with invalid post ID", func(t *testing.T) {
+		w := client.Put("/posts/abc/like", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid post ID", response["error"])
+	})
+
+	t.Run("like and bookmark endpoints require authentication", func(t *testing.T) {
+		for _, path := range []string{"/posts/1/like", "/posts/1/bookmark"} {
+			w := client.Put(path, nil)
+			assert.Equal(t, http.StatusUnauthorized, w.Code, "PUT %s", path)
+
+			w = client.Delete(path)
+			assert.Equal(t, http.StatusUnauthorized, w.Code, "DELETE %s", path)
+		}
+	})
+
+	t.Run("bookmark list requires authentication", func(t *testing.T) {
+		w := client.Get("/users/me/bookmarks")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+}
\ No newline at end of file
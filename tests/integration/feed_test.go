@@ -0,0 +1,65 @@
+package integration
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedHandler(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	feedHandler := handlers.NewFeedHandler(nil) // 以下用例均在访问数据库之前返回
+	router.GET("/feeds/users/:id/posts.rss", feedHandler.RSS)
+	router.GET("/feeds/users/:id/posts.atom", feedHandler.Atom)
+	router.GET("/feeds/users/:id/posts.json", feedHandler.JSON)
+	router.GET("/feeds/tags/:tag/posts.rss", feedHandler.RSS)
+	router.GET("/feeds/tags/:tag/posts.atom", feedHandler.Atom)
+	router.GET("/feeds/tags/:tag/posts.json", feedHandler.JSON)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	for _, path := range []string{
+		"/feeds/users/abc/posts.rss",
+		"/feeds/users/abc/posts.atom",
+		"/feeds/users/abc/posts.json",
+	} {
+		t.Run("author feed fails with invalid user ID "+path, func(t *testing.T) {
+			w := client.Get(path)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response map[string]interface{}
+			err := helpers.ParseJSON(w, &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "Invalid user ID", response["error"])
+		})
+	}
+
+	longTag := strings.Repeat("a", 51)
+	for _, path := range []string{
+		"/feeds/tags/%20/posts.rss",
+		"/feeds/tags/" + longTag + "/posts.atom",
+		"/feeds/tags/" + longTag + "/posts.json",
+	} {
+		t.Run("tag feed fails with invalid tag "+path, func(t *testing.T) {
+			w := client.Get(path)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response map[string]interface{}
+			err := helpers.ParseJSON(w, &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "Invalid tag", response["error"])
+		})
+	}
+}
\ No newline at end of file
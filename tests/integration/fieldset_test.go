@@ -0,0 +1,117 @@
+package integration
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/internal/i18n"
+	"github.com/demo/demo-gin/internal/listquery"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldset(t *testing.T) {
+	fields := []string{"id", "title", "content", "tags"}
+	relations := []string{"author", "tags"}
+
+	parse := func(t *testing.T, query string) (listquery.Fieldset, error) {
+		values, err := url.ParseQuery(query)
+		require.NoError(t, err)
+		return listquery.ParseFieldset(values, fields, relations)
+	}
+
+	t.Run("selects every field by default", func(t *testing.T) {
+		fieldset, err := parse(t, "")
+		require.NoError(t, err)
+
+		item := map[string]interface{}{"id": 1, "title": "Hello", "content": "Body"}
+		fieldset.Select(item)
+		assert.Len(t, item, 3)
+		assert.True(t, fieldset.Wants("content"))
+		assert.False(t, fieldset.Includes("author"))
+	})
+
+	t.Run("keeps requested fields, id and included relations", func(t *testing.T) {
+		fieldset, err := parse(t, "fields=title&include=author")
+		require.NoError(t, err)
+
+		item := map[string]interface{}{"id": 1, "title": "Hello", "content": "Body", "author": nil}
+		fieldset.Select(item)
+		assert.Equal(t, map[string]interface{}{"id": 1, "title": "Hello", "author": nil}, item)
+		assert.False(t, fieldset.Wants("content"))
+		assert.True(t, fieldset.Wants("author"))
+		assert.True(t, fieldset.Includes("author"))
+	})
+
+	t.Run("reports unknown fields and relations", func(t *testing.T) {
+		_, err := parse(t, "fields=title,password_hash&include=comments")
+
+		var errs listquery.Errors
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs, 2)
+		assert.Equal(t, "fields", errs[0].Parameter)
+		assert.Equal(t, "include", errs[1].Parameter)
+	})
+}
+
+func TestResponseFieldsets(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	postHandler := handlers.NewPostHandler(nil, nil, i18n.Locales{}, nil) // 以下用例均在访问数据库之前返回
+	userHandler := handlers.NewUserHandler(nil, nil)
+	router.GET("/posts", postHandler.List)
+	router.GET("/posts/:id", postHandler.Get)
+	router.GET("/posts/by-slug/:slug", postHandler.GetBySlug)
+	router.GET("/users", userHandler.List)
+	router.GET("/users/:id", userHandler.Get)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("post list fails with unknown field", func(t *testing.T) {
+		w := client.Get("/posts?fields=id,title,content_html")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response struct {
+			Error   string            `json:"error"`
+			Details []listquery.Error `json:"details"`
+		}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid fields or include", response.Error)
+		require.Len(t, response.Details, 1)
+		assert.Equal(t, "fields", response.Details[0].Parameter)
+	})
+
+	t.Run("post fails with unknown relation", func(t *testing.T) {
+		w := client.Get("/posts/1?include=author,comments")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("post by slug fails with unknown relation", func(t *testing.T) {
+		w := client.Get("/posts/by-slug/hello?include=series")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("user list fails with password field", func(t *testing.T) {
+		w := client.Get("/users?fields=username,password_hash")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("user fails with post relation", func(t *testing.T) {
+		w := client.Get("/users/1?include=tags")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
\ No newline at end of file
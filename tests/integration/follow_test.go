@@ -0,0 +1,116 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/internal/middleware"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFollowHandler(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	followHandler := handlers.NewFollowHandler(nil, nil) // 以下用例均在访问数据库之前返回
+	router.PUT("/users/:id/follow", followHandler.Follow)
+	router.DELETE("/users/:id/follow", followHandler.Unfollow)
+	router.GET("/users/:id/followers", followHandler.Followers)
+	router.GET("/users/:id/following", followHandler.Following)
+	router.GET("/feed/home", followHandler.Home)
+
+	// 需要认证的路由，当前中间件固定设置userID为1
+	protected := router.Group("/api")
+	protected.Use(middleware.Auth())
+	protected.PUT("/users/:id/follow", followHandler.Follow)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("follow fails with invalid user ID", func(t *testing.T) {
+		w := client.Put("/users/abc/follow", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid user ID", response["error"])
+	})
+
+	t.Run("follow requires authentication", func(t *testing.T) {
+		w := client.Put("/users/2/follow", nil)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("unfollow requires authentication", func(t *testing.T) {
+		w := client.Delete("/users/2/follow")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("following yourself fails", func(t *testing.T) {
+		client.SetAuth("valid_token")
+		defer client.SetAuth("")
+
+		w := client.Put("/api/users/1/follow", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "You cannot follow yourself", response["error"])
+	})
+
+	for _, path := range []string{"/users/abc/followers", "/users/abc/following"} {
+		t.Run("list fails with invalid user ID "+path, func(t *testing.T) {
+			w := client.Get(path)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response map[string]interface{}
+			err := helpers.ParseJSON(w, &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "Invalid user ID", response["error"])
+		})
+	}
+
+	t.Run("home feed requires authentication", func(t *testing.T) {
+		w := client.Get("/feed/home")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("home feed fails with invalid cursor", func(t *testing.T) {
+		w := client.Get("/feed/home?cursor=not-a-cursor")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid cursor", response["error"])
+	})
+}
\ No newline at end of file
@@ -0,0 +1,162 @@
+package integration
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/internal/i18n"
+	"github.com/demo/demo-gin/internal/listquery"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListQuery(t *testing.T) {
+	schema := &listquery.Schema{
+		Fields: map[string]listquery.Field{
+			"status":     {Column: "p.status", Values: []string{"draft", "published"}},
+			"title":      {Column: "p.title", Sortable: true},
+			"user_id":    {Column: "p.user_id", Type: listquery.Integer},
+			"closed":     {Column: "p.comments_closed", Type: listquery.Boolean},
+			"created_at": {Column: "p.created_at", Type: listquery.Time, Sortable: true},
+		},
+		DefaultSort: "-created_at",
+		Tiebreaker:  "p.id",
+	}
+
+	parse := func(t *testing.T, query string) (listquery.Query, error) {
+		values, err := url.ParseQuery(query)
+		require.NoError(t, err)
+		return schema.Parse(values)
+	}
+
+	t.Run("compiles filters to parameterized SQL", func(t *testing.T) {
+		query, err := parse(t, "filter[status]=draft&filter[created_at][gte]=2024-01-01&filter[user_id][in]=1,2&filter[title][contains]=50%25_off&page=2")
+		require.NoError(t, err)
+
+		where, args := query.Where([]interface{}{"viewer"})
+		assert.Equal(t, " AND p.created_at >= $2 AND p.status = $3 AND p.title ILIKE $4 AND p.user_id IN ($5, $6)", where)
+		assert.Equal(t, []interface{}{
+			"viewer",
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			"draft",
+			`%50\%\_off%`,
+			int64(1),
+			int64(2),
+		}, args)
+	})
+
+	t.Run("never puts values into the SQL", func(t *testing.T) {
+		query, err := parse(t, "filter[title]='%3B DROP TABLE posts%3B --")
+		require.NoError(t, err)
+
+		where, args := query.Where(nil)
+		assert.Equal(t, " AND p.title = $1", where)
+		assert.Equal(t, []interface{}{"'; DROP TABLE posts; --"}, args)
+	})
+
+	t.Run("compiles sort keys with a tiebreaker", func(t *testing.T) {
+		query, err := parse(t, "sort=-created_at,title")
+		require.NoError(t, err)
+		assert.Equal(t, "p.created_at DESC NULLS LAST, p.title ASC NULLS LAST, p.id ASC", query.OrderBy())
+
+		query, err = parse(t, "")
+		require.NoError(t, err)
+		assert.Equal(t, "p.created_at DESC NULLS LAST, p.id DESC", query.OrderBy())
+	})
+
+	t.Run("reports every invalid parameter", func(t *testing.T) {
+		_, err := parse(t, "filter[password]=x&filter[status][gt]=draft&filter[status]=deleted&filter[user_id]=me&filter[closed]=maybe&filter[created_at][lt]=yesterday&filter=1&sort=user_id,-title,title")
+
+		var errs listquery.Errors
+		require.ErrorAs(t, err, &errs)
+
+		parameters := make([]string, 0, len(errs))
+		for _, e := range errs {
+			parameters = append(parameters, e.Parameter)
+			assert.NotEmpty(t, e.Message)
+		}
+		assert.ElementsMatch(t, []string{
+			"filter",
+			"filter[closed]",
+			"filter[created_at][lt]",
+			"filter[password]",
+			"filter[status]",
+			"filter[status][gt]",
+			"filter[user_id]",
+			"sort",
+			"sort",
+		}, parameters)
+	})
+
+	t.Run("rejects repeated parameters", func(t *testing.T) {
+		_, err := parse(t, "filter[status]=draft&filter[status]=published")
+		assert.Error(t, err)
+	})
+}
+
+func TestListFilters(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	postHandler := handlers.NewPostHandler(nil, nil, i18n.Locales{}, nil) // 以下用例均在访问数据库之前返回
+	userHandler := handlers.NewUserHandler(nil, nil)
+	router.GET("/posts", postHandler.List)
+	router.GET("/users", userHandler.List)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("post list fails with unknown filter field", func(t *testing.T) {
+		w := client.Get("/posts?filter[content]=secret")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response struct {
+			Error   string            `json:"error"`
+			Details []listquery.Error `json:"details"`
+		}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid filter or sort", response.Error)
+		require.Len(t, response.Details, 1)
+		assert.Equal(t, "filter[content]", response.Details[0].Parameter)
+	})
+
+	t.Run("post list fails with invalid status", func(t *testing.T) {
+		w := client.Get("/posts?filter[status]=deleted")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("post list fails with unsortable field", func(t *testing.T) {
+		w := client.Get("/posts?sort=-status")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("user list fails with invalid date", func(t *testing.T) {
+		w := client.Get("/users?filter[created_at][gte]=01/02/2024")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("user list fails with filter on password", func(t *testing.T) {
+		w := client.Get("/users?filter[password_hash][contains]=a&sort=email")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response struct {
+			Details []listquery.Error `json:"details"`
+		}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Len(t, response.Details, 2)
+	})
+}
\ No newline at end of file
@@ -0,0 +1,87 @@
+package integration
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/media"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodePNG returns a solid PNG image of the given size.
+func encodePNG(t *testing.T, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: 200, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestMedia(t *testing.T) {
+	t.Run("detect uses the file contents", func(t *testing.T) {
+		contentType, ext, ok := media.Detect(encodePNG(t, 2, 2))
+		assert.True(t, ok)
+		assert.Equal(t, "image/png", contentType)
+		assert.Equal(t, ".png", ext)
+
+		contentType, ext, ok = media.Detect([]byte("%PDF-1.7\n"))
+		assert.True(t, ok)
+		assert.Equal(t, "application/pdf", contentType)
+		assert.Equal(t, ".pdf", ext)
+	})
+
+	t.Run("detect rejects other types", func(t *testing.T) {
+		_, _, ok := media.Detect([]byte("<html><script>alert(1)</script></html>"))
+		assert.False(t, ok)
+
+		_, _, ok = media.Detect([]byte("plain text"))
+		assert.False(t, ok)
+	})
+
+	t.Run("thumbnail fits within the size", func(t *testing.T) {
+		thumb, width, height, err := media.Thumbnail(bytes.NewReader(encodePNG(t, 800, 400)), 320)
+		require.NoError(t, err)
+		assert.Equal(t, 800, width)
+		assert.Equal(t, 400, height)
+
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(thumb))
+		require.NoError(t, err)
+		assert.Equal(t, 320, cfg.Width)
+		assert.Equal(t, 160, cfg.Height)
+	})
+
+	t.Run("small images are not enlarged", func(t *testing.T) {
+		thumb, _, _, err := media.Thumbnail(bytes.NewReader(encodePNG(t, 40, 60)), 320)
+		require.NoError(t, err)
+
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(thumb))
+		require.NoError(t, err)
+		assert.Equal(t, 40, cfg.Width)
+		assert.Equal(t, 60, cfg.Height)
+	})
+
+	t.Run("avatar is cropped to a square", func(t *testing.T) {
+		avatar, err := media.Avatar(bytes.NewReader(encodePNG(t, 300, 100)), 64)
+		require.NoError(t, err)
+
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(avatar))
+		require.NoError(t, err)
+		assert.Equal(t, 64, cfg.Width)
+		assert.Equal(t, 64, cfg.Height)
+	})
+
+	t.Run("invalid image fails", func(t *testing.T) {
+		_, _, _, err := media.Thumbnail(bytes.NewReader([]byte("\x89PNG\r\n\x1a\ngarbage")), 320)
+		assert.Error(t, err)
+	})
+}
\ No newline at end of file
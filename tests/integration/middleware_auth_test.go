@@ -186,4 +186,57 @@ func TestJWTAuthMiddleware(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.True(t, requestProcessed, "Request should have been processed by the handler")
 	})
+}
+
+func TestOptionalAuthMiddleware(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由，公开接口使用可选认证
+	router := gin.New()
+	router.Use(middleware.OptionalAuth())
+	router.GET("/posts", func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		c.JSON(http.StatusOK, gin.H{
+			"user_id_exists": exists,
+			"user_id":        userID,
+		})
+	})
+
+	client := helpers.NewTestClient(router)
+
+	t.Run("anonymous request passes without userID", func(t *testing.T) {
+		client.SetAuth("")
+
+		w := client.Get("/posts")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, false, response["user_id_exists"])
+	})
+
+	t.Run("bearer token sets userID", func(t *testing.T) {
+		client.SetAuth("valid_token")
+
+		w := client.Get("/posts")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, true, response["user_id_exists"])
+		assert.Equal(t, float64(1), response["user_id"]) // 当前实现固定设置为1
+	})
+
+	t.Run("malformed header is rejected", func(t *testing.T) {
+		client.Token = "InvalidFormat token123"
+
+		w := client.Get("/posts")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
 }
\ No newline at end of file
@@ -0,0 +1,81 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModerationHandler(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	moderationHandler := handlers.NewModerationHandler(nil, nil) // 以下用例均在访问数据库之前返回
+	router.GET("/moderation/reports", moderationHandler.List)
+	router.POST("/moderation/reports/:id/claim", moderationHandler.Claim)
+	router.POST("/moderation/reports/:id/resolve", moderationHandler.Resolve)
+	router.POST("/moderation/reports/:id/dismiss", moderationHandler.Dismiss)
+	router.GET("/moderation/log", moderationHandler.Log)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("list fails with invalid status", func(t *testing.T) {
+		w := client.Get("/moderation/reports?status=pending")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid status, must be open, claimed, resolved or dismissed", response["error"])
+	})
+
+	for _, path := range []string{
+		"/moderation/reports/abc/claim",
+		"/moderation/reports/abc/resolve",
+		"/moderation/reports/abc/dismiss",
+	} {
+		t.Run("fails with invalid report ID "+path, func(t *testing.T) {
+			w := client.Post(path, map[string]interface{}{"action": "none"})
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response map[string]interface{}
+			err := helpers.ParseJSON(w, &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "Invalid report ID", response["error"])
+		})
+	}
+
+	t.Run("resolve fails with unknown action", func(t *testing.T) {
+		w := client.Post("/moderation/reports/1/resolve", map[string]interface{}{"action": "ban_forever"})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	for _, path := range []string{"/moderation/reports", "/moderation/log"} {
+		t.Run("requires authentication "+path, func(t *testing.T) {
+			w := client.Get(path)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+			var response map[string]interface{}
+			err := helpers.ParseJSON(w, &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "User not authenticated", response["error"])
+		})
+	}
+
+	t.Run("dismiss without a body requires authentication", func(t *testing.T) {
+		w := client.Post("/moderation/reports/1/dismiss", nil)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
\ No newline at end of file
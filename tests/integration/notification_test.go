@@ -0,0 +1,151 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/demo/demo-gin/internal/config"
+	"github.com/demo/demo-gin/internal/events"
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/internal/mailer"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBus(t *testing.T) {
+	t.Run("calls every hook in order", func(t *testing.T) {
+		bus := events.NewBus()
+		var calls []string
+		bus.Subscribe(func(ctx context.Context, event events.Event) error {
+			calls = append(calls, "first:"+event.Type)
+			return errors.New("failed")
+		})
+		bus.Subscribe(func(ctx context.Context, event events.Event) error {
+			calls = append(calls, "second:"+event.Type)
+			assert.False(t, event.At.IsZero())
+			return nil
+		})
+
+		bus.Emit(context.Background(), events.Event{Type: events.PostLiked, ActorID: 1, UserID: 2, PostID: 3})
+
+		assert.Equal(t, []string{"first:post.liked", "second:post.liked"}, calls)
+	})
+
+	t.Run("nil bus drops events", func(t *testing.T) {
+		var bus *events.Bus
+		assert.NotPanics(t, func() {
+			bus.Emit(context.Background(), events.Event{Type: events.UserFollowed})
+		})
+	})
+}
+
+func TestMailer(t *testing.T) {
+	t.Run("composes a plain text message", func(t *testing.T) {
+		date := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		msg := string(mailer.Compose("no-reply@example.com", mailer.Message{
+			To:      "alice@example.com",
+			Subject: "Your daily notification digest\r\nBcc: eve@example.com",
+			Text:    "Hi alice,\n\nbob liked \"Hello\"\n",
+		}, date))
+
+		assert.Contains(t, msg, "From: no-reply@example.com\r\n")
+		assert.Contains(t, msg, "To: alice@example.com\r\n")
+		assert.Contains(t, msg, "Date: Tue, 02 Jan 2024 03:04:05 +0000\r\n")
+		assert.NotContains(t, msg, "\r\nBcc:")
+		assert.True(t, strings.HasSuffix(msg, "\r\n\r\nHi alice,\r\n\r\nbob liked \"Hello\"\r\n"))
+	})
+
+	t.Run("selects the driver from the configuration", func(t *testing.T) {
+		m, err := mailer.New(config.MailConfig{Driver: "log"})
+		require.NoError(t, err)
+		assert.IsType(t, &mailer.Log{}, m)
+
+		m, err = mailer.New(config.MailConfig{Driver: "smtp", SMTPHost: "localhost", SMTPPort: 587, From: "no-reply@example.com"})
+		require.NoError(t, err)
+		assert.IsType(t, &mailer.SMTP{}, m)
+	})
+
+	t.Run("rejects incomplete or unknown configurations", func(t *testing.T) {
+		_, err := mailer.New(config.MailConfig{Driver: "smtp"})
+		assert.Error(t, err)
+
+		_, err = mailer.New(config.MailConfig{Driver: "carrier-pigeon"})
+		assert.Error(t, err)
+	})
+}
+
+func TestNotifications(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	notificationHandler := handlers.NewNotificationHandler(nil) // 以下用例均在访问数据库之前返回
+	router.GET("/notifications", notificationHandler.List)
+	router.POST("/notifications/read-all", notificationHandler.MarkAllRead)
+	router.POST("/notifications/:id/read", notificationHandler.MarkRead)
+	router.GET("/users/me/notification-preferences", notificationHandler.GetPreferences)
+	router.PUT("/users/me/notification-preferences", notificationHandler.UpdatePreferences)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("list fails with invalid unread filter", func(t *testing.T) {
+		w := client.Get("/notifications?unread=maybe")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("list requires authentication", func(t *testing.T) {
+		w := client.Get("/notifications?unread=true")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("mark read fails with invalid notification ID", func(t *testing.T) {
+		w := client.Post("/notifications/abc/read", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("mark read requires authentication", func(t *testing.T) {
+		w := client.Post("/notifications/1/read", nil)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("mark all read requires authentication", func(t *testing.T) {
+		w := client.Post("/notifications/read-all", nil)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("get preferences requires authentication", func(t *testing.T) {
+		w := client.Get("/users/me/notification-preferences")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("update preferences fails with unknown digest frequency", func(t *testing.T) {
+		w := client.Put("/users/me/notification-preferences", map[string]interface{}{"email_digest": "hourly"})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("update preferences requires authentication", func(t *testing.T) {
+		w := client.Put("/users/me/notification-preferences", map[string]interface{}{"likes": false, "email_digest": "weekly"})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
\ No newline at end of file
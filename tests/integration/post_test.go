@@ -0,0 +1,205 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/internal/i18n"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostHandler(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	postHandler := handlers.NewPostHandler(nil, nil, i18n.Locales{}, nil) // 以下用例均在访问数据库之前返回
+	router.GET("/posts/:id", postHandler.Get)
+	router.GET("/posts/by-slug/:slug", postHandler.GetBySlug)
+	router.POST("/posts", postHandler.Create)
+	router.PUT("/posts/:id", postHandler.Update)
+	router.PATCH("/posts/:id", postHandler.Patch)
+	router.DELETE("/posts/:id", postHandler.Delete)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("get fails with invalid post ID", func(t *testing.T) {
+		w := client.Get("/posts/abc")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid post ID", response["error"])
+	})
+
+	t.Run("get fails with unknown render mode", func(t *testing.T) {
+		w := client.Get("/posts/1?render=pdf")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid render, must be html or raw", response["error"])
+	})
+
+	t.Run("get by slug fails with unknown render mode", func(t *testing.T) {
+		w := client.Get("/posts/by-slug/hello-world?render=pdf")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid render, must be html or raw", response["error"])
+	})
+
+	t.Run("create fails with unknown content format", func(t *testing.T) {
+		w := client.Post("/posts", map[string]interface{}{
+			"title":          "Hello",
+			"content":        "# Hello",
+			"content_format": "html",
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Contains(t, response, "error")
+	})
+
+	t.Run("create requires authentication", func(t *testing.T) {
+		w := client.Post("/posts", map[string]interface{}{
+			"title":          "Hello",
+			"content":        "# Hello",
+			"content_format": "markdown",
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("update fails with invalid post ID", func(t *testing.T) {
+		w := client.Put("/posts/abc", map[string]interface{}{
+			"title": "Edited",
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid post ID", response["error"])
+	})
+
+	t.Run("update fails with unknown status", func(t *testing.T) {
+		w := client.Put("/posts/1", map[string]interface{}{
+			"status": "deleted",
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Contains(t, response, "error")
+	})
+
+	t.Run("update fails with missing status", func(t *testing.T) {
+		w := client.Put("/posts/1", map[string]interface{}{
+			"title": "Edited",
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Contains(t, response, "error")
+	})
+
+	t.Run("update requires authentication", func(t *testing.T) {
+		w := client.Put("/posts/1", map[string]interface{}{
+			"title":  "Edited",
+			"status": "draft",
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("patch fails with invalid post ID", func(t *testing.T) {
+		w := client.Patch("/posts/abc", map[string]interface{}{
+			"content": nil,
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid post ID", response["error"])
+	})
+
+	t.Run("patch fails when body is not an object", func(t *testing.T) {
+		w := client.Patch("/posts/1", []interface{}{"title"})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "patch must be a JSON object", response["error"])
+	})
+
+	t.Run("patch requires authentication", func(t *testing.T) {
+		w := client.Patch("/posts/1", map[string]interface{}{
+			"content": nil,
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("delete fails with invalid post ID", func(t *testing.T) {
+		w := client.Delete("/posts/abc")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid post ID", response["error"])
+	})
+
+	t.Run("delete requires authentication", func(t *testing.T) {
+		w := client.Delete("/posts/1")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+}
\ No newline at end of file
@@ -0,0 +1,188 @@
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/internal/storage"
+	"github.com/demo/demo-gin/internal/worker"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivacy(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	privacyHandler := handlers.NewPrivacyHandler(nil, nil, 30*24*time.Hour) // 以下用例均在访问数据库之前返回
+	router.POST("/users/me/export", privacyHandler.RequestExport)
+	router.GET("/users/me/exports/:id", privacyHandler.GetExport)
+	router.GET("/exports/:token", privacyHandler.Download)
+	router.POST("/users/me/erasure", privacyHandler.RequestErasure)
+	router.DELETE("/users/me/erasure", privacyHandler.CancelErasure)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("request export requires authentication", func(t *testing.T) {
+		w := client.Post("/users/me/export", nil)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("get export fails with invalid ID", func(t *testing.T) {
+		w := client.Get("/users/me/exports/abc")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid export ID", response["error"])
+	})
+
+	t.Run("get export requires authentication", func(t *testing.T) {
+		w := client.Get("/users/me/exports/1")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	for _, token := range []string{"abc", strings.Repeat("z", 64), strings.Repeat("a", 63)} {
+		t.Run("download rejects malformed token "+token, func(t *testing.T) {
+			w := client.Get("/exports/" + token)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+
+			var response map[string]interface{}
+			err := helpers.ParseJSON(w, &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "Export not found", response["error"])
+		})
+	}
+
+	t.Run("request erasure requires authentication", func(t *testing.T) {
+		w := client.Post("/users/me/erasure", nil)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("cancel erasure requires authentication", func(t *testing.T) {
+		w := client.Delete("/users/me/erasure")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestDataExportArchive(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewLocal(t.TempDir())
+	require.NoError(t, store.Put(ctx, "attachments/a.png", strings.NewReader("png"), 3, "image/png"))
+
+	user := db.User{ID: 1, Email: "jane@example.com", Username: "jane"}
+	var out bytes.Buffer
+	err := worker.WriteArchive(ctx, &out, store, worker.Archive{
+		User:     user,
+		Posts:    []db.Post{{ID: 2, UserID: 1, Title: "Hello", Slug: "hello"}},
+		Comments: []db.Comment{{ID: 3, PostID: 2, UserID: 1, Content: "First"}},
+		Attachments: []db.Attachment{{
+			ID:          4,
+			PostID:      sql.NullInt32{Int32: 2, Valid: true},
+			Filename:    "../../a.png",
+			ContentType: "image/png",
+			SizeBytes:   3,
+			StorageKey:  "attachments/a.png",
+		}},
+	})
+	require.NoError(t, err)
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		r.Close()
+		files[f.Name] = string(content)
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{
+		"profile.json", "posts.json", "comments.json", "attachments.json", "sessions.json",
+		"attachments/4_a.png",
+	}, names)
+
+	assert.Contains(t, files["profile.json"], `"email": "jane@example.com"`)
+	assert.Contains(t, files["posts.json"], `"title": "Hello"`)
+	assert.Contains(t, files["comments.json"], `"content": "First"`)
+	assert.Contains(t, files["attachments.json"], `"file": "attachments/4_a.png"`)
+	assert.JSONEq(t, `[]`, files["sessions.json"])
+	assert.Equal(t, "png", files["attachments/4_a.png"])
+}
+
+func TestAccountErasureAnonymize(t *testing.T) {
+	testDB, err := helpers.SetupTestDB()
+	if err != nil {
+		t.Skipf("test database unavailable: %v", err)
+	}
+	defer testDB.Close()
+
+	ctx := context.Background()
+	suffix := strconv.FormatInt(time.Now().UnixNano(), 36)
+
+	var userID, postID int32
+	require.NoError(t, testDB.QueryRow(`INSERT INTO users (email, username, password_hash, erasure_scheduled_at)
+		VALUES ($1, $2, 'x', CURRENT_TIMESTAMP - INTERVAL '1 day') RETURNING id`,
+		"erase-"+suffix+"@example.com", "erase-"+suffix).Scan(&userID))
+	require.NoError(t, testDB.QueryRow(`INSERT INTO posts (user_id, title, slug, locale)
+		VALUES ($1, 'Kept', $2, 'en') RETURNING id`, userID, "kept-"+suffix).Scan(&postID))
+	_, err = testDB.Exec(`INSERT INTO post_drafts (post_id, user_id, base_version) VALUES ($1, $2, 1)`, postID, userID)
+	require.NoError(t, err)
+	_, err = testDB.Exec(`INSERT INTO notification_preferences (user_id, email_digest) VALUES ($1, 'daily')`, userID)
+	require.NoError(t, err)
+	_, err = testDB.Exec(`INSERT INTO webhooks (user_id, url, event_types, secret)
+		VALUES ($1, 'https://example.com/hook', '{post.published}', 's')`, userID)
+	require.NoError(t, err)
+
+	_, err = worker.NewAccountErasure(testDB.DB, storage.NewLocal(t.TempDir()), true, time.Hour).RunOnce(ctx)
+	require.NoError(t, err)
+
+	for _, table := range []string{"post_drafts", "notification_preferences", "webhooks"} {
+		var count int
+		require.NoError(t, testDB.QueryRow("SELECT COUNT(*) FROM "+table+" WHERE user_id = $1", userID).Scan(&count))
+		assert.Zero(t, count, table)
+	}
+
+	var posts int
+	require.NoError(t, testDB.QueryRow(`SELECT COUNT(*) FROM posts WHERE id = $1`, postID).Scan(&posts))
+	assert.Equal(t, 1, posts, "anonymized users keep their posts")
+}
\ No newline at end of file
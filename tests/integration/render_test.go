@@ -0,0 +1,50 @@
+package integration
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/render"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	t.Run("markdown is rendered to HTML", func(t *testing.T) {
+		html, err := render.HTML(render.FormatMarkdown, "# Title\n\nSome **bold** text")
+		assert.NoError(t, err)
+
+		assert.Contains(t, html, "<h1")
+		assert.Contains(t, html, "<strong>bold</strong>")
+	})
+
+	t.Run("raw HTML and scripts are stripped from markdown", func(t *testing.T) {
+		html, err := render.HTML(render.FormatMarkdown, "<script>alert(1)</script>\n\n[click](javascript:alert(1))\n\n<img src=x onerror=alert(1)>")
+		assert.NoError(t, err)
+
+		assert.NotContains(t, html, "<script")
+		assert.NotContains(t, html, "javascript:")
+		assert.NotContains(t, html, "onerror")
+	})
+
+	t.Run("plain text is escaped", func(t *testing.T) {
+		html, err := render.HTML(render.FormatPlain, "<b>not bold</b>\n\nsecond paragraph")
+		assert.NoError(t, err)
+
+		assert.Equal(t, "<p>&lt;b&gt;not bold&lt;/b&gt;</p>\n<p>second paragraph</p>\n", html)
+	})
+
+	t.Run("text strips markup", func(t *testing.T) {
+		assert.Equal(t, "Title Fish & chips", render.Text("<h1>Title</h1>\n<p>Fish &amp; chips</p>"))
+	})
+
+	t.Run("excerpt cuts at a word boundary", func(t *testing.T) {
+		assert.Equal(t, "short text", render.Excerpt("short text", 20))
+		assert.Equal(t, "the quick brown…", render.Excerpt("the quick brown fox jumps", 18))
+	})
+
+	t.Run("reading time rounds up", func(t *testing.T) {
+		assert.Equal(t, 0, render.ReadingTime(""))
+		assert.Equal(t, 1, render.ReadingTime("one word"))
+		assert.Equal(t, 2, render.ReadingTime(strings.Repeat("word ", render.WordsPerMinute+1)))
+	})
+}
\ No newline at end of file
@@ -0,0 +1,66 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportHandler(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	reportHandler := handlers.NewReportHandler(nil) // 以下用例均在访问数据库之前返回
+	router.POST("/posts/:id/report", reportHandler.ReportPost)
+	router.POST("/users/:id/report", reportHandler.ReportUser)
+	router.POST("/comments/:id/report", reportHandler.ReportComment)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	for target, path := range map[string]string{
+		"post":    "/posts/abc/report",
+		"user":    "/users/abc/report",
+		"comment": "/comments/abc/report",
+	} {
+		t.Run("fails with invalid "+target+" ID", func(t *testing.T) {
+			w := client.Post(path, map[string]interface{}{"reason": "spam"})
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response map[string]interface{}
+			err := helpers.ParseJSON(w, &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "Invalid "+target+" ID", response["error"])
+		})
+	}
+
+	t.Run("fails without reason", func(t *testing.T) {
+		w := client.Post("/posts/1/report", map[string]interface{}{})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("fails with unknown reason", func(t *testing.T) {
+		w := client.Post("/posts/1/report", map[string]interface{}{"reason": "boring"})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("requires authentication", func(t *testing.T) {
+		w := client.Post("/comments/1/report", map[string]interface{}{"reason": "harassment"})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+}
\ No newline at end of file
@@ -0,0 +1,157 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostAuthors(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	authorHandler := handlers.NewAuthorHandler(nil) // 以下用例均在访问数据库之前返回
+	router.GET("/posts/:id/authors", authorHandler.List)
+	router.PUT("/posts/:id/authors/:user_id", authorHandler.Add)
+	router.DELETE("/posts/:id/authors/:user_id", authorHandler.Remove)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("list fails with invalid post ID", func(t *testing.T) {
+		w := client.Get("/posts/abc/authors")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid post ID", response["error"])
+	})
+
+	t.Run("add fails with invalid user ID", func(t *testing.T) {
+		w := client.Put("/posts/1/authors/abc", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid user ID", response["error"])
+	})
+
+	t.Run("add requires authentication", func(t *testing.T) {
+		w := client.Put("/posts/1/authors/2", nil)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("remove requires authentication", func(t *testing.T) {
+		w := client.Delete("/posts/1/authors/2")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestSeries(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	seriesHandler := handlers.NewSeriesHandler(nil) // 以下用例均在访问数据库之前返回
+	router.GET("/series", seriesHandler.List)
+	router.GET("/series/:id", seriesHandler.Get)
+	router.POST("/series", seriesHandler.Create)
+	router.PUT("/series/:id", seriesHandler.Update)
+	router.DELETE("/series/:id", seriesHandler.Delete)
+	router.PUT("/series/:id/posts", seriesHandler.SetPosts)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("list fails with invalid user ID", func(t *testing.T) {
+		w := client.Get("/series?user_id=abc")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid user ID", response["error"])
+	})
+
+	t.Run("get fails with invalid ID", func(t *testing.T) {
+		w := client.Get("/series/abc")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid series ID", response["error"])
+	})
+
+	t.Run("create requires a title", func(t *testing.T) {
+		w := client.Post("/series", map[string]interface{}{"description": "No title"})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("create requires authentication", func(t *testing.T) {
+		w := client.Post("/series", map[string]interface{}{"title": "Go in depth"})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("update requires authentication", func(t *testing.T) {
+		w := client.Put("/series/1", map[string]interface{}{"title": "Go in depth"})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("delete requires authentication", func(t *testing.T) {
+		w := client.Delete("/series/1")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("set posts requires post_ids", func(t *testing.T) {
+		w := client.Put("/series/1/posts", map[string]interface{}{})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("set posts rejects duplicate posts", func(t *testing.T) {
+		w := client.Put("/series/1/posts", map[string]interface{}{"post_ids": []int{3, 1, 3}})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Duplicate post ID 3", response["error"])
+	})
+
+	t.Run("set posts requires authentication", func(t *testing.T) {
+		w := client.Put("/series/1/posts", map[string]interface{}{"post_ids": []int{1, 2}})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
\ No newline at end of file
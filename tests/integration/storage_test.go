@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/storage"
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStorage runs the behaviour shared by every storage backend.
+func testStorage(t *testing.T, store storage.Storage) {
+	ctx := context.Background()
+
+	t.Run("put and open round-trip", func(t *testing.T) {
+		content := "hello attachment"
+		err := store.Put(ctx, "attachments/hello.txt", strings.NewReader(content), int64(len(content)), "text/plain")
+		require.NoError(t, err)
+
+		r, err := store.Open(ctx, "attachments/hello.txt")
+		require.NoError(t, err)
+		defer r.Close()
+
+		data, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	})
+
+	t.Run("open missing key returns ErrNotFound", func(t *testing.T) {
+		_, err := store.Open(ctx, "attachments/missing.txt")
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("delete removes the object", func(t *testing.T) {
+		err := store.Put(ctx, "attachments/gone.txt", strings.NewReader("x"), 1, "text/plain")
+		require.NoError(t, err)
+
+		assert.NoError(t, store.Delete(ctx, "attachments/gone.txt"))
+
+		_, err = store.Open(ctx, "attachments/gone.txt")
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("deleting a missing key is not an error", func(t *testing.T) {
+		assert.NoError(t, store.Delete(ctx, "attachments/never.txt"))
+	})
+}
+
+func TestLocalStorage(t *testing.T) {
+	testStorage(t, storage.NewLocal(t.TempDir()))
+
+	t.Run("keys cannot escape the root", func(t *testing.T) {
+		store := storage.NewLocal(t.TempDir())
+		err := store.Put(context.Background(), "../outside.txt", strings.NewReader("x"), 1, "text/plain")
+		assert.Error(t, err)
+	})
+}
+
+func TestS3Storage(t *testing.T) {
+	// 启动内存中的S3兼容服务
+	backend := s3mem.New()
+	require.NoError(t, backend.CreateBucket("attachments"))
+	server := httptest.NewServer(gofakes3.New(backend).Server())
+	defer server.Close()
+
+	store, err := storage.NewS3(storage.S3Options{
+		Endpoint:  strings.TrimPrefix(server.URL, "http://"),
+		Region:    "us-east-1",
+		Bucket:    "attachments",
+		AccessKey: "test",
+		SecretKey: "test",
+		UseSSL:    false,
+	})
+	require.NoError(t, err)
+
+	testStorage(t, store)
+}
\ No newline at end of file
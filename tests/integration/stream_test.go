@@ -0,0 +1,142 @@
+package integration
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/internal/middleware"
+	"github.com/demo/demo-gin/internal/realtime"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func TestHub(t *testing.T) {
+	t.Run("delivers public messages to everyone and private ones to their user", func(t *testing.T) {
+		hub := realtime.NewHub()
+		alice := hub.Subscribe(1)
+		defer alice.Close()
+		bob := hub.Subscribe(2)
+		defer bob.Close()
+
+		hub.Broadcast(realtime.Message{ID: 1, Type: "post.published", Data: json.RawMessage(`{}`)})
+		hub.Broadcast(realtime.Message{ID: 2, Type: realtime.NotificationCreated, UserID: 2, Data: json.RawMessage(`{}`)})
+
+		assert.Equal(t, int64(1), (<-alice.C).ID)
+		assert.Equal(t, int64(1), (<-bob.C).ID)
+		assert.Equal(t, int64(2), (<-bob.C).ID)
+		assert.Empty(t, alice.C)
+	})
+
+	t.Run("drops subscribers that fall behind", func(t *testing.T) {
+		hub := realtime.NewHub()
+		sub := hub.Subscribe(1)
+
+		for i := int64(1); i <= 100; i++ {
+			hub.Broadcast(realtime.Message{ID: i, Type: "post.updated"})
+		}
+
+		received := 0
+		for range sub.C {
+			received++
+		}
+		assert.Less(t, received, 100)
+		assert.NotPanics(t, sub.Close)
+	})
+}
+
+func TestStream(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	hub := realtime.NewHub()
+
+	// 创建测试路由
+	router := gin.New()
+	streamHandler := handlers.NewStreamHandler(nil, hub, 50*time.Millisecond) // 以下用例均在访问数据库之前返回
+	router.GET("/events", streamHandler.Events)
+	protected := router.Group("/api")
+	protected.Use(middleware.Auth())
+	protected.GET("/events", streamHandler.Events)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("fails with invalid Last-Event-ID", func(t *testing.T) {
+		w := client.Get("/events?last_event_id=abc")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("requires authentication", func(t *testing.T) {
+		w := client.Get("/events")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	// broadcast sends msg once the stream has had time to subscribe.
+	broadcast := func(msg realtime.Message) {
+		time.Sleep(20 * time.Millisecond)
+		hub.Broadcast(msg)
+	}
+
+	t.Run("pushes server-sent events with heartbeats", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/api/events", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer token")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		go broadcast(realtime.Message{ID: 7, Type: "post.published", Data: json.RawMessage(`{"id":3}`)})
+
+		var lines []string
+		received, heartbeat := false, false
+		scanner := bufio.NewScanner(resp.Body)
+		for !(received && heartbeat) && scanner.Scan() {
+			lines = append(lines, scanner.Text())
+			received = received || strings.HasPrefix(scanner.Text(), "data: ")
+			heartbeat = heartbeat || scanner.Text() == ": heartbeat"
+		}
+
+		stream := strings.Join(lines, "\n")
+		assert.Contains(t, stream, "retry: 3000\n")
+		assert.Contains(t, stream, "id: 7\nevent: post.published\ndata: {\"id\":3}\n")
+	})
+
+	t.Run("pushes JSON messages over a WebSocket", func(t *testing.T) {
+		config, err := websocket.NewConfig(strings.Replace(server.URL, "http", "ws", 1)+"/api/events", server.URL)
+		require.NoError(t, err)
+		config.Header.Set("Authorization", "Bearer token")
+
+		ws, err := websocket.DialConfig(config)
+		require.NoError(t, err)
+		defer ws.Close()
+
+		go broadcast(realtime.Message{ID: 8, Type: realtime.NotificationCreated, UserID: 1, Data: json.RawMessage(`{"type":"like"}`)})
+
+		var msg map[string]interface{}
+		require.NoError(t, websocket.JSON.Receive(ws, &msg))
+		assert.Equal(t, "8", msg["id"])
+		assert.Equal(t, realtime.NotificationCreated, msg["event"])
+		assert.Equal(t, map[string]interface{}{"type": "like"}, msg["data"])
+
+		require.NoError(t, websocket.JSON.Receive(ws, &msg))
+		assert.Equal(t, "heartbeat", msg["event"])
+	})
+}
\ No newline at end of file
@@ -0,0 +1,245 @@
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/internal/i18n"
+	"github.com/demo/demo-gin/internal/transfer"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWXR = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"
+	xmlns:content="http://purl.org/rss/1.0/modules/content/"
+	xmlns:excerpt="http://wordpress.org/export/1.2/excerpt/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/"
+	xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+	<language>en-US</language>
+	<wp:author><wp:author_login>alice</wp:author_login><wp:author_email>alice@example.com</wp:author_email></wp:author>
+	<item>
+		<title>Hello World</title>
+		<guid isPermaLink="false">https://blog.example.com/?p=1</guid>
+		<dc:creator>alice</dc:creator>
+		<content:encoded><![CDATA[<p>Some <strong>bold</strong> text.</p><ul><li>one</li><li>two</li></ul>]]></content:encoded>
+		<excerpt:encoded><![CDATA[Excerpt]]></excerpt:encoded>
+		<wp:post_id>1</wp:post_id>
+		<wp:post_date>2024-03-01 10:00:00</wp:post_date>
+		<wp:post_date_gmt>2024-03-01 09:00:00</wp:post_date_gmt>
+		<wp:post_name>hello-world</wp:post_name>
+		<wp:status>publish</wp:status>
+		<wp:post_type>post</wp:post_type>
+		<category domain="category" nicename="news"><![CDATA[News]]></category>
+		<category domain="post_tag" nicename="go"><![CDATA[Go]]></category>
+	</item>
+	<item>
+		<title>Work in progress</title>
+		<guid isPermaLink="false"></guid>
+		<dc:creator>bob</dc:creator>
+		<content:encoded><![CDATA[Plain text]]></content:encoded>
+		<wp:post_id>2</wp:post_id>
+		<wp:post_date_gmt>0000-00-00 00:00:00</wp:post_date_gmt>
+		<wp:status>draft</wp:status>
+		<wp:post_type>post</wp:post_type>
+	</item>
+	<item>
+		<title>About</title>
+		<wp:post_id>3</wp:post_id>
+		<wp:status>publish</wp:status>
+		<wp:post_type>page</wp:post_type>
+	</item>
+	<item>
+		<title>Deleted</title>
+		<wp:post_id>4</wp:post_id>
+		<wp:status>trash</wp:status>
+		<wp:post_type>post</wp:post_type>
+	</item>
+</channel>
+</rss>`
+
+func TestMarkdownTransfer(t *testing.T) {
+	t.Run("parses front matter", func(t *testing.T) {
+		document, err := transfer.ParseMarkdown("posts/hello.md", []byte("---\n"+
+			"title: Hello\n"+
+			"date: 2024-03-01 10:00\n"+
+			"draft: true\n"+
+			"tags: [go, web]\n"+
+			"lang: en\n"+
+			"---\n\n# Hello\n"))
+		require.NoError(t, err)
+
+		assert.Equal(t, "posts/hello.md", document.SourceID)
+		assert.Equal(t, "Hello", document.Title)
+		assert.Equal(t, "# Hello", document.Content)
+		assert.Equal(t, "draft", document.Status)
+		assert.Equal(t, "en", document.Locale)
+		assert.Equal(t, []string{"go", "web"}, document.Tags)
+		assert.Equal(t, time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), document.Date)
+	})
+
+	t.Run("fails without front matter or title", func(t *testing.T) {
+		_, err := transfer.ParseMarkdown("a.md", []byte("# Hello\n"))
+		assert.Error(t, err)
+
+		_, err = transfer.ParseMarkdown("b.md", []byte("---\nslug: b\n---\nbody\n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("round-trips through an archive", func(t *testing.T) {
+		documents := []transfer.Document{
+			{
+				SourceID:      "post-1",
+				Title:         "Hello: World",
+				Slug:          "hello",
+				Content:       "Some *text*.",
+				ContentFormat: "markdown",
+				Status:        "published",
+				Locale:        "en",
+				Tags:          []string{"go"},
+				Author:        "alice",
+				Date:          time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+			},
+			{SourceID: "post-2", Title: "Second", Slug: "hello", Content: "Body", ContentFormat: "markdown", Status: "draft"},
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, transfer.WriteMarkdownArchive(&buf, documents))
+
+		archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		require.Len(t, archive.File, 2)
+		assert.Equal(t, "hello.md", archive.File[0].Name)
+		assert.Equal(t, "hello-2.md", archive.File[1].Name)
+
+		parsed, err := transfer.ParseMarkdownArchive(buf.Bytes())
+		require.NoError(t, err)
+		require.Len(t, parsed, 2)
+		assert.Equal(t, documents[0], parsed[0])
+		assert.Equal(t, "post-2", parsed[1].SourceID)
+		assert.Equal(t, "draft", parsed[1].Status)
+	})
+
+	t.Run("reports unreadable files in an archive", func(t *testing.T) {
+		var buf bytes.Buffer
+		archive := zip.NewWriter(&buf)
+		for name, content := range map[string]string{
+			"ok.md":         "---\ntitle: OK\n---\nbody",
+			"broken.md":     "no front matter",
+			"notes.txt":     "ignored",
+			"__MACOSX/x.md": "ignored",
+		} {
+			w, err := archive.Create(name)
+			require.NoError(t, err)
+			_, err = w.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, archive.Close())
+
+		documents, err := transfer.ParseMarkdownArchive(buf.Bytes())
+		require.NoError(t, err)
+		require.Len(t, documents, 2)
+		for _, document := range documents {
+			if document.SourceID == "broken.md" {
+				assert.NotEmpty(t, document.Error)
+			} else {
+				assert.Equal(t, "OK", document.Title)
+				assert.Empty(t, document.Error)
+			}
+		}
+	})
+
+	t.Run("fails with an invalid archive", func(t *testing.T) {
+		_, err := transfer.ParseMarkdownArchive([]byte("not a zip"))
+		assert.Error(t, err)
+	})
+}
+
+func TestWXRTransfer(t *testing.T) {
+	documents, err := transfer.ParseWXR(strings.NewReader(testWXR))
+	require.NoError(t, err)
+	require.Len(t, documents, 2)
+
+	t.Run("maps published posts", func(t *testing.T) {
+		document := documents[0]
+		assert.Equal(t, "https://blog.example.com/?p=1", document.SourceID)
+		assert.Equal(t, "Hello World", document.Title)
+		assert.Equal(t, "hello-world", document.Slug)
+		assert.Equal(t, "published", document.Status)
+		assert.Equal(t, "en-US", document.Locale)
+		assert.Equal(t, "alice", document.Author)
+		assert.Equal(t, "alice@example.com", document.AuthorEmail)
+		assert.Equal(t, []string{"Go"}, document.Tags)
+		assert.Equal(t, time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), document.Date)
+	})
+
+	t.Run("converts HTML to Markdown", func(t *testing.T) {
+		content := documents[0].Content
+		assert.Contains(t, content, "Some **bold** text.")
+		assert.Contains(t, content, "- one\n- two")
+		assert.NotContains(t, content, "<")
+	})
+
+	t.Run("maps drafts without a guid or date", func(t *testing.T) {
+		document := documents[1]
+		assert.Equal(t, "post-2", document.SourceID)
+		assert.Equal(t, "draft", document.Status)
+		assert.True(t, document.Date.IsZero())
+	})
+
+	t.Run("fails with invalid XML", func(t *testing.T) {
+		_, err := transfer.ParseWXR(strings.NewReader("<rss><channel>"))
+		assert.Error(t, err)
+	})
+}
+
+func TestTransfer(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	transferHandler := handlers.NewTransferHandler(nil, i18n.Locales{}) // 以下用例均在访问数据库之前返回
+	router.POST("/posts/import", transferHandler.Import)
+	router.GET("/users/me/posts/export", transferHandler.Export)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("import fails with unknown format", func(t *testing.T) {
+		w := client.PostForm("/posts/import?format=ghost", map[string]string{})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid format, must be markdown or wxr", response["error"])
+	})
+
+	t.Run("import fails with invalid dry_run", func(t *testing.T) {
+		w := client.PostForm("/posts/import?dry_run=maybe", map[string]string{})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("import requires authentication", func(t *testing.T) {
+		w := client.PostForm("/posts/import?format=wxr&dry_run=true", map[string]string{})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("export requires authentication", func(t *testing.T) {
+		w := client.Get("/users/me/posts/export")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
\ No newline at end of file
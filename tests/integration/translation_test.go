@@ -0,0 +1,153 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/internal/i18n"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocales(t *testing.T) {
+	locales := i18n.New("zh", []string{"en", "ZH", "fr_CA"})
+
+	t.Run("normalizes tags and supports the default", func(t *testing.T) {
+		assert.Equal(t, []string{"en", "zh", "fr-ca"}, locales.Supported)
+		assert.True(t, locales.Supports("EN"))
+		assert.True(t, locales.Supports("fr_ca"))
+		assert.False(t, locales.Supports("de"))
+
+		assert.Equal(t, []string{"zh", "en"}, i18n.New("zh", []string{"en"}).Supported)
+	})
+
+	t.Run("orders lang, Accept-Language by quality, then the default", func(t *testing.T) {
+		preferences := locales.Preferences("fr-CA", "en;q=0.5, de, zh;q=0.8")
+		assert.Equal(t, []string{"fr-ca", "zh", "en"}, preferences)
+	})
+
+	t.Run("matches regional tags to their language", func(t *testing.T) {
+		assert.Equal(t, []string{"en", "zh"}, locales.Preferences("", "en-US,*;q=0.1"))
+	})
+
+	t.Run("skips tags with zero quality", func(t *testing.T) {
+		assert.Equal(t, []string{"zh"}, locales.Preferences("", "en;q=0"))
+	})
+
+	t.Run("matches the first available preference", func(t *testing.T) {
+		assert.Equal(t, "en", i18n.Match([]string{"fr-ca", "en", "zh"}, []string{"zh", "en"}))
+		assert.Equal(t, "", i18n.Match([]string{"fr-ca"}, []string{"zh"}))
+	})
+}
+
+func TestTranslations(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	locales := i18n.New("zh", []string{"zh", "en"})
+
+	// 创建测试路由
+	router := gin.New()
+	translationHandler := handlers.NewTranslationHandler(nil, locales) // 以下用例均在访问数据库之前返回
+	postHandler := handlers.NewPostHandler(nil, nil, locales, nil)
+	router.GET("/posts", postHandler.List)
+	router.GET("/posts/:id", postHandler.Get)
+	router.GET("/posts/:id/translations", translationHandler.List)
+	router.GET("/posts/:id/translations/:locale", translationHandler.Get)
+	router.PUT("/posts/:id/translations/:locale", translationHandler.Put)
+	router.DELETE("/posts/:id/translations/:locale", translationHandler.Delete)
+	router.GET("/posts/translations/missing", translationHandler.Missing)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("post fails with unsupported lang", func(t *testing.T) {
+		w := client.Get("/posts/1?lang=de")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid lang, must be one of zh, en", response["error"])
+	})
+
+	t.Run("post list fails with unsupported lang", func(t *testing.T) {
+		w := client.Get("/posts?lang=de")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("list fails with invalid post ID", func(t *testing.T) {
+		w := client.Get("/posts/abc/translations")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid post ID", response["error"])
+	})
+
+	t.Run("get fails with unsupported locale", func(t *testing.T) {
+		w := client.Get("/posts/1/translations/de")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid locale, must be one of zh, en", response["error"])
+	})
+
+	t.Run("put fails without title", func(t *testing.T) {
+		w := client.Put("/posts/1/translations/en", map[string]interface{}{
+			"content": "Hello",
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("put fails with invalid content format", func(t *testing.T) {
+		w := client.Put("/posts/1/translations/en", map[string]interface{}{
+			"title":          "Hello",
+			"content":        "Hello",
+			"content_format": "html",
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("put requires authentication", func(t *testing.T) {
+		w := client.Put("/posts/1/translations/en", map[string]interface{}{
+			"title":   "Hello",
+			"content": "Hello",
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("delete requires authentication", func(t *testing.T) {
+		w := client.Delete("/posts/1/translations/EN")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("missing requires a supported locale", func(t *testing.T) {
+		w := client.Get("/posts/translations/missing")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid locale, must be one of zh, en", response["error"])
+	})
+}
\ No newline at end of file
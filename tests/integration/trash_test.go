@@ -0,0 +1,77 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/internal/i18n"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrash(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	postHandler := handlers.NewPostHandler(nil, nil, i18n.Locales{}, nil) // 以下用例均在访问数据库之前返回
+	userHandler := handlers.NewUserHandler(nil, nil)
+	router.GET("/posts/trash", postHandler.Trash)
+	router.POST("/posts/:id/restore", postHandler.Restore)
+	router.GET("/users/trash", userHandler.Trash)
+	router.POST("/users/:id/restore", userHandler.Restore)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("restore post fails with invalid ID", func(t *testing.T) {
+		w := client.Post("/posts/abc/restore", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid post ID", response["error"])
+	})
+
+	t.Run("restore user fails with invalid ID", func(t *testing.T) {
+		w := client.Post("/users/abc/restore", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid user ID", response["error"])
+	})
+
+	for _, path := range []string{"/posts/trash", "/users/trash"} {
+		t.Run("list requires authentication "+path, func(t *testing.T) {
+			w := client.Get(path)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+			var response map[string]interface{}
+			err := helpers.ParseJSON(w, &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "User not authenticated", response["error"])
+		})
+	}
+
+	for _, path := range []string{"/posts/1/restore", "/users/1/restore"} {
+		t.Run("restore requires authentication "+path, func(t *testing.T) {
+			w := client.Post(path, nil)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+			var response map[string]interface{}
+			err := helpers.ParseJSON(w, &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "User not authenticated", response["error"])
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,186 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/internal/middleware"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserHandler(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	userHandler := handlers.NewUserHandler(nil, nil) // 以下用例均在访问数据库之前返回
+	router.GET("/users/:id", userHandler.Get)
+	router.GET("/users/:id/profile", userHandler.Profile)
+	router.PUT("/users/:id", userHandler.Update)
+	router.PATCH("/users/:id", userHandler.Patch)
+	router.DELETE("/users/:id", userHandler.Delete)
+
+	// 需要认证的路由，当前中间件固定设置userID为1
+	protected := router.Group("/api")
+	protected.Use(middleware.Auth())
+	protected.PUT("/users/:id", userHandler.Update)
+	protected.PATCH("/users/:id", userHandler.Patch)
+	protected.DELETE("/users/:id", userHandler.Delete)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("get fails with invalid user ID", func(t *testing.T) {
+		w := client.Get("/users/abc")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid user ID", response["error"])
+	})
+
+	t.Run("profile fails with invalid user ID", func(t *testing.T) {
+		w := client.Get("/users/abc/profile")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid user ID", response["error"])
+	})
+
+	t.Run("update fails with invalid website", func(t *testing.T) {
+		w := client.Put("/users/1", map[string]interface{}{
+			"email":    "jane@example.com",
+			"username": "jane",
+			"website":  "not a url",
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Contains(t, response["error"], "Website")
+	})
+
+	t.Run("update fails with invalid email", func(t *testing.T) {
+		w := client.Put("/users/1", map[string]interface{}{
+			"email": "not-an-email",
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Contains(t, response, "error")
+	})
+
+	t.Run("update requires authentication", func(t *testing.T) {
+		w := client.Put("/users/1", map[string]interface{}{
+			"email":     "jane@example.com",
+			"username":  "jane",
+			"full_name": "Jane Doe",
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("update of another account is forbidden", func(t *testing.T) {
+		client.SetAuth("valid_token")
+		defer client.SetAuth("")
+
+		w := client.Put("/api/users/2", map[string]interface{}{
+			"email":     "jane@example.com",
+			"username":  "jane",
+			"full_name": "Jane Doe",
+		})
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "You can only update your own account", response["error"])
+	})
+
+	t.Run("update fails with missing fields", func(t *testing.T) {
+		w := client.Put("/users/1", map[string]interface{}{
+			"full_name": "Jane Doe",
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Contains(t, response, "error")
+	})
+
+	t.Run("patch requires authentication", func(t *testing.T) {
+		w := client.Patch("/users/1", map[string]interface{}{
+			"full_name": nil,
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("patch of another account is forbidden", func(t *testing.T) {
+		client.SetAuth("valid_token")
+		defer client.SetAuth("")
+
+		w := client.Patch("/api/users/2", map[string]interface{}{
+			"full_name": nil,
+		})
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "You can only update your own account", response["error"])
+	})
+
+	t.Run("delete requires authentication", func(t *testing.T) {
+		w := client.Delete("/users/1")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+
+	t.Run("delete of another account is forbidden", func(t *testing.T) {
+		client.SetAuth("valid_token")
+		defer client.SetAuth("")
+
+		w := client.Delete("/api/users/2")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "You can only delete your own account", response["error"])
+	})
+}
\ No newline at end of file
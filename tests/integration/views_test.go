@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/internal/i18n"
+	"github.com/demo/demo-gin/internal/views"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewCounter(t *testing.T) {
+	t.Run("counts a visitor once per window", func(t *testing.T) {
+		counter := views.NewCounter(nil, time.Hour, time.Minute)
+
+		assert.True(t, counter.Record(1, "user:1"))
+		assert.False(t, counter.Record(1, "user:1"))
+		assert.True(t, counter.Record(1, "user:2"))
+		assert.True(t, counter.Record(2, "user:1"))
+		assert.Equal(t, 3, counter.Pending())
+	})
+
+	t.Run("counts a visitor again after the window", func(t *testing.T) {
+		counter := views.NewCounter(nil, 10*time.Millisecond, time.Minute)
+
+		assert.True(t, counter.Record(1, "anon:127.0.0.1|curl"))
+		time.Sleep(20 * time.Millisecond)
+		assert.True(t, counter.Record(1, "anon:127.0.0.1|curl"))
+		assert.Equal(t, 2, counter.Pending())
+	})
+}
+
+func TestPostStats(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	postHandler := handlers.NewPostHandler(nil, nil, i18n.Locales{}, nil) // 以下用例均在访问数据库之前返回
+	router.GET("/posts/:id/stats", postHandler.Stats)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("stats fail with invalid ID", func(t *testing.T) {
+		w := client.Get("/posts/abc/stats")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid post ID", response["error"])
+	})
+
+	for _, days := range []string{"0", "91", "abc"} {
+		t.Run("stats reject days "+days, func(t *testing.T) {
+			w := client.Get("/posts/1/stats?days=" + days)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response map[string]interface{}
+			err := helpers.ParseJSON(w, &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "Invalid days, must be between 1 and 90", response["error"])
+		})
+	}
+
+	t.Run("stats require authentication", func(t *testing.T) {
+		w := client.Get("/posts/1/stats")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User not authenticated", response["error"])
+	})
+}
\ No newline at end of file
@@ -0,0 +1,230 @@
+package integration
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	db "github.com/demo/demo-gin/internal/db/sqlc"
+	"github.com/demo/demo-gin/internal/events"
+	"github.com/demo/demo-gin/internal/handlers"
+	"github.com/demo/demo-gin/internal/webhooks"
+	"github.com/demo/demo-gin/tests/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSigning(t *testing.T) {
+	t.Run("signs the timestamp and body", func(t *testing.T) {
+		at := time.Unix(1700000000, 0)
+		body := []byte(`{"id":"evt_1","type":"post.published"}`)
+
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte("1700000000." + string(body)))
+		expected := "t=1700000000,v1=" + hex.EncodeToString(mac.Sum(nil))
+
+		assert.Equal(t, expected, webhooks.Sign("secret", at, body))
+		assert.NotEqual(t, expected, webhooks.Sign("other", at, body))
+		assert.NotEqual(t, expected, webhooks.Sign("secret", at.Add(time.Second), body))
+	})
+
+	t.Run("generates distinct secrets", func(t *testing.T) {
+		a, err := webhooks.NewSecret()
+		require.NoError(t, err)
+		b, err := webhooks.NewSecret()
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(a, "whsec_"))
+		assert.NotEqual(t, a, b)
+	})
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, webhooks.Backoff(1))
+	assert.Equal(t, time.Minute, webhooks.Backoff(2))
+	assert.Equal(t, 2*time.Minute, webhooks.Backoff(3))
+	assert.Equal(t, 6*time.Hour, webhooks.Backoff(20))
+	assert.Equal(t, 6*time.Hour, webhooks.Backoff(1000))
+}
+
+func TestWebhookRequest(t *testing.T) {
+	webhook := db.Webhook{ID: 7, Secret: "secret"}
+	delivery := db.WebhookDelivery{ID: 42, EventType: events.PostPublished, Payload: []byte(`{"id":"evt_1"}`)}
+	at := time.Unix(1700000000, 0)
+
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	webhook.Url = server.URL + "/hooks"
+
+	req, err := webhooks.NewRequest(context.Background(), webhook, delivery, at)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.NotNil(t, received)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, "/hooks", received.URL.Path)
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(t, "7", received.Header.Get(webhooks.HeaderWebhook))
+	assert.Equal(t, "post.published", received.Header.Get(webhooks.HeaderEvent))
+	assert.Equal(t, "42", received.Header.Get(webhooks.HeaderDelivery))
+	assert.Equal(t, webhooks.Sign("secret", at, delivery.Payload), received.Header.Get(webhooks.HeaderSignature))
+	assert.Equal(t, string(delivery.Payload), string(body))
+}
+
+func TestWebhookEnqueuer(t *testing.T) {
+	// 以下事件均在访问数据库之前被忽略
+	enqueuer := webhooks.NewEnqueuer(nil)
+
+	t.Run("ignores unsupported events", func(t *testing.T) {
+		assert.NoError(t, enqueuer.Handle(context.Background(), events.Event{Type: events.PostLiked}))
+	})
+
+	t.Run("ignores changes to drafts", func(t *testing.T) {
+		assert.NoError(t, enqueuer.Handle(context.Background(), events.Event{Type: events.PostDeleted, Public: false}))
+	})
+}
+
+func TestWebhookHandler(t *testing.T) {
+	// 设置Gin为测试模式
+	gin.SetMode(gin.TestMode)
+
+	// 创建测试路由
+	router := gin.New()
+	webhookHandler := handlers.NewWebhookHandler(nil) // 以下用例均在访问数据库之前返回
+	router.POST("/webhooks", webhookHandler.Create)
+	router.GET("/webhooks", webhookHandler.List)
+	router.GET("/webhooks/:id", webhookHandler.Get)
+	router.PUT("/webhooks/:id", webhookHandler.Update)
+	router.DELETE("/webhooks/:id", webhookHandler.Delete)
+	router.GET("/webhooks/:id/deliveries", webhookHandler.Deliveries)
+	router.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver)
+
+	// 创建测试客户端
+	client := helpers.NewTestClient(router)
+
+	t.Run("create fails without a URL", func(t *testing.T) {
+		w := client.Post("/webhooks", map[string]interface{}{
+			"event_types": []string{"post.published"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("create fails with a non-http URL", func(t *testing.T) {
+		w := client.Post("/webhooks", map[string]interface{}{
+			"url":         "ftp://example.com/hooks",
+			"event_types": []string{"post.published"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "URL must be an http or https URL", response["error"])
+	})
+
+	t.Run("create fails with an unknown event type", func(t *testing.T) {
+		w := client.Post("/webhooks", map[string]interface{}{
+			"url":         "https://example.com/hooks",
+			"event_types": []string{"post.published", "post.liked"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Contains(t, response["error"], `Unknown event type "post.liked"`)
+	})
+
+	t.Run("create fails with a short secret", func(t *testing.T) {
+		w := client.Post("/webhooks", map[string]interface{}{
+			"url":         "https://example.com/hooks",
+			"event_types": []string{"post.published"},
+			"secret":      "short",
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("create fails without authentication", func(t *testing.T) {
+		w := client.Post("/webhooks", map[string]interface{}{
+			"url":         "https://example.com/hooks",
+			"event_types": []string{"post.published", "user.updated"},
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("list fails without authentication", func(t *testing.T) {
+		w := client.Get("/webhooks")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("get fails with invalid webhook ID", func(t *testing.T) {
+		w := client.Get("/webhooks/abc")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid webhook ID", response["error"])
+	})
+
+	t.Run("update fails without active", func(t *testing.T) {
+		w := client.Put("/webhooks/1", map[string]interface{}{
+			"url":         "https://example.com/hooks",
+			"event_types": []string{"post.published"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("delete fails without authentication", func(t *testing.T) {
+		w := client.Delete("/webhooks/1")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("deliveries fail with invalid webhook ID", func(t *testing.T) {
+		w := client.Get("/webhooks/abc/deliveries")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("redeliver fails with invalid delivery ID", func(t *testing.T) {
+		w := client.Post("/webhooks/1/deliveries/abc/redeliver", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		err := helpers.ParseJSON(w, &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid delivery ID", response["error"])
+	})
+
+	t.Run("redeliver fails without authentication", func(t *testing.T) {
+		w := client.Post("/webhooks/1/deliveries/2/redeliver", nil)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
\ No newline at end of file